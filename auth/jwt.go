@@ -34,19 +34,20 @@ func init() {
 		IsSlice:          false,
 		AuthorizedValues: []any{},
 	})
-	registerKeyConfigEntry("auth.jwt.secret")
-	registerKeyConfigEntry("auth.jwt.rsa.public")
-	registerKeyConfigEntry("auth.jwt.rsa.private")
-	registerKeyConfigEntry("auth.jwt.ecdsa.public")
-	registerKeyConfigEntry("auth.jwt.ecdsa.private")
+	registerKeyConfigEntry("auth.jwt.secret", true)
+	registerKeyConfigEntry("auth.jwt.rsa.public", false)
+	registerKeyConfigEntry("auth.jwt.rsa.private", false)
+	registerKeyConfigEntry("auth.jwt.ecdsa.public", false)
+	registerKeyConfigEntry("auth.jwt.ecdsa.private", false)
 }
 
-func registerKeyConfigEntry(name string) {
+func registerKeyConfigEntry(name string, sensitive bool) {
 	config.Register(name, config.Entry{
 		Value:            nil,
 		Type:             reflect.String,
 		IsSlice:          false,
 		AuthorizedValues: []any{},
+		Sensitive:        sensitive,
 	})
 }
 
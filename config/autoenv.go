@@ -0,0 +1,63 @@
+package config
+
+import (
+	"strings"
+	"unicode"
+)
+
+// EnvVarName returns the name of the environment variable overriding the entry
+// identified by the given key when automatic env overrides are enabled (`LoadOptions.AutoEnv`).
+// The key is converted to upper snake case: dots and all non-alphanumeric characters
+// are replaced with underscores, and camel case words are separated. If the prefix
+// is not empty, it is prepended and separated with an underscore.
+//
+//	EnvVarName("APP", "server.port") // "APP_SERVER_PORT"
+//	EnvVarName("APP", "server.maxUploadSize") // "APP_SERVER_MAX_UPLOAD_SIZE"
+//	EnvVarName("", "auth.jwt.secret") // "AUTH_JWT_SECRET"
+func EnvVarName(prefix, key string) string {
+	builder := strings.Builder{}
+	if prefix != "" {
+		builder.WriteString(prefix)
+		builder.WriteByte('_')
+	}
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1]))) {
+				builder.WriteByte('_')
+			}
+			builder.WriteRune(r)
+		case unicode.IsLower(r) || unicode.IsDigit(r):
+			builder.WriteRune(unicode.ToUpper(r))
+		default:
+			builder.WriteByte('_')
+		}
+	}
+	return builder.String()
+}
+
+// autoEnvLayer overrides each entry of the configuration loaded so far with
+// the environment variable whose name is derived from the entry's key, if it is set.
+func autoEnvLayer(prefix string) layer {
+	return func(config object) ([]sourceValues, error) {
+		vars := map[string]string{}
+		collectEnvVarNames(config, "", prefix, vars)
+		return envVarsLayer(vars)(config)
+	}
+}
+
+func collectEnvVarNames(o object, path, prefix string, vars map[string]string) {
+	for k, v := range o {
+		key := k
+		if path != "" {
+			key = path + "." + k
+		}
+		if category, ok := v.(object); ok {
+			collectEnvVarNames(category, key, prefix, vars)
+			continue
+		}
+		vars[key] = EnvVarName(prefix, key)
+	}
+}
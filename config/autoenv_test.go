@@ -0,0 +1,129 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvVarName(t *testing.T) {
+	cases := []struct {
+		prefix string
+		key    string
+		want   string
+	}{
+		{prefix: "APP", key: "server.port", want: "APP_SERVER_PORT"},
+		{prefix: "APP", key: "server.maxUploadSize", want: "APP_SERVER_MAX_UPLOAD_SIZE"},
+		{prefix: "", key: "auth.jwt.secret", want: "AUTH_JWT_SECRET"},
+		{prefix: "APP", key: "server.assetsURL", want: "APP_SERVER_ASSETS_URL"},
+		{prefix: "APP", key: "server.URLPrefix", want: "APP_SERVER_URL_PREFIX"},
+		{prefix: "APP", key: "custom-entry.v2Value", want: "APP_CUSTOM_ENTRY_V2_VALUE"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, EnvVarName(c.prefix, c.key))
+	}
+}
+
+func TestEnvSliceConversion(t *testing.T) {
+	cases := []struct {
+		want    any
+		value   string
+		kind    reflect.Kind
+		wantErr bool
+	}{
+		{value: "a, b ,c", kind: reflect.String, want: []string{"a", "b", "c"}},
+		{value: "1,2,3", kind: reflect.Int, want: []int{1, 2, 3}},
+		{value: "1.5,2", kind: reflect.Float64, want: []float64{1.5, 2}},
+		{value: "true,false", kind: reflect.Bool, want: []bool{true, false}},
+		{value: "", kind: reflect.String, want: []string{}},
+		{value: `["a,b","c"]`, kind: reflect.String, want: []string{"a,b", "c"}},
+		{value: ` [1, 2]`, kind: reflect.Int, want: []int{1, 2}},
+		{value: "1,a", kind: reflect.Int, wantErr: true},
+		{value: `[1, "a"]`, kind: reflect.Int, wantErr: true},
+		{value: `["unclosed"`, kind: reflect.String, wantErr: true},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.value, func(t *testing.T) {
+			t.Setenv("TEST_ENV_SLICE", c.value)
+			entry := &Entry{Value: "${TEST_ENV_SLICE}", AuthorizedValues: []any{}, Type: c.kind, IsSlice: true}
+			err := entry.validate("slice")
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.want, entry.Value)
+		})
+	}
+}
+
+func TestAutoEnv(t *testing.T) {
+	filesystem := fstest.MapFS{
+		"config.json": &fstest.MapFile{Data: []byte(`{"custom": {"tags": ["a"], "maxItems": 3}}`)},
+	}
+
+	t.Run("overrides", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "")
+		t.Setenv("APP_SERVER_PORT", "9000")
+		t.Setenv("APP_SERVER_MAX_UPLOAD_SIZE", "2.5")
+		t.Setenv("APP_APP_DEBUG", "false")
+		t.Setenv("APP_APP_PREVIOUS_KEYS", "key1,key2")
+		t.Setenv("APP_SERVER_PROXY_HOST", "proxy.example.org")
+		t.Setenv("APP_CUSTOM_TAGS", `["b","c"]`)
+		t.Setenv("APP_CUSTOM_MAX_ITEMS", "10")
+		t.Setenv("APP_DATABASE_HOST", "auto")
+		t.Setenv("DB_HOST", "explicit")
+		t.Setenv("SERVER_HOST", "no-prefix")
+
+		cfg, err := LoadLayered(LoadOptions{
+			FS:        filesystem,
+			AutoEnv:   true,
+			EnvPrefix: "APP",
+			EnvVars:   map[string]string{"database.host": "DB_HOST"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 9000, cfg.Get("server.port"))
+		assert.InEpsilon(t, 2.5, cfg.Get("server.maxUploadSize"), 0)
+		assert.Equal(t, false, cfg.Get("app.debug"))
+		assert.Equal(t, []string{"key1", "key2"}, cfg.Get("app.previousKeys"))
+		assert.Equal(t, "proxy.example.org", cfg.Get("server.proxy.host"))
+		assert.Equal(t, []any{"b", "c"}, cfg.Get("custom.tags")) // Unregistered, same as JSON
+		assert.InEpsilon(t, 10.0, cfg.Get("custom.maxItems"), 0) // Unregistered, float like JSON
+		assert.Equal(t, "explicit", cfg.Get("database.host"))
+		assert.Equal(t, "127.0.0.1", cfg.Get("server.host"))
+
+		source, _ := cfg.Source("server.port")
+		assert.Equal(t, Source{Kind: SourceEnv, Name: "APP_SERVER_PORT"}, source)
+		source, _ = cfg.Source("database.host")
+		assert.Equal(t, Source{Kind: SourceEnv, Name: "DB_HOST"}, source)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "")
+		t.Setenv("APP_SERVER_PORT", "9000")
+		cfg, err := LoadLayered(LoadOptions{FS: filesystem, EnvPrefix: "APP"})
+		require.NoError(t, err)
+		assert.Equal(t, 8080, cfg.Get("server.port"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "")
+		t.Setenv("APP_SERVER_PORT", "abc")
+		t.Setenv("APP_APP_DEBUG", "maybe")
+		cfg, err := LoadLayered(LoadOptions{FS: filesystem, AutoEnv: true, EnvPrefix: "APP"})
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+
+		var configErr *Error
+		require.ErrorAs(t, err, &configErr)
+		assert.Contains(t, err.Error(), `"server.port" could not be converted to int from environment variable "APP_SERVER_PORT" of value "abc"`)
+		assert.Contains(t, err.Error(), `"app.debug" could not be converted to bool from environment variable "APP_APP_DEBUG" of value "maybe"`)
+	})
+}
@@ -1,13 +1,13 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
 	"io/fs"
 	"os"
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"goyave.dev/goyave/v5/util/errors"
 	"goyave.dev/goyave/v5/util/fsutil/osfs"
@@ -15,16 +15,40 @@ import (
 
 type object map[string]any
 
-type readFunc func(string) (object, error)
-
 // Config structure holding a configuration that should be used for a single
 // instance of `goyave.Server`.
 //
 // This structure is not protected for safe concurrent access in order to increase
 // performance. Therefore, you should never use the `Set()` function when the configuration
-// is in use by an already running server.
+// is in use by an already running server. However, the configuration can safely be
+// reloaded while in use, see `Config.Watch()`.
 type Config struct {
-	config object
+	state atomic.Pointer[configState]
+
+	// reload loads a new configuration from the same sources.
+	reload   func() (*Config, error)
+	reloadMu sync.Mutex
+
+	subscribers   []*subscriber
+	subscribersMu sync.RWMutex
+}
+
+// configState the loaded configuration. It is replaced as a whole
+// when the configuration is reloaded.
+type configState struct {
+	config  object
+	sources map[string]Source
+	files   []configFile
+}
+
+// configFile a file the configuration was loaded from.
+type configFile struct {
+	fs   fs.FS
+	path string
+}
+
+func (c *Config) current() *configState {
+	return c.state.Load()
 }
 
 // Error returned when the configuration could not
@@ -87,27 +111,42 @@ func (l *loader) register(key string, entry Entry) {
 }
 
 func (l *loader) loadFrom(fs fs.FS, path string) (*Config, error) {
-	return l.load(func(_ string) (object, error) { return l.readConfigFile(fs, path) }, path)
+	return l.load(dotenvLayer(fs), l.fileLayer(fs, path))
 }
 
 func (l *loader) loadJSON(cfg string) (*Config, error) {
-	return l.load(l.readString, cfg)
+	return l.load(l.stringLayer(decodeJSON, cfg))
 }
 
-func (l *loader) load(readFunc readFunc, source string) (*Config, error) {
+func (l *loader) loadYAML(cfg string) (*Config, error) {
+	return l.load(l.stringLayer(decodeYAML, cfg))
+}
+
+// load the defaults, then applies the given layers in order, each layer
+// overriding the values of the previous ones. The resulting configuration
+// is validated once all layers are applied.
+func (l *loader) load(layers ...layer) (*Config, error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	config := make(object, len(l.defaults))
 	loadDefaults(l.defaults, config)
+	sources := map[string]Source{}
+	recordSources(config, "", Source{Kind: SourceDefault}, sources)
+	files := []configFile{}
 
-	if readFunc != nil {
-		conf, err := readFunc(source)
+	for _, layer := range layers {
+		values, err := layer(config)
 		if err != nil {
 			return nil, errors.New(&Error{err})
 		}
-
-		if err := override(conf, config); err != nil {
-			return nil, errors.New(&Error{err})
+		for _, v := range values {
+			if err := override(v.values, config); err != nil {
+				return nil, errors.New(&Error{err})
+			}
+			recordSources(v.values, "", v.source, sources)
+			if v.fs != nil {
+				files = append(files, configFile{fs: v.fs, path: v.source.Name})
+			}
 		}
 	}
 
@@ -115,9 +154,15 @@ func (l *loader) load(readFunc readFunc, source string) (*Config, error) {
 		return nil, errors.New(&Error{err})
 	}
 
-	return &Config{
-		config: config,
-	}, nil
+	cfg := &Config{
+		reload: func() (*Config, error) { return l.load(layers...) },
+	}
+	cfg.state.Store(&configState{
+		config:  config,
+		sources: sources,
+		files:   files,
+	})
+	return cfg, nil
 }
 
 // Load loads the config.json file in the current working directory.
@@ -125,17 +170,28 @@ func (l *loader) load(readFunc readFunc, source string) (*Config, error) {
 //   - "production": "config.production.json"
 //   - "test": "config.test.json"
 //   - By default: "config.json"
+//
+// If the JSON file doesn't exist, a YAML file with the same name and the ".yml"
+// or ".yaml" extension is used instead.
+//
+// The ".env" files in the current working directory are loaded before the
+// configuration so their variables can be used in "${VAR}" entries.
+// See `LoadEnvFile` for more details.
 func Load() (*Config, error) {
-	return defaultLoader.loadFrom(&osfs.FS{}, getConfigFilePath())
+	filesystem := &osfs.FS{}
+	return defaultLoader.loadFrom(filesystem, findConfigFile(filesystem, getConfigFilePath()))
 }
 
 // LoadDefault loads default config.
 func LoadDefault() *Config {
-	cfg, _ := defaultLoader.load(nil, "")
+	cfg, _ := defaultLoader.load()
 	return cfg
 }
 
-// LoadFrom loads a config file from the given path.
+// LoadFrom loads a config file from the given path. Files with the ".yml" or ".yaml"
+// extension are decoded as YAML, all other files are decoded as JSON.
+//
+// Like `Load`, the ".env" files in the current working directory are loaded first.
 func LoadFrom(path string) (*Config, error) {
 	return defaultLoader.loadFrom(&osfs.FS{}, path)
 }
@@ -167,14 +223,51 @@ func LoadJSON(cfg string) (*Config, error) {
 	return defaultLoader.loadJSON(cfg)
 }
 
+// LoadYAML load a configuration file from raw YAML. Can be used in combination with
+// Go's embed directive, like `LoadJSON`.
+func LoadYAML(cfg string) (*Config, error) {
+	return defaultLoader.loadYAML(cfg)
+}
+
 func getConfigFilePath() string {
-	env := strings.ToLower(os.Getenv("GOYAVE_ENV"))
-	if env == "local" || env == "localhost" || env == "" {
+	env := getEnvironment()
+	if env == "" {
 		return "config.json"
 	}
 	return "config." + env + ".json"
 }
 
+// getEnvironment returns the lowercased value of the "GOYAVE_ENV" env variable,
+// or an empty string if it is not set or is a local environment.
+func getEnvironment() string {
+	env := strings.ToLower(os.Getenv("GOYAVE_ENV"))
+	if env == "local" || env == "localhost" {
+		return ""
+	}
+	return env
+}
+
+// findConfigFile returns the given JSON config file path if it exists. Otherwise,
+// returns the first existing YAML alternative ("config.yml" or "config.yaml" for
+// "config.json"). If none exist, the original path is returned.
+func findConfigFile(filesystem fs.FS, file string) string {
+	if !strings.HasSuffix(file, ".json") || fileExists(filesystem, file) {
+		return file
+	}
+	base := strings.TrimSuffix(file, ".json")
+	for _, ext := range []string{".yml", ".yaml"} {
+		if fileExists(filesystem, base+ext) {
+			return base + ext
+		}
+	}
+	return file
+}
+
+func fileExists(filesystem fs.FS, file string) bool {
+	info, err := fs.Stat(filesystem, file)
+	return err == nil && !info.IsDir()
+}
+
 func (l *loader) readConfigFile(filesystem fs.FS, file string) (o object, err error) {
 	var configFile fs.File
 	o = make(object, len(l.defaults))
@@ -187,8 +280,7 @@ func (l *loader) readConfigFile(filesystem fs.FS, file string) (o object, err er
 				err = errors.New(e)
 			}
 		}()
-		jsonParser := json.NewDecoder(configFile)
-		err = errors.New(jsonParser.Decode(&o))
+		err = errors.New(getDecoder(file)(configFile, &o))
 	} else {
 		err = errors.New(err)
 	}
@@ -196,9 +288,9 @@ func (l *loader) readConfigFile(filesystem fs.FS, file string) (o object, err er
 	return
 }
 
-func (l *loader) readString(str string) (object, error) {
+func (l *loader) readString(decode decodeFunc, str string) (object, error) {
 	conf := make(object, len(l.defaults))
-	if err := json.NewDecoder(strings.NewReader(str)).Decode(&conf); err != nil {
+	if err := decode(strings.NewReader(str), &conf); err != nil {
 		return nil, err
 	}
 	return conf, nil
@@ -357,7 +449,7 @@ func (c *Config) Get(key string) any {
 }
 
 func (c *Config) get(key string) (any, bool) {
-	currentCategory := c.config
+	currentCategory := c.current().config
 	start := 0
 	dotIndex := strings.Index(key, ".")
 	if dotIndex == -1 {
@@ -469,6 +561,25 @@ func (c *Config) GetFloatSlice(key string) []float64 {
 	return str
 }
 
+// Map returns a copy of the whole configuration as nested maps.
+// Categories are represented by a `map[string]any` and entries by their value.
+// Unset entries have a `nil` value.
+func (c *Config) Map() map[string]any {
+	return c.current().config.toMap()
+}
+
+func (o object) toMap() map[string]any {
+	m := make(map[string]any, len(o))
+	for k, v := range o {
+		if category, ok := v.(object); ok {
+			m[k] = category.toMap()
+			continue
+		}
+		m[k] = v.(*Entry).Value
+	}
+	return m
+}
+
 // Has check if a config entry exists.
 func (c *Config) Has(key string) bool {
 	_, ok := c.get(key)
@@ -491,7 +602,8 @@ func (c *Config) Has(key string) bool {
 // This operation is not concurrently safe and should not be used when the configuration
 // is in use by an already running server.
 func (c *Config) Set(key string, value any) {
-	category, entryKey, exists := walk(c.config, key)
+	state := c.current()
+	category, entryKey, exists := walk(state.config, key)
 	if exists {
 		entry := category[entryKey].(*Entry)
 		previous := entry.Value
@@ -504,4 +616,5 @@ func (c *Config) Set(key string, value any) {
 	} else {
 		category[entryKey] = makeEntryFromValue(value)
 	}
+	state.sources[key] = Source{Kind: SourceSet}
 }
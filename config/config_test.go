@@ -55,7 +55,7 @@ func TestLoad(t *testing.T) {
 			Type:             reflect.String,
 			IsSlice:          false,
 		}
-		assert.Equal(t, expected, cfg.config["rootLevel"])
+		assert.Equal(t, expected, cfg.current().config["rootLevel"])
 
 		// Default config also loaded
 		expected = &Entry{
@@ -64,7 +64,7 @@ func TestLoad(t *testing.T) {
 			Type:             reflect.String,
 			IsSlice:          false,
 		}
-		assert.Equal(t, expected, cfg.config["app"].(object)["name"])
+		assert.Equal(t, expected, cfg.current().config["app"].(object)["name"])
 	})
 
 	t.Run("Load Invalid", func(t *testing.T) {
@@ -76,7 +76,7 @@ func TestLoad(t *testing.T) {
 
 	t.Run("Load Default", func(t *testing.T) {
 		cfg := LoadDefault()
-		assert.Equal(t, defaultLoader.defaults, cfg.config)
+		assert.Equal(t, defaultLoader.defaults, cfg.current().config)
 	})
 
 	t.Run("Load Non Existing", func(t *testing.T) {
@@ -98,7 +98,7 @@ func TestLoad(t *testing.T) {
 			Type:             reflect.String,
 			IsSlice:          false,
 		}
-		assert.Equal(t, expected, cfg.config["custom-entry"])
+		assert.Equal(t, expected, cfg.current().config["custom-entry"])
 
 		// Default config also loaded
 		expected = &Entry{
@@ -107,7 +107,7 @@ func TestLoad(t *testing.T) {
 			Type:             reflect.String,
 			IsSlice:          false,
 		}
-		assert.Equal(t, expected, cfg.config["app"].(object)["name"])
+		assert.Equal(t, expected, cfg.current().config["app"].(object)["name"])
 	})
 
 	t.Run("LoadJSON", func(t *testing.T) {
@@ -122,7 +122,7 @@ func TestLoad(t *testing.T) {
 			Type:             reflect.String,
 			IsSlice:          false,
 		}
-		assert.Equal(t, expected, cfg.config["custom-entry"])
+		assert.Equal(t, expected, cfg.current().config["custom-entry"])
 
 		// Default config also loaded
 		expected = &Entry{
@@ -131,7 +131,7 @@ func TestLoad(t *testing.T) {
 			Type:             reflect.String,
 			IsSlice:          false,
 		}
-		assert.Equal(t, expected, cfg.config["app"].(object)["name"])
+		assert.Equal(t, expected, cfg.current().config["app"].(object)["name"])
 	})
 
 	t.Run("LoadJSON Invalid", func(t *testing.T) {
@@ -301,7 +301,7 @@ func TestLoad(t *testing.T) {
 
 		assert.NotNil(t, cfg)
 
-		cat, ok := cfg.config["category"].(object)
+		cat, ok := cfg.current().config["category"].(object)
 		if !assert.True(t, ok) {
 			return
 		}
@@ -351,7 +351,7 @@ func TestLoad(t *testing.T) {
 
 		assert.NotNil(t, cfg)
 
-		cat, ok = cfg.config["app"].(object)
+		cat, ok = cfg.current().config["app"].(object)
 		if !assert.True(t, ok) {
 			return
 		}
@@ -564,6 +564,21 @@ func TestConfig(t *testing.T) {
 		assert.False(t, cfg.Has("testCategory.nonexistent"))
 	})
 
+	t.Run("Map", func(t *testing.T) {
+		m := cfg.Map()
+		assert.Equal(t, "root", m["rootLevel"])
+		category, ok := m["testCategory"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "hello", category["string"])
+		assert.Equal(t, []string{"a", "b"}, category["stringSlice"])
+		assert.Equal(t, 456, category["set"])
+		assert.Equal(t, cfg.GetString("app.name"), m["app"].(map[string]any)["name"])
+
+		// Modifying the map doesn't affect the config
+		category["string"] = "modified"
+		assert.Equal(t, "hello", cfg.GetString("testCategory.string"))
+	})
+
 	t.Run("Set", func(t *testing.T) {
 		cfg.Set("testCategory.set", 789)
 		expected := &Entry{
@@ -572,7 +587,7 @@ func TestConfig(t *testing.T) {
 			Type:             reflect.Int,
 			IsSlice:          false,
 		}
-		assert.Equal(t, expected, cfg.config["testCategory"].(object)["set"])
+		assert.Equal(t, expected, cfg.current().config["testCategory"].(object)["set"])
 
 		cfg.Set("testCategory.set", 456.0) // Conversion float->int
 		expected = &Entry{
@@ -581,7 +596,7 @@ func TestConfig(t *testing.T) {
 			Type:             reflect.Int,
 			IsSlice:          false,
 		}
-		assert.Equal(t, expected, cfg.config["testCategory"].(object)["set"])
+		assert.Equal(t, expected, cfg.current().config["testCategory"].(object)["set"])
 
 		cfg.Set("testCategory.setSlice", []int{789, 456})
 		expected = &Entry{
@@ -590,7 +605,7 @@ func TestConfig(t *testing.T) {
 			Type:             reflect.Int,
 			IsSlice:          true,
 		}
-		assert.Equal(t, expected, cfg.config["testCategory"].(object)["setSlice"])
+		assert.Equal(t, expected, cfg.current().config["testCategory"].(object)["setSlice"])
 
 		// No need to validate the other conversions, they have been tested indirectly
 		// through the loading at the start of this test
@@ -621,7 +636,7 @@ func TestConfig(t *testing.T) {
 	t.Run("Set New Entry", func(t *testing.T) {
 		cfg.Set("testCategory.subcategory.deep.entry", "hello")
 
-		subcategory, ok := cfg.config["testCategory"].(object)["subcategory"].(object)["deep"].(object)
+		subcategory, ok := cfg.current().config["testCategory"].(object)["subcategory"].(object)["deep"].(object)
 		if !assert.True(t, ok) {
 			return
 		}
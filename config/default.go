@@ -4,49 +4,53 @@ import "reflect"
 
 var configDefaults = object{
 	"app": object{
-		"name":            &Entry{"goyave", []any{}, reflect.String, false},
-		"environment":     &Entry{"localhost", []any{}, reflect.String, false},
-		"debug":           &Entry{true, []any{}, reflect.Bool, false},
-		"defaultLanguage": &Entry{"en-US", []any{}, reflect.String, false},
+		"name":            &Entry{Value: "goyave", AuthorizedValues: []any{}, Type: reflect.String},
+		"environment":     &Entry{Value: "localhost", AuthorizedValues: []any{}, Type: reflect.String},
+		"debug":           &Entry{Value: true, AuthorizedValues: []any{}, Type: reflect.Bool},
+		"defaultLanguage": &Entry{Value: "en-US", AuthorizedValues: []any{}, Type: reflect.String},
+		"key":             &Entry{Value: "", AuthorizedValues: []any{}, Type: reflect.String, Sensitive: true},
+		"previousKeys":    &Entry{Value: []string{}, AuthorizedValues: []any{}, Type: reflect.String, IsSlice: true, Sensitive: true},
 	},
 	"server": object{
-		"host":                  &Entry{"127.0.0.1", []any{}, reflect.String, false},
-		"domain":                &Entry{"", []any{}, reflect.String, false},
-		"port":                  &Entry{8080, []any{}, reflect.Int, false},
-		"writeTimeout":          &Entry{10, []any{}, reflect.Int, false},
-		"readTimeout":           &Entry{10, []any{}, reflect.Int, false},
-		"readHeaderTimeout":     &Entry{10, []any{}, reflect.Int, false},
-		"idleTimeout":           &Entry{20, []any{}, reflect.Int, false},
-		"websocketCloseTimeout": &Entry{10, []any{}, reflect.Int, false},
-		"maxUploadSize":         &Entry{10.0, []any{}, reflect.Float64, false},
+		"host":                  &Entry{Value: "127.0.0.1", AuthorizedValues: []any{}, Type: reflect.String, NonReloadable: true},
+		"domain":                &Entry{Value: "", AuthorizedValues: []any{}, Type: reflect.String, NonReloadable: true},
+		"port":                  &Entry{Value: 8080, AuthorizedValues: []any{}, Type: reflect.Int, NonReloadable: true},
+		"writeTimeout":          &Entry{Value: 10, AuthorizedValues: []any{}, Type: reflect.Int, NonReloadable: true},
+		"readTimeout":           &Entry{Value: 10, AuthorizedValues: []any{}, Type: reflect.Int, NonReloadable: true},
+		"readHeaderTimeout":     &Entry{Value: 10, AuthorizedValues: []any{}, Type: reflect.Int, NonReloadable: true},
+		"idleTimeout":           &Entry{Value: 20, AuthorizedValues: []any{}, Type: reflect.Int, NonReloadable: true},
+		"websocketCloseTimeout": &Entry{Value: 10, AuthorizedValues: []any{}, Type: reflect.Int},
+		"maxUploadSize":         &Entry{Value: 10.0, AuthorizedValues: []any{}, Type: reflect.Float64},
+		"strictRouting":         &Entry{Value: false, AuthorizedValues: []any{}, Type: reflect.Bool},
+		"assetsURL":             &Entry{Value: "", AuthorizedValues: []any{}, Type: reflect.String},
 		"proxy": object{
-			"protocol": &Entry{"http", []any{"http", "https"}, reflect.String, false},
-			"host":     &Entry{nil, []any{}, reflect.String, false},
-			"port":     &Entry{80, []any{}, reflect.Int, false},
-			"base":     &Entry{"", []any{}, reflect.String, false},
+			"protocol": &Entry{Value: "http", AuthorizedValues: []any{"http", "https"}, Type: reflect.String, NonReloadable: true},
+			"host":     &Entry{Value: nil, AuthorizedValues: []any{}, Type: reflect.String, NonReloadable: true},
+			"port":     &Entry{Value: 80, AuthorizedValues: []any{}, Type: reflect.Int, NonReloadable: true},
+			"base":     &Entry{Value: "", AuthorizedValues: []any{}, Type: reflect.String, NonReloadable: true},
 		},
 	},
 	"database": object{
-		"connection":               &Entry{"none", []any{}, reflect.String, false},
-		"host":                     &Entry{"127.0.0.1", []any{}, reflect.String, false},
-		"port":                     &Entry{0, []any{}, reflect.Int, false},
-		"name":                     &Entry{"", []any{}, reflect.String, false},
-		"username":                 &Entry{"", []any{}, reflect.String, false},
-		"password":                 &Entry{"", []any{}, reflect.String, false},
-		"options":                  &Entry{"", []any{}, reflect.String, false},
-		"maxOpenConnections":       &Entry{20, []any{}, reflect.Int, false},
-		"maxIdleConnections":       &Entry{20, []any{}, reflect.Int, false},
-		"maxLifetime":              &Entry{300, []any{}, reflect.Int, false},
-		"defaultReadQueryTimeout":  &Entry{20000, []any{}, reflect.Int, false},
-		"defaultWriteQueryTimeout": &Entry{40000, []any{}, reflect.Int, false},
+		"connection":               &Entry{Value: "none", AuthorizedValues: []any{}, Type: reflect.String, NonReloadable: true},
+		"host":                     &Entry{Value: "127.0.0.1", AuthorizedValues: []any{}, Type: reflect.String, NonReloadable: true},
+		"port":                     &Entry{Value: 0, AuthorizedValues: []any{}, Type: reflect.Int, NonReloadable: true},
+		"name":                     &Entry{Value: "", AuthorizedValues: []any{}, Type: reflect.String, NonReloadable: true},
+		"username":                 &Entry{Value: "", AuthorizedValues: []any{}, Type: reflect.String, NonReloadable: true},
+		"password":                 &Entry{Value: "", AuthorizedValues: []any{}, Type: reflect.String, Sensitive: true, NonReloadable: true},
+		"options":                  &Entry{Value: "", AuthorizedValues: []any{}, Type: reflect.String, NonReloadable: true},
+		"maxOpenConnections":       &Entry{Value: 20, AuthorizedValues: []any{}, Type: reflect.Int},
+		"maxIdleConnections":       &Entry{Value: 20, AuthorizedValues: []any{}, Type: reflect.Int},
+		"maxLifetime":              &Entry{Value: 300, AuthorizedValues: []any{}, Type: reflect.Int},
+		"defaultReadQueryTimeout":  &Entry{Value: 20000, AuthorizedValues: []any{}, Type: reflect.Int},
+		"defaultWriteQueryTimeout": &Entry{Value: 40000, AuthorizedValues: []any{}, Type: reflect.Int},
 		"config": object{
-			"skipDefaultTransaction":                   &Entry{false, []any{}, reflect.Bool, false},
-			"dryRun":                                   &Entry{false, []any{}, reflect.Bool, false},
-			"prepareStmt":                              &Entry{true, []any{}, reflect.Bool, false},
-			"disableNestedTransaction":                 &Entry{false, []any{}, reflect.Bool, false},
-			"allowGlobalUpdate":                        &Entry{false, []any{}, reflect.Bool, false},
-			"disableAutomaticPing":                     &Entry{false, []any{}, reflect.Bool, false},
-			"disableForeignKeyConstraintWhenMigrating": &Entry{false, []any{}, reflect.Bool, false},
+			"skipDefaultTransaction":                   &Entry{Value: false, AuthorizedValues: []any{}, Type: reflect.Bool},
+			"dryRun":                                   &Entry{Value: false, AuthorizedValues: []any{}, Type: reflect.Bool},
+			"prepareStmt":                              &Entry{Value: true, AuthorizedValues: []any{}, Type: reflect.Bool},
+			"disableNestedTransaction":                 &Entry{Value: false, AuthorizedValues: []any{}, Type: reflect.Bool},
+			"allowGlobalUpdate":                        &Entry{Value: false, AuthorizedValues: []any{}, Type: reflect.Bool},
+			"disableAutomaticPing":                     &Entry{Value: false, AuthorizedValues: []any{}, Type: reflect.Bool},
+			"disableForeignKeyConstraintWhenMigrating": &Entry{Value: false, AuthorizedValues: []any{}, Type: reflect.Bool},
 		},
 	},
 }
@@ -70,7 +74,9 @@ func loadDefaults(src object, dst object) {
 				}
 				value = slice.Interface()
 			}
-			dst[k] = &Entry{value, entry.AuthorizedValues, entry.Type, entry.IsSlice}
+			e := *entry
+			e.Value = value
+			dst[k] = &e
 		}
 	}
 }
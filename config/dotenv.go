@@ -0,0 +1,250 @@
+package config
+
+import (
+	"bufio"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+)
+
+// LoadEnvFile reads the given dotenv file and sets the environment variables
+// it defines. Variables already set in the environment are never overridden,
+// so the actual environment always takes precedence.
+//
+// The file contains one "KEY=value" assignment per line. The following syntax is supported:
+//   - Empty lines and lines starting with "#" are ignored
+//   - The "export " prefix is optional
+//   - Unquoted values are trimmed and can be followed by a " #" comment
+//   - Single-quoted values are kept as-is
+//   - Double-quoted values support the "\n", "\r", "\t", "\"" and "\\" escape sequences
+//
+// `Load` and `LoadFrom` automatically load the ".env" file from the current working
+// directory, if it exists. If the "GOYAVE_ENV" env variable is set, the ".env.<env>"
+// file is loaded first and therefore takes precedence over ".env".
+func LoadEnvFile(path string) error {
+	return loadEnvFile(&osfs.FS{}, path)
+}
+
+// dotenvVars the env variables set by `loadEnvFiles` and their value, so they can be
+// updated or unset when the ".env" files change and the configuration is reloaded.
+var dotenvVars = struct {
+	values map[string]string
+	mu     sync.Mutex
+}{values: map[string]string{}}
+
+// envFiles returns the names of the ".env" files, by order of precedence.
+func envFiles() []string {
+	files := []string{".env"}
+	if env := getEnvironment(); env != "" {
+		files = append([]string{".env." + env}, files...)
+	}
+	return files
+}
+
+// loadEnvFiles sets the env variables defined in the ".env" files. Unlike `LoadEnvFile`,
+// variables previously set by this function are updated if the files changed since, or
+// unset if they are not defined anymore. Variables set by other means are never overridden.
+func loadEnvFiles(filesystem fs.FS) error {
+	vars := [][2]string{}
+	for _, file := range envFiles() {
+		if !fileExists(filesystem, file) {
+			continue
+		}
+		v, err := readEnvFile(filesystem, file)
+		if err != nil {
+			return err
+		}
+		vars = append(vars, v...)
+	}
+
+	dotenvVars.mu.Lock()
+	defer dotenvVars.mu.Unlock()
+	defined := make(map[string]struct{}, len(vars))
+	for _, v := range vars {
+		if _, ok := defined[v[0]]; ok {
+			continue // Defined in a file with higher precedence
+		}
+		defined[v[0]] = struct{}{}
+		if current, set := os.LookupEnv(v[0]); set {
+			if previous, ok := dotenvVars.values[v[0]]; !ok || current != previous {
+				continue
+			}
+		}
+		if err := os.Setenv(v[0], v[1]); err != nil {
+			return errors.New(err)
+		}
+		dotenvVars.values[v[0]] = v[1]
+	}
+	for key, previous := range dotenvVars.values {
+		if _, ok := defined[key]; ok {
+			continue
+		}
+		delete(dotenvVars.values, key)
+		if current, set := os.LookupEnv(key); set && current == previous {
+			if err := os.Unsetenv(key); err != nil {
+				return errors.New(err)
+			}
+		}
+	}
+	return nil
+}
+
+// dotenvLayer loads the ".env" files before the next layers are read.
+// It doesn't define any configuration value but returns the ".env" files,
+// even if they don't exist, so they are watched by `Config.Watch()`.
+func dotenvLayer(filesystem fs.FS) layer {
+	return func(_ object) ([]sourceValues, error) {
+		if err := loadEnvFiles(filesystem); err != nil {
+			return nil, err
+		}
+		files := envFiles()
+		values := make([]sourceValues, 0, len(files))
+		for _, file := range files {
+			values = append(values, sourceValues{values: object{}, fs: filesystem, source: Source{Kind: SourceFile, Name: file}})
+		}
+		return values, nil
+	}
+}
+
+func loadEnvFile(filesystem fs.FS, path string) error {
+	vars, err := readEnvFile(filesystem, path)
+	if err != nil {
+		return err
+	}
+	for _, v := range vars {
+		if _, set := os.LookupEnv(v[0]); set {
+			continue
+		}
+		if err := os.Setenv(v[0], v[1]); err != nil {
+			return errors.New(err)
+		}
+	}
+	return nil
+}
+
+func readEnvFile(filesystem fs.FS, path string) (vars [][2]string, err error) {
+	file, err := filesystem.Open(path)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	defer func() {
+		e := file.Close()
+		if err == nil && e != nil {
+			err = errors.New(e)
+		}
+	}()
+	return parseEnvFile(file, path)
+}
+
+// parseEnvFile returns the key/value pairs defined in the given dotenv file, in order.
+func parseEnvFile(r io.Reader, path string) ([][2]string, error) {
+	vars := [][2]string{}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !isEnvKey(key) {
+			return nil, errors.Errorf("%s:%d: invalid assignment", path, lineNumber)
+		}
+
+		value, err := parseEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, errors.Errorf("%s:%d: %w", path, lineNumber, err)
+		}
+		vars = append(vars, [2]string{key, value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New(err)
+	}
+	return vars, nil
+}
+
+func parseEnvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	var result string
+	var rest string
+	switch value[0] {
+	case '\'':
+		end := strings.IndexByte(value[1:], '\'')
+		if end == -1 {
+			return "", errors.New("unterminated single-quoted value")
+		}
+		result = value[1 : end+1]
+		rest = value[end+2:]
+	case '"':
+		builder := strings.Builder{}
+		closed := false
+		i := 1
+		for ; i < len(value) && !closed; i++ {
+			c := value[i]
+			switch {
+			case c == '"':
+				closed = true
+			case c == '\\' && i+1 < len(value):
+				i++
+				switch value[i] {
+				case 'n':
+					builder.WriteByte('\n')
+				case 'r':
+					builder.WriteByte('\r')
+				case 't':
+					builder.WriteByte('\t')
+				case '"', '\\':
+					builder.WriteByte(value[i])
+				default:
+					builder.WriteByte('\\')
+					builder.WriteByte(value[i])
+				}
+			default:
+				builder.WriteByte(c)
+			}
+		}
+		if !closed {
+			return "", errors.New("unterminated double-quoted value")
+		}
+		result = builder.String()
+		rest = value[i:]
+	default:
+		if i := strings.Index(value, " #"); i != -1 {
+			value = value[:i]
+		}
+		return strings.TrimSpace(value), nil
+	}
+
+	rest = strings.TrimSpace(rest)
+	if rest != "" && rest[0] != '#' {
+		return "", errors.New("unexpected characters after quoted value")
+	}
+	return result, nil
+}
+
+func isEnvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case i > 0 && (c == '.' || (c >= '0' && c <= '9')):
+		default:
+			return false
+		}
+	}
+	return true
+}
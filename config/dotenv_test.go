@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unsetEnv unsets the given env variables and restores them
+// when the test ends.
+func unsetEnv(t *testing.T, keys ...string) {
+	t.Helper()
+	for _, k := range keys {
+		t.Setenv(k, "") // Registers the cleanup
+		require.NoError(t, os.Unsetenv(k))
+	}
+}
+
+func TestParseEnvFile(t *testing.T) {
+	content := `
+# Comment
+SIMPLE=value
+export EXPORTED=exported
+  SPACES  =   trimmed value
+EMPTY=
+INLINE_COMMENT=value # comment
+HASH=value#not-a-comment
+SINGLE='single # quoted \n'
+DOUBLE="double \"quoted\"\n\t\\ \x" # comment
+EQUALS=a=b
+app.name=dotted
+`
+	vars, err := parseEnvFile(strings.NewReader(content), ".env")
+	require.NoError(t, err)
+
+	expected := [][2]string{
+		{"SIMPLE", "value"},
+		{"EXPORTED", "exported"},
+		{"SPACES", "trimmed value"},
+		{"EMPTY", ""},
+		{"INLINE_COMMENT", "value"},
+		{"HASH", "value#not-a-comment"},
+		{"SINGLE", `single # quoted \n`},
+		{"DOUBLE", "double \"quoted\"\n\t\\ \\x"},
+		{"EQUALS", "a=b"},
+		{"app.name", "dotted"},
+	}
+	assert.Equal(t, expected, vars)
+
+	cases := []struct {
+		content string
+		err     string
+	}{
+		{content: "A=1\nNO_EQUALS", err: ".env:2: invalid assignment"},
+		{content: "=value", err: ".env:1: invalid assignment"},
+		{content: "1KEY=value", err: ".env:1: invalid assignment"},
+		{content: "MY-KEY=value", err: ".env:1: invalid assignment"},
+		{content: "A='unterminated", err: ".env:1: unterminated single-quoted value"},
+		{content: `A="unterminated`, err: ".env:1: unterminated double-quoted value"},
+		{content: `A="value" trailing`, err: ".env:1: unexpected characters after quoted value"},
+	}
+	for _, c := range cases {
+		_, err := parseEnvFile(strings.NewReader(c.content), ".env")
+		require.Error(t, err)
+		assert.Equal(t, c.err, err.Error())
+	}
+}
+
+func TestLoadEnvFiles(t *testing.T) {
+	filesystem := fstest.MapFS{
+		".env":      &fstest.MapFile{Data: []byte("GOYAVE_TEST_DOTENV_A=from-env\nGOYAVE_TEST_DOTENV_B=from-env\nGOYAVE_TEST_DOTENV_C=from-env")},
+		".env.test": &fstest.MapFile{Data: []byte("GOYAVE_TEST_DOTENV_A=from-env-test")},
+		".env.bad":  &fstest.MapFile{Data: []byte("invalid")},
+	}
+
+	t.Run("load", func(t *testing.T) {
+		unsetEnv(t, "GOYAVE_TEST_DOTENV_A", "GOYAVE_TEST_DOTENV_B", "GOYAVE_TEST_DOTENV_C")
+		t.Setenv("GOYAVE_TEST_DOTENV_C", "from-process")
+		t.Setenv("GOYAVE_ENV", "test")
+
+		require.NoError(t, loadEnvFiles(filesystem))
+		assert.Equal(t, "from-env-test", os.Getenv("GOYAVE_TEST_DOTENV_A"))
+		assert.Equal(t, "from-env", os.Getenv("GOYAVE_TEST_DOTENV_B"))
+		assert.Equal(t, "from-process", os.Getenv("GOYAVE_TEST_DOTENV_C"))
+	})
+
+	t.Run("no_files", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "")
+		require.NoError(t, loadEnvFiles(fstest.MapFS{}))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		unsetEnv(t, "GOYAVE_TEST_DOTENV_A", "GOYAVE_TEST_DOTENV_B", "GOYAVE_TEST_DOTENV_C")
+		t.Setenv("GOYAVE_ENV", "bad")
+		require.Error(t, loadEnvFiles(filesystem))
+	})
+
+	t.Run("LoadEnvFile", func(t *testing.T) {
+		path := t.TempDir() + "/.env"
+		require.NoError(t, os.WriteFile(path, []byte("GOYAVE_TEST_DOTENV_PORT=4567"), 0o600))
+		unsetEnv(t, "GOYAVE_TEST_DOTENV_PORT")
+
+		require.NoError(t, LoadEnvFile(path))
+
+		// Substitution uses the loaded variables
+		cfg, err := LoadJSON(`{"server": {"port": "${GOYAVE_TEST_DOTENV_PORT}"}}`)
+		require.NoError(t, err)
+		assert.Equal(t, 4567, cfg.Get("server.port"))
+
+		require.Error(t, LoadEnvFile(t.TempDir()+"/nonexisting"))
+	})
+}
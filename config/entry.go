@@ -1,6 +1,8 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"reflect"
 	"strconv"
@@ -14,11 +16,20 @@ import (
 // It contains the entry value, its expected type (for validation)
 // and a slice of authorized values (for validation too). If this slice
 // is empty, it means any value can be used, provided it is of the correct type.
+//
+// Sensitive entries (such as passwords and secrets) are redacted when the
+// configuration is logged, dumped or marshaled. Their value can be a secret
+// reference (e.g. "file:/run/secrets/db_password"), see `RegisterSecretProvider`.
+//
+// Non-reloadable entries (such as the server port) keep their value when the
+// configuration is reloaded, see `Config.Watch()`.
 type Entry struct {
 	Value            any
 	AuthorizedValues []any // Leave empty for "any"
 	Type             reflect.Kind
 	IsSlice          bool
+	Sensitive        bool
+	NonReloadable    bool
 }
 
 func makeEntryFromValue(value any) *Entry {
@@ -29,7 +40,7 @@ func makeEntryFromValue(value any) *Entry {
 		kind = t.Elem().Kind()
 		isSlice = true
 	}
-	return &Entry{value, []any{}, kind, isSlice}
+	return &Entry{Value: value, AuthorizedValues: []any{}, Type: kind, IsSlice: isSlice}
 }
 
 func (e *Entry) validate(key string) error {
@@ -40,6 +51,9 @@ func (e *Entry) validate(key string) error {
 	if err := e.tryEnvVarConversion(key); err != nil {
 		return err
 	}
+	if err := e.trySecretResolution(key); err != nil {
+		return err
+	}
 	t := reflect.TypeOf(e.Value)
 	kind := t.Kind()
 	if e.IsSlice && kind == reflect.Slice {
@@ -147,11 +161,6 @@ func (e *Entry) tryEnvVarConversion(key string) error {
 	if ok {
 		val, err := e.convertEnvVar(str, key)
 		if err == nil && val != nil {
-
-			if e.IsSlice {
-				return errors.Errorf("%q is a slice entry, it cannot be loaded from env", key)
-			}
-
 			e.Value = val
 		}
 		return err
@@ -168,27 +177,79 @@ func (e *Entry) convertEnvVar(str, key string) (any, error) {
 			return nil, errors.Errorf("%q: %q environment variable is not set", key, varName)
 		}
 
-		switch e.Type {
-		case reflect.Int:
-			if i, err := strconv.Atoi(value); err == nil {
-				return i, nil
-			}
-			return nil, errors.Errorf("%q could not be converted to int from environment variable %q of value %q", key, varName, value)
-		case reflect.Float64:
-			if f, err := strconv.ParseFloat(value, 64); err == nil {
-				return f, nil
-			}
-			return nil, errors.Errorf("%q could not be converted to float64 from environment variable %q of value %q", key, varName, value)
-		case reflect.Bool:
-			if b, err := strconv.ParseBool(value); err == nil {
-				return b, nil
-			}
-			return nil, errors.Errorf("%q could not be converted to bool from environment variable %q of value %q", key, varName, value)
-		default:
-			// Keep value as string if type is not supported and let validation do its job
-			return value, nil
-		}
+		origin := fmt.Sprintf("environment variable %q of value %q", varName, value)
+		return e.convertString(value, key, origin)
 	}
 
 	return nil, nil
 }
+
+// convertString converts the given string to the entry's type. If the entry is
+// a slice, see `convertStringSlice`. The origin describes where the value comes
+// from and is used in error messages.
+func (e *Entry) convertString(value, key, origin string) (any, error) {
+	if e.IsSlice {
+		return e.convertStringSlice(value, key, origin)
+	}
+	return e.convertScalar(value, key, origin)
+}
+
+func (e *Entry) convertScalar(value, key, origin string) (any, error) {
+	switch e.Type {
+	case reflect.Int:
+		if i, err := strconv.Atoi(value); err == nil {
+			return i, nil
+		}
+	case reflect.Float64:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f, nil
+		}
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b, nil
+		}
+	default:
+		// Keep value as string if type is not supported and let validation do its job
+		return value, nil
+	}
+	return nil, errors.Errorf("%q could not be converted to %s from %s", key, e.Type, origin)
+}
+
+// convertStringSlice converts the given string to a slice.
+// The value is either JSON encoded (e.g. `["a","b"]`) or a comma-separated list
+// (e.g. `a,b`). In the latter case, each element is trimmed and converted to the
+// entry's type. An empty value results in an empty slice.
+func (e *Entry) convertStringSlice(value, key, origin string) (any, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") {
+		var slice []any
+		if err := json.Unmarshal([]byte(value), &slice); err != nil {
+			return nil, errors.Errorf("%q could not be decoded as a JSON array from %s", key, origin)
+		}
+		// The elements are converted to the entry's type during validation
+		return slice, nil
+	}
+
+	var elements []string
+	if value != "" {
+		elements = strings.Split(value, ",")
+	}
+	elemType := reflect.TypeOf("")
+	switch e.Type {
+	case reflect.Int:
+		elemType = reflect.TypeOf(0)
+	case reflect.Float64:
+		elemType = reflect.TypeOf(0.0)
+	case reflect.Bool:
+		elemType = reflect.TypeOf(false)
+	}
+	slice := reflect.MakeSlice(reflect.SliceOf(elemType), 0, len(elements))
+	for _, elem := range elements {
+		v, err := e.convertScalar(strings.TrimSpace(elem), key, origin)
+		if err != nil {
+			return nil, err
+		}
+		slice = reflect.Append(slice, reflect.ValueOf(v))
+	}
+	return slice.Interface(), nil
+}
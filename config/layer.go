@@ -0,0 +1,327 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+)
+
+// SourceKind the kind of source a configuration value can come from.
+type SourceKind int
+
+// Source kinds
+const (
+	// SourceDefault the value is the default value of the entry.
+	SourceDefault SourceKind = iota
+	// SourceFile the value comes from a config file.
+	SourceFile
+	// SourceRaw the value comes from a raw JSON or YAML string (`LoadJSON`, `LoadYAML`).
+	SourceRaw
+	// SourceEnv the value comes from an environment variable override.
+	SourceEnv
+	// SourceOverride the value comes from a programmatic override (`LoadOptions.Overrides`).
+	SourceOverride
+	// SourceSet the value was set using `Config.Set()`.
+	SourceSet
+)
+
+func (k SourceKind) String() string {
+	switch k {
+	case SourceDefault:
+		return "default"
+	case SourceFile:
+		return "file"
+	case SourceRaw:
+		return "raw"
+	case SourceEnv:
+		return "env"
+	case SourceOverride:
+		return "override"
+	case SourceSet:
+		return "set"
+	}
+	return fmt.Sprintf("SourceKind(%d)", int(k))
+}
+
+// Source describes where the effective value of a config entry came from.
+type Source struct {
+	// Name the name of the source: the file path for `SourceFile` and the
+	// variable name for `SourceEnv`. Empty for the other kinds.
+	Name string
+	Kind SourceKind
+}
+
+// String returns the source kind, followed by the source name if
+// there is one. For example: "file:config.production.json" or "env:DB_HOST".
+func (s Source) String() string {
+	if s.Name == "" {
+		return s.Kind.String()
+	}
+	return s.Kind.String() + ":" + s.Name
+}
+
+// LoadOptions options for `LoadLayered`.
+type LoadOptions struct {
+	// FS the file system the config files and ".env" files are read from.
+	// Defaults to the OS file system (relative to the working directory).
+	FS fs.FS
+
+	// EnvVars maps config keys (dot-separated paths) to environment variable names.
+	// If the variable is set, its value overrides the entry. The value is converted
+	// to the entry's type the same way as "${VAR}" values, including slices.
+	EnvVars map[string]string
+
+	// Overrides values overriding all the other layers. Keys are dot-separated paths.
+	Overrides map[string]any
+
+	// EnvPrefix the prefix of the environment variable names used when `AutoEnv` is enabled.
+	// For example, with the "APP" prefix, "server.port" is overridden by "APP_SERVER_PORT".
+	EnvPrefix string
+
+	// BaseFile the path to the base config file. Defaults to "config.json", or
+	// "config.yml" / "config.yaml" if it doesn't exist.
+	BaseFile string
+
+	// Environment the name of the environment, used to pick the environment config file.
+	// Defaults to the "GOYAVE_ENV" env variable. If not set, the "app.environment" value
+	// of the base config file is used.
+	Environment string
+
+	// AutoEnv if true, every entry can be overridden by an environment variable whose
+	// name is derived from the entry's key and `EnvPrefix` (see `EnvVarName`).
+	// Values are converted to the entry's type. Slices are either JSON encoded
+	// (`["a","b"]`) or comma-separated (`a,b`). The explicit `EnvVars` mapping
+	// takes precedence over the derived names.
+	AutoEnv bool
+}
+
+// sourceValues raw configuration values and their source.
+type sourceValues struct {
+	values object
+	// fs the file system of the file the values were read from (`SourceFile` only).
+	fs     fs.FS
+	source Source
+}
+
+// layer reads configuration values. The configuration loaded by the previous layers
+// is given so layers can depend on it. A layer returning no values is skipped.
+type layer func(config object) ([]sourceValues, error)
+
+// LoadLayered loads the configuration by deep merging the following layers, each layer
+// only overriding the keys it defines:
+//  1. The default values of the registered entries
+//  2. The base config file (`LoadOptions.BaseFile`)
+//  3. The environment config file, if it exists. For the "production" environment
+//     and "config.json" base file, the environment file is "config.production.json".
+//     Local environments ("local" and "localhost") don't have an environment file.
+//  4. The automatic environment variable overrides, if `LoadOptions.AutoEnv` is enabled
+//  5. The environment variable overrides (`LoadOptions.EnvVars`)
+//  6. The programmatic overrides (`LoadOptions.Overrides`)
+//
+// The resulting configuration is validated once all layers are applied. Like `Load`,
+// the ".env" files are loaded first. Use `Config.Source()` to find out which layer
+// the effective value of an entry came from.
+func LoadLayered(opts LoadOptions) (*Config, error) {
+	filesystem := opts.FS
+	if filesystem == nil {
+		filesystem = &osfs.FS{}
+	}
+
+	baseFile := opts.BaseFile
+	if baseFile == "" {
+		baseFile = "config.json"
+	}
+
+	layers := []layer{
+		dotenvLayer(filesystem),
+		defaultLoader.fileLayer(filesystem, findConfigFile(filesystem, baseFile)),
+		defaultLoader.environmentFileLayer(filesystem, baseFile, opts.Environment),
+	}
+	if opts.AutoEnv {
+		layers = append(layers, autoEnvLayer(opts.EnvPrefix))
+	}
+	layers = append(layers, envVarsLayer(opts.EnvVars), overridesLayer(opts.Overrides))
+	return defaultLoader.load(layers...)
+}
+
+func (l *loader) fileLayer(filesystem fs.FS, file string) layer {
+	return func(_ object) ([]sourceValues, error) {
+		values, err := l.readConfigFile(filesystem, file)
+		if err != nil {
+			return nil, err
+		}
+		return []sourceValues{{values: values, fs: filesystem, source: Source{Kind: SourceFile, Name: file}}}, nil
+	}
+}
+
+func (l *loader) stringLayer(decode decodeFunc, str string) layer {
+	return func(_ object) ([]sourceValues, error) {
+		values, err := l.readString(decode, str)
+		if err != nil {
+			return nil, err
+		}
+		return []sourceValues{{values: values, source: Source{Kind: SourceRaw}}}, nil
+	}
+}
+
+// environmentFileLayer reads the environment config file matching the given base file,
+// if it exists. If the given environment is empty, it is determined from the "GOYAVE_ENV"
+// env variable or from the "app.environment" entry of the configuration loaded so far.
+func (l *loader) environmentFileLayer(filesystem fs.FS, baseFile, environment string) layer {
+	return func(config object) ([]sourceValues, error) {
+		env := strings.ToLower(environment)
+		if env == "" {
+			env = getEnvironment()
+		}
+		if env == "" {
+			env = configEnvironment(config)
+		}
+		if env == "" || env == "local" || env == "localhost" {
+			return nil, nil
+		}
+
+		ext := path.Ext(baseFile)
+		file := findConfigFile(filesystem, strings.TrimSuffix(baseFile, ext)+"."+env+ext)
+		if !fileExists(filesystem, file) {
+			return nil, nil
+		}
+		return l.fileLayer(filesystem, file)(config)
+	}
+}
+
+// configEnvironment returns the lowercased "app.environment" value of the given config,
+// or an empty string if it is not a valid string.
+func configEnvironment(config object) string {
+	app, ok := config["app"].(object)
+	if !ok {
+		return ""
+	}
+	entry, ok := app["environment"].(*Entry)
+	if !ok {
+		return ""
+	}
+	// Validate a copy so "${VAR}" values are resolved without altering the config
+	e := *entry
+	if err := e.validate("app.environment"); err != nil {
+		return ""
+	}
+	env, _ := e.Value.(string)
+	return strings.ToLower(env)
+}
+
+func envVarsLayer(vars map[string]string) layer {
+	return func(_ object) ([]sourceValues, error) {
+		keys := make([]string, 0, len(vars))
+		for k := range vars {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		values := make([]sourceValues, 0, len(keys))
+		for _, key := range keys {
+			name := vars[key]
+			if _, set := os.LookupEnv(name); !set {
+				continue
+			}
+			// The value is resolved and converted during validation
+			v, err := expandKeys(map[string]any{key: "${" + name + "}"})
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, sourceValues{values: v, source: Source{Kind: SourceEnv, Name: name}})
+		}
+		return values, nil
+	}
+}
+
+func overridesLayer(overrides map[string]any) layer {
+	return func(_ object) ([]sourceValues, error) {
+		if len(overrides) == 0 {
+			return nil, nil
+		}
+		values, err := expandKeys(overrides)
+		if err != nil {
+			return nil, err
+		}
+		return []sourceValues{{values: values, source: Source{Kind: SourceOverride}}}, nil
+	}
+}
+
+// expandKeys converts a map with dot-separated keys into nested maps.
+// For example, `{"server.port": 8080}` becomes `{"server": {"port": 8080}}`.
+func expandKeys(flat map[string]any) (object, error) {
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := object{}
+	for _, key := range keys {
+		if key == "" || strings.HasPrefix(key, ".") || strings.HasSuffix(key, ".") || strings.Contains(key, "..") {
+			return nil, errors.Errorf("\n\t- invalid key %q", key)
+		}
+		segments := strings.Split(key, ".")
+		current := map[string]any(result)
+		for _, s := range segments[:len(segments)-1] {
+			child, exists := current[s]
+			if !exists {
+				child = map[string]any{}
+				current[s] = child
+			}
+			category, ok := child.(map[string]any)
+			if !ok {
+				return nil, errors.Errorf("\n\t- cannot override entry %q with a category", s)
+			}
+			current = category
+		}
+		last := segments[len(segments)-1]
+		if _, exists := current[last]; exists {
+			return nil, errors.Errorf("\n\t- key %q conflicts with another key", key)
+		}
+		current[last] = flat[key]
+	}
+	return result, nil
+}
+
+// recordSources sets the given source for all the entries defined in the given values.
+func recordSources(values map[string]any, prefix string, source Source, sources map[string]Source) {
+	for k, v := range values {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch category := v.(type) {
+		case object:
+			recordSources(category, key, source, sources)
+		case map[string]any:
+			recordSources(category, key, source, sources)
+		default:
+			sources[key] = source
+		}
+	}
+}
+
+// Source returns where the effective value of the given entry came from.
+// Returns false if the entry doesn't exist.
+func (c *Config) Source(key string) (Source, bool) {
+	source, ok := c.current().sources[key]
+	return source, ok
+}
+
+// Sources returns a copy of the sources of all the entries, indexed by
+// their dot-separated path. This is useful to debug layered configurations.
+func (c *Config) Sources() map[string]Source {
+	current := c.current().sources
+	sources := make(map[string]Source, len(current))
+	for k, v := range current {
+		sources[k] = v
+	}
+	return sources
+}
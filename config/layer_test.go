@@ -0,0 +1,215 @@
+package config
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource(t *testing.T) {
+	assert.Equal(t, "default", Source{Kind: SourceDefault}.String())
+	assert.Equal(t, "file:config.json", Source{Kind: SourceFile, Name: "config.json"}.String())
+	assert.Equal(t, "raw", Source{Kind: SourceRaw}.String())
+	assert.Equal(t, "env:DB_HOST", Source{Kind: SourceEnv, Name: "DB_HOST"}.String())
+	assert.Equal(t, "override", Source{Kind: SourceOverride}.String())
+	assert.Equal(t, "set", Source{Kind: SourceSet}.String())
+	assert.Equal(t, "SourceKind(42)", SourceKind(42).String())
+}
+
+func TestExpandKeys(t *testing.T) {
+	result, err := expandKeys(map[string]any{
+		"server.port":       8080,
+		"server.proxy.host": "example.org",
+		"custom":            true,
+	})
+	require.NoError(t, err)
+	expected := object{
+		"server": map[string]any{
+			"port":  8080,
+			"proxy": map[string]any{"host": "example.org"},
+		},
+		"custom": true,
+	}
+	assert.Equal(t, expected, result)
+
+	for _, keys := range []map[string]any{
+		{"": 1},
+		{".a": 1},
+		{"a.": 1},
+		{"a..b": 1},
+		{"a": 1, "a.b": 2},
+	} {
+		_, err := expandKeys(keys)
+		require.Error(t, err)
+	}
+}
+
+func TestLoadLayered(t *testing.T) {
+	filesystem := fstest.MapFS{
+		"config.json": &fstest.MapFile{Data: []byte(`{
+			"app": {"name": "base", "environment": "production"},
+			"server": {"host": "base-host", "port": 1000, "proxy": {"host": "base-proxy"}},
+			"custom": {"a": "base", "b": "base"}
+		}`)},
+		"config.production.json": &fstest.MapFile{Data: []byte(`{
+			"server": {"port": 2000, "proxy": {"base": "/api"}},
+			"custom": {"b": "production"}
+		}`)},
+		"config.staging.yml":  &fstest.MapFile{Data: []byte("server:\n  port: 3000\n")},
+		"config.invalid.json": &fstest.MapFile{Data: []byte(`{"server": {"port": "not an int"}}`)},
+		"config.broken.json":  &fstest.MapFile{Data: []byte(`{"unclosed`)},
+		"app.yaml":            &fstest.MapFile{Data: []byte("app:\n  name: yaml\n")},
+		"app.test.yaml":       &fstest.MapFile{Data: []byte("app:\n  debug: false\n")},
+	}
+
+	t.Run("environment_from_config", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "")
+		cfg, err := LoadLayered(LoadOptions{FS: filesystem})
+		require.NoError(t, err)
+
+		assert.Equal(t, "base", cfg.Get("app.name"))
+		assert.Equal(t, "base-host", cfg.Get("server.host"))
+		assert.Equal(t, 2000, cfg.Get("server.port"))
+		assert.Equal(t, "base-proxy", cfg.Get("server.proxy.host"))
+		assert.Equal(t, "/api", cfg.Get("server.proxy.base"))
+		assert.Equal(t, "base", cfg.Get("custom.a"))
+		assert.Equal(t, "production", cfg.Get("custom.b"))
+
+		sources := cfg.Sources()
+		assert.Equal(t, Source{Kind: SourceFile, Name: "config.json"}, sources["app.name"])
+		assert.Equal(t, Source{Kind: SourceFile, Name: "config.production.json"}, sources["server.port"])
+		assert.Equal(t, Source{Kind: SourceFile, Name: "config.production.json"}, sources["custom.b"])
+		assert.Equal(t, Source{Kind: SourceDefault}, sources["server.writeTimeout"])
+		assert.Equal(t, Source{Kind: SourceDefault}, sources["server.proxy.protocol"])
+	})
+
+	t.Run("GOYAVE_ENV", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "staging")
+		cfg, err := LoadLayered(LoadOptions{FS: filesystem})
+		require.NoError(t, err)
+		assert.Equal(t, 3000, cfg.Get("server.port"))
+		source, ok := cfg.Source("server.port")
+		assert.True(t, ok)
+		assert.Equal(t, Source{Kind: SourceFile, Name: "config.staging.yml"}, source)
+	})
+
+	t.Run("environment_option", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "staging")
+		cfg, err := LoadLayered(LoadOptions{FS: filesystem, Environment: "localhost"})
+		require.NoError(t, err)
+		assert.Equal(t, 1000, cfg.Get("server.port"))
+
+		cfg, err = LoadLayered(LoadOptions{FS: filesystem, Environment: "nonexisting"})
+		require.NoError(t, err)
+		assert.Equal(t, 1000, cfg.Get("server.port"))
+	})
+
+	t.Run("base_file", func(t *testing.T) {
+		cfg, err := LoadLayered(LoadOptions{FS: filesystem, BaseFile: "app.yaml", Environment: "test"})
+		require.NoError(t, err)
+		assert.Equal(t, "yaml", cfg.Get("app.name"))
+		assert.Equal(t, false, cfg.Get("app.debug"))
+		source, _ := cfg.Source("app.debug")
+		assert.Equal(t, Source{Kind: SourceFile, Name: "app.test.yaml"}, source)
+	})
+
+	t.Run("env_vars_and_overrides", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "")
+		t.Setenv("TEST_LAYERED_PORT", "4000")
+		t.Setenv("TEST_LAYERED_HOST", "env-host")
+		unsetEnv(t, "TEST_LAYERED_UNSET")
+		cfg, err := LoadLayered(LoadOptions{
+			FS: filesystem,
+			EnvVars: map[string]string{
+				"server.port":  "TEST_LAYERED_PORT",
+				"server.host":  "TEST_LAYERED_HOST",
+				"server.proxy": "TEST_LAYERED_UNSET",
+			},
+			Overrides: map[string]any{
+				"server.host":  "override-host",
+				"custom.c.new": 1.5,
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 4000, cfg.Get("server.port"))
+		assert.Equal(t, "override-host", cfg.Get("server.host"))
+		assert.InEpsilon(t, 1.5, cfg.Get("custom.c.new"), 0)
+		assert.Equal(t, "base-proxy", cfg.Get("server.proxy.host"))
+
+		sources := cfg.Sources()
+		assert.Equal(t, Source{Kind: SourceEnv, Name: "TEST_LAYERED_PORT"}, sources["server.port"])
+		assert.Equal(t, Source{Kind: SourceOverride}, sources["server.host"])
+		assert.Equal(t, Source{Kind: SourceOverride}, sources["custom.c.new"])
+
+		cfg.Set("server.port", 5000)
+		source, _ := cfg.Source("server.port")
+		assert.Equal(t, Source{Kind: SourceSet}, source)
+
+		// Sources returns a copy
+		sources["server.port"] = Source{Kind: SourceDefault}
+		source, _ = cfg.Source("server.port")
+		assert.Equal(t, Source{Kind: SourceSet}, source)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "")
+
+		cfg, err := LoadLayered(LoadOptions{FS: filesystem, BaseFile: "nonexisting.json"})
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+
+		cfg, err = LoadLayered(LoadOptions{FS: filesystem, Environment: "invalid"})
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+
+		cfg, err = LoadLayered(LoadOptions{FS: filesystem, Environment: "broken"})
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+
+		cfg, err = LoadLayered(LoadOptions{FS: filesystem, Overrides: map[string]any{"server.port": "abc"}})
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+		assert.Equal(t, "Config error: \n\t- \"server.port\" type must be int", err.Error())
+
+		cfg, err = LoadLayered(LoadOptions{FS: filesystem, Overrides: map[string]any{"server.port.a": 1}})
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+
+		cfg, err = LoadLayered(LoadOptions{FS: filesystem, Overrides: map[string]any{"a..b": 1}})
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+
+		t.Setenv("TEST_LAYERED_PORT", "abc")
+		cfg, err = LoadLayered(LoadOptions{FS: filesystem, EnvVars: map[string]string{"server.port": "TEST_LAYERED_PORT"}})
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+
+		cfg, err = LoadLayered(LoadOptions{FS: filesystem, EnvVars: map[string]string{".port": "TEST_LAYERED_PORT"}})
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+
+		t.Setenv("GOYAVE_ENV", "bad")
+		cfg, err = LoadLayered(LoadOptions{FS: fstest.MapFS{".env.bad": &fstest.MapFile{Data: []byte("invalid")}}})
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+	})
+
+	t.Run("sources_simple_load", func(t *testing.T) {
+		cfg, err := LoadJSON(`{"app": {"name": "raw"}}`)
+		require.NoError(t, err)
+		source, ok := cfg.Source("app.name")
+		assert.True(t, ok)
+		assert.Equal(t, Source{Kind: SourceRaw}, source)
+
+		_, ok = cfg.Source("nonexisting")
+		assert.False(t, ok)
+
+		cfg = LoadDefault()
+		source, ok = cfg.Source("app.name")
+		assert.True(t, ok)
+		assert.Equal(t, Source{Kind: SourceDefault}, source)
+	})
+}
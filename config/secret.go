@@ -0,0 +1,186 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+)
+
+// Redacted the value replacing sensitive entries when the configuration
+// is logged, dumped or marshaled.
+const Redacted = "[REDACTED]"
+
+// SecretProvider resolves secret references. A secret reference is a value of a
+// sensitive config entry using the "<scheme>:<reference>" format, for example
+// "file:/run/secrets/db_password" or "env:DB_PASS".
+type SecretProvider interface {
+	// Resolve returns the secret identified by the given reference
+	// (without the scheme).
+	Resolve(reference string) (string, error)
+}
+
+// SecretProviderFunc an adapter allowing the use of an ordinary function as a `SecretProvider`.
+type SecretProviderFunc func(reference string) (string, error)
+
+// Resolve calls f(reference).
+func (f SecretProviderFunc) Resolve(reference string) (string, error) {
+	return f(reference)
+}
+
+// FileSecretProvider a `SecretProvider` reading secrets from files, such as
+// Docker or Kubernetes secrets. The reference is the path of the file.
+// Trailing line breaks are trimmed.
+type FileSecretProvider struct {
+	// FS the file system the secrets are read from. Defaults to the OS file system.
+	FS fs.FS
+}
+
+// Resolve reads the secret file at the given path.
+func (p *FileSecretProvider) Resolve(reference string) (string, error) {
+	filesystem := p.FS
+	if filesystem == nil {
+		filesystem = &osfs.FS{}
+	}
+	content, err := fs.ReadFile(filesystem, reference)
+	if err != nil {
+		return "", errors.New(err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// EnvSecretProvider a `SecretProvider` reading secrets from environment variables.
+// The reference is the name of the variable.
+type EnvSecretProvider struct{}
+
+// Resolve returns the value of the given environment variable.
+// Returns an error if the variable is not set.
+func (p *EnvSecretProvider) Resolve(reference string) (string, error) {
+	value, set := os.LookupEnv(reference)
+	if !set {
+		return "", errors.Errorf("environment variable %q is not set", reference)
+	}
+	return value, nil
+}
+
+var (
+	secretProviders = map[string]SecretProvider{
+		"file": &FileSecretProvider{},
+		"env":  &EnvSecretProvider{},
+	}
+	secretProvidersMu sync.RWMutex
+)
+
+// RegisterSecretProvider registers a `SecretProvider` for the given scheme
+// (e.g. "vault"). If a provider is already registered for this scheme, it is replaced.
+// By default, the "file" (`FileSecretProvider`) and "env" (`EnvSecretProvider`)
+// schemes are registered.
+//
+// Secret references are only resolved in sensitive entries (`Entry.Sensitive`), when the
+// configuration is validated. Values using a scheme that has no provider are left untouched.
+func RegisterSecretProvider(scheme string, provider SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	secretProviders[scheme] = provider
+}
+
+func getSecretProvider(scheme string) SecretProvider {
+	secretProvidersMu.RLock()
+	defer secretProvidersMu.RUnlock()
+	return secretProviders[scheme]
+}
+
+func (e *Entry) trySecretResolution(key string) error {
+	if !e.Sensitive {
+		return nil
+	}
+	str, ok := e.Value.(string)
+	if !ok {
+		return nil
+	}
+	scheme, reference, ok := strings.Cut(str, ":")
+	if !ok {
+		return nil
+	}
+	provider := getSecretProvider(scheme)
+	if provider == nil {
+		return nil
+	}
+
+	secret, err := provider.Resolve(reference)
+	if err != nil {
+		return errors.Errorf("%q: could not resolve secret %q: %w", key, str, err)
+	}
+	value, err := e.convertString(secret, key, fmt.Sprintf("secret %q", str))
+	if err != nil {
+		return err
+	}
+	e.Value = value
+	return nil
+}
+
+// RedactedMap returns a copy of the whole configuration as nested maps, like `Map()`,
+// with the values of the sensitive entries replaced with `Redacted`.
+// Unset and empty sensitive entries are not redacted so it is
+// still possible to tell if they are defined.
+func (c *Config) RedactedMap() map[string]any {
+	return c.current().config.toRedactedMap()
+}
+
+func (o object) toRedactedMap() map[string]any {
+	m := make(map[string]any, len(o))
+	for k, v := range o {
+		if category, ok := v.(object); ok {
+			m[k] = category.toRedactedMap()
+			continue
+		}
+		entry := v.(*Entry)
+		if entry.Sensitive && !isEmptyValue(entry.Value) {
+			m[k] = Redacted
+			continue
+		}
+		m[k] = entry.Value
+	}
+	return m
+}
+
+func isEmptyValue(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice:
+		return v.Len() == 0
+	}
+	return false
+}
+
+// String returns the JSON representation of the configuration, with
+// the sensitive entries redacted.
+func (c *Config) String() string {
+	b, err := c.MarshalJSON()
+	if err != nil {
+		return fmt.Sprintf("%v", c.RedactedMap())
+	}
+	return string(b)
+}
+
+// MarshalJSON marshals the configuration as nested JSON objects,
+// with the sensitive entries redacted.
+func (c *Config) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.RedactedMap())
+}
+
+// LogValue returns the configuration with the sensitive entries redacted,
+// so it can be logged safely with `slog`.
+func (c *Config) LogValue() slog.Value {
+	return slog.AnyValue(c.RedactedMap())
+}
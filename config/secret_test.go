@@ -0,0 +1,163 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSecretProvider(t *testing.T) {
+	provider := &FileSecretProvider{FS: fstest.MapFS{
+		"run/secrets/db_password": &fstest.MapFile{Data: []byte("p4ssw0rd\n")},
+		"run/secrets/multiline":   &fstest.MapFile{Data: []byte("line1\nline2\r\n\n")},
+	}}
+
+	secret, err := provider.Resolve("run/secrets/db_password")
+	require.NoError(t, err)
+	assert.Equal(t, "p4ssw0rd", secret)
+
+	secret, err = provider.Resolve("run/secrets/multiline")
+	require.NoError(t, err)
+	assert.Equal(t, "line1\nline2", secret)
+
+	_, err = provider.Resolve("run/secrets/nonexisting")
+	require.Error(t, err)
+
+	// Defaults to the OS file system
+	secret, err = (&FileSecretProvider{}).Resolve("../resources/test_file.txt")
+	require.NoError(t, err)
+	assert.NotEmpty(t, secret)
+}
+
+func TestEnvSecretProvider(t *testing.T) {
+	t.Setenv("TEST_SECRET", "secret")
+	unsetEnv(t, "TEST_SECRET_UNSET")
+
+	secret, err := (&EnvSecretProvider{}).Resolve("TEST_SECRET")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", secret)
+
+	_, err = (&EnvSecretProvider{}).Resolve("TEST_SECRET_UNSET")
+	require.Error(t, err)
+}
+
+func TestSecretResolution(t *testing.T) {
+	RegisterSecretProvider("test", &FileSecretProvider{FS: fstest.MapFS{
+		"password": &fstest.MapFile{Data: []byte("p4ssw0rd\n")},
+		"port":     &fstest.MapFile{Data: []byte("5432")},
+		"keys":     &fstest.MapFile{Data: []byte("key1,key2")},
+		"invalid":  &fstest.MapFile{Data: []byte("not an int")},
+	}})
+	RegisterSecretProvider("upper", SecretProviderFunc(func(reference string) (string, error) {
+		return reference + "-UPPER", nil
+	}))
+	t.Cleanup(func() {
+		secretProvidersMu.Lock()
+		delete(secretProviders, "test")
+		delete(secretProviders, "upper")
+		secretProvidersMu.Unlock()
+	})
+	t.Setenv("TEST_DB_PASS", "from-env")
+
+	newEntry := func(value any, kind reflect.Kind, isSlice, sensitive bool) *Entry {
+		return &Entry{Value: value, AuthorizedValues: []any{}, Type: kind, IsSlice: isSlice, Sensitive: sensitive}
+	}
+
+	cases := []struct {
+		entry   *Entry
+		want    any
+		desc    string
+		wantErr bool
+	}{
+		{desc: "file", entry: newEntry("test:password", reflect.String, false, true), want: "p4ssw0rd"},
+		{desc: "env", entry: newEntry("env:TEST_DB_PASS", reflect.String, false, true), want: "from-env"},
+		{desc: "func", entry: newEntry("upper:a", reflect.String, false, true), want: "a-UPPER"},
+		{desc: "int", entry: newEntry("test:port", reflect.Int, false, true), want: 5432},
+		{desc: "slice", entry: newEntry("test:keys", reflect.String, true, true), want: []string{"key1", "key2"}},
+		{desc: "not_sensitive", entry: newEntry("test:password", reflect.String, false, false), want: "test:password"},
+		{desc: "unknown_scheme", entry: newEntry("unknown:password", reflect.String, false, true), want: "unknown:password"},
+		{desc: "no_scheme", entry: newEntry("password", reflect.String, false, true), want: "password"},
+		{desc: "not_found", entry: newEntry("test:nonexisting", reflect.String, false, true), wantErr: true},
+		{desc: "env_not_set", entry: newEntry("env:TEST_DB_PASS_UNSET", reflect.String, false, true), wantErr: true},
+		{desc: "conversion_error", entry: newEntry("test:invalid", reflect.Int, false, true), wantErr: true},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			err := c.entry.validate("key")
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.want, c.entry.Value)
+		})
+	}
+
+	t.Run("error_does_not_leak_secret", func(t *testing.T) {
+		err := newEntry("test:invalid", reflect.Int, false, true).validate("key")
+		require.Error(t, err)
+		assert.Equal(t, `"key" could not be converted to int from secret "test:invalid"`, err.Error())
+	})
+
+	t.Run("load", func(t *testing.T) {
+		cfg, err := LoadJSON(`{"database": {"password": "test:password"}}`)
+		require.NoError(t, err)
+		assert.Equal(t, "p4ssw0rd", cfg.GetString("database.password"))
+	})
+}
+
+func TestRedaction(t *testing.T) {
+	cfg, err := LoadJSON(`{"database": {"password": "p4ssw0rd"}, "app": {"key": "app-key"}}`)
+	require.NoError(t, err)
+
+	cfg.Set("custom", "value")
+
+	redacted := cfg.RedactedMap()
+	assert.Equal(t, Redacted, redacted["database"].(map[string]any)["password"])
+	assert.Equal(t, Redacted, redacted["app"].(map[string]any)["key"])
+	assert.Equal(t, []string{}, redacted["app"].(map[string]any)["previousKeys"]) // Empty values are not redacted
+	assert.Equal(t, "127.0.0.1", redacted["database"].(map[string]any)["host"])
+	assert.Equal(t, "value", redacted["custom"])
+
+	// The config and Map() are not affected
+	assert.Equal(t, "p4ssw0rd", cfg.GetString("database.password"))
+	assert.Equal(t, "p4ssw0rd", cfg.Map()["database"].(map[string]any)["password"])
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(cfg)
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), "p4ssw0rd")
+		assert.NotContains(t, string(b), "app-key")
+
+		var decoded map[string]any
+		require.NoError(t, json.Unmarshal(b, &decoded))
+		assert.Equal(t, Redacted, decoded["database"].(map[string]any)["password"])
+	})
+
+	t.Run("string", func(t *testing.T) {
+		str := fmt.Sprintf("%v", cfg)
+		assert.NotContains(t, str, "p4ssw0rd")
+		assert.Contains(t, str, Redacted)
+
+		cfg := &Config{}
+		cfg.state.Store(&configState{config: object{"invalid": &Entry{Value: func() {}}}})
+		assert.Contains(t, cfg.String(), "invalid")
+	})
+
+	t.Run("slog", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		logger := slog.New(slog.NewJSONHandler(buf, nil))
+		logger.Info("config", "config", cfg)
+		assert.NotContains(t, buf.String(), "p4ssw0rd")
+		assert.Contains(t, buf.String(), Redacted)
+	})
+}
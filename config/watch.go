@@ -0,0 +1,315 @@
+package config
+
+import (
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// DefaultWatchInterval the default interval at which a `Watcher` checks
+// if the config files changed.
+const DefaultWatchInterval = 2 * time.Second
+
+// Change describes a config entry whose value changed after a reload.
+// `Old` is `nil` if the entry didn't exist before, `New` is `nil` if
+// the entry doesn't exist anymore.
+type Change struct {
+	Old any
+	New any
+	Key string
+}
+
+// ChangeFunc a function called when a config entry changed after a reload.
+type ChangeFunc func(change Change)
+
+type subscriber struct {
+	fn     ChangeFunc
+	prefix string
+}
+
+func (s *subscriber) matches(key string) bool {
+	return s.prefix == "" || key == s.prefix || strings.HasPrefix(key, s.prefix+".")
+}
+
+// OnChange registers a function called when the value of an entry matching the given
+// key prefix changes after the configuration is reloaded. The prefix is a dot-separated
+// path matching either an entry or all the entries of a category: "server" matches
+// "server.port" and "server.proxy.host". An empty prefix matches all entries.
+//
+//	cfg.OnChange("server.maxUploadSize", func(change config.Change) {
+//		// ...
+//	})
+//
+// The function is called once per changed entry, after the new configuration is in use.
+// Returns a function removing the subscription.
+func (c *Config) OnChange(prefix string, fn ChangeFunc) func() {
+	s := &subscriber{prefix: prefix, fn: fn}
+	c.subscribersMu.Lock()
+	c.subscribers = append(c.subscribers, s)
+	c.subscribersMu.Unlock()
+	return func() {
+		c.subscribersMu.Lock()
+		defer c.subscribersMu.Unlock()
+		for i, sub := range c.subscribers {
+			if sub == s {
+				c.subscribers = append(c.subscribers[:i], c.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// reloadConfig loads a new configuration from the same sources as the current one,
+// then atomically replaces the current configuration and notifies the subscribers.
+// If the new configuration is invalid, the current one is kept and an error is returned.
+// Changes to non-reloadable entries are rejected: they keep their current value and a
+// warning is logged.
+func (c *Config) reloadConfig(logger *slog.Logger) error {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	if c.reload == nil {
+		return errors.New("config cannot be reloaded")
+	}
+	newConfig, err := c.reload()
+	if err != nil {
+		return err
+	}
+
+	current := c.current()
+	next := newConfig.current()
+	currentEntries := map[string]*Entry{}
+	flattenEntries(current.config, "", currentEntries)
+	nextEntries := map[string]*Entry{}
+	flattenEntries(next.config, "", nextEntries)
+
+	keys := make([]string, 0, len(nextEntries))
+	for k := range nextEntries {
+		keys = append(keys, k)
+	}
+	for k := range currentEntries {
+		if _, ok := nextEntries[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := []Change{}
+	for _, key := range keys {
+		change := Change{Key: key}
+		currentEntry, exists := currentEntries[key]
+		if exists {
+			change.Old = currentEntry.Value
+		}
+		nextEntry, ok := nextEntries[key]
+		if ok {
+			change.New = nextEntry.Value
+		}
+		if reflect.DeepEqual(change.Old, change.New) {
+			continue
+		}
+		if exists && ok && currentEntry.NonReloadable {
+			logger.Warn("config: change of non-reloadable entry rejected", slog.String("key", key))
+			nextEntry.Value = currentEntry.Value
+			next.sources[key] = current.sources[key]
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	c.state.Store(next)
+
+	c.subscribersMu.RLock()
+	subscribers := append([]*subscriber{}, c.subscribers...)
+	c.subscribersMu.RUnlock()
+	for _, change := range changes {
+		for _, s := range subscribers {
+			if s.matches(change.Key) {
+				s.fn(change)
+			}
+		}
+	}
+	return nil
+}
+
+func flattenEntries(o object, prefix string, entries map[string]*Entry) {
+	for k, v := range o {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if category, ok := v.(object); ok {
+			flattenEntries(category, key, entries)
+			continue
+		}
+		entries[key] = v.(*Entry)
+	}
+}
+
+// WatchOptions options for `Config.Watch()`.
+type WatchOptions struct {
+	// Logger used to report reload errors and rejected changes.
+	// Defaults to `slog.Default()`.
+	Logger *slog.Logger
+
+	// Interval the interval at which the config files are checked for changes.
+	// Defaults to `DefaultWatchInterval`. A negative value disables file watching.
+	Interval time.Duration
+
+	// DisableSignal if true, the configuration is not reloaded when the
+	// process receives SIGHUP.
+	DisableSignal bool
+}
+
+// Watcher reloads a configuration when its files change or when the process
+// receives SIGHUP. Use `Config.Watch()` to create a `Watcher`.
+type Watcher struct {
+	config  *Config
+	logger  *slog.Logger
+	signals chan os.Signal
+	done    chan struct{}
+	stamps  []fileStamp
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+// Watch starts watching the files the configuration was loaded from, reloading the
+// configuration when they change or when the process receives SIGHUP.
+//
+// The new configuration is loaded from the same sources (files, environment variables,
+// overrides) and validated against the registered entries. If it is valid, it atomically
+// replaces the current configuration, which can therefore safely be used by a running server.
+// Otherwise, the current configuration is kept and the error is logged.
+// Values modified with `Config.Set()` are lost on reload.
+//
+// The ".env" files are watched too: the env variables they define are updated (or unset
+// if they were removed from the files) before the configuration is reloaded. Variables
+// already set in the environment when the files were first loaded keep precedence.
+//
+// Changes to non-reloadable entries (`Entry.NonReloadable`), such as "server.port", are
+// rejected with a logged warning: these entries keep their current value. This is also the
+// case of "server.domain" and "server.proxy.*" because the server's base URL and proxy base URL
+// are computed once when the server is created.
+// The subscribers registered with `Config.OnChange()` are notified of the other changes.
+//
+// Call `Watcher.Close()` to stop watching.
+func (c *Config) Watch(opts WatchOptions) *Watcher {
+	w := &Watcher{
+		config: c,
+		logger: opts.Logger,
+		done:   make(chan struct{}),
+	}
+	if w.logger == nil {
+		w.logger = slog.Default()
+	}
+	interval := opts.Interval
+	if interval == 0 {
+		interval = DefaultWatchInterval
+	}
+	if !opts.DisableSignal {
+		w.signals = make(chan os.Signal, 1)
+		signal.Notify(w.signals, syscall.SIGHUP)
+	}
+	w.stamps = w.stat()
+
+	w.wg.Add(1)
+	go w.watch(interval)
+	return w
+}
+
+func (w *Watcher) watch(interval time.Duration) {
+	defer w.wg.Done()
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.signals:
+			w.reload()
+		case <-tick:
+			if w.changed() {
+				w.reload()
+			}
+		}
+	}
+}
+
+func (w *Watcher) reload() {
+	if err := w.Reload(); err != nil {
+		w.logger.Error("config: reload failed", slog.Any("error", err))
+	}
+}
+
+// Reload the configuration immediately. If the new configuration is invalid,
+// the current one is kept and an error is returned.
+func (w *Watcher) Reload() error {
+	err := w.config.reloadConfig(w.logger)
+	stamps := w.stat()
+	w.config.reloadMu.Lock()
+	w.stamps = stamps
+	w.config.reloadMu.Unlock()
+	return err
+}
+
+// changed returns true if one of the config files changed since the last reload.
+func (w *Watcher) changed() bool {
+	stamps := w.stat()
+	w.config.reloadMu.Lock()
+	defer w.config.reloadMu.Unlock()
+	if len(stamps) != len(w.stamps) {
+		return true
+	}
+	for i, s := range stamps {
+		previous := w.stamps[i]
+		if s.exists != previous.exists || s.size != previous.size || !s.modTime.Equal(previous.modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Watcher) stat() []fileStamp {
+	files := w.config.current().files
+	stamps := make([]fileStamp, 0, len(files))
+	for _, f := range files {
+		stamp := fileStamp{}
+		if info, err := fs.Stat(f.fs, f.path); err == nil {
+			stamp.exists = true
+			stamp.modTime = info.ModTime()
+			stamp.size = info.Size()
+		}
+		stamps = append(stamps, stamp)
+	}
+	return stamps
+}
+
+// Close stops watching. The configuration is not reloaded anymore.
+func (w *Watcher) Close() {
+	w.once.Do(func() {
+		if w.signals != nil {
+			signal.Stop(w.signals)
+		}
+		close(w.done)
+		w.wg.Wait()
+	})
+}
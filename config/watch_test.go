@@ -0,0 +1,253 @@
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeWatchedConfig(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestWatch(t *testing.T) {
+	newLogger := func() (*slog.Logger, *bytes.Buffer) {
+		buf := &bytes.Buffer{}
+		return slog.New(slog.NewTextHandler(buf, nil)), buf
+	}
+
+	t.Run("reload", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		writeWatchedConfig(t, path, `{"server": {"port": 8080, "maxUploadSize": 10, "domain": "example.org", "proxy": {"host": "proxy.example.org"}}, "custom": "a"}`)
+		cfg, err := LoadFrom(path)
+		require.NoError(t, err)
+
+		changes := []Change{}
+		cfg.OnChange("server.maxUploadSize", func(change Change) {
+			changes = append(changes, change)
+		})
+		serverChanges := []Change{}
+		cfg.OnChange("server", func(change Change) {
+			serverChanges = append(serverChanges, change)
+		})
+		allChanges := []Change{}
+		cfg.OnChange("", func(change Change) {
+			allChanges = append(allChanges, change)
+		})
+		cfg.OnChange("serv", func(_ Change) {
+			assert.Fail(t, "prefix should match whole key segments")
+		})
+
+		logger, buf := newLogger()
+		w := cfg.Watch(WatchOptions{Logger: logger, Interval: -1, DisableSignal: true})
+		defer w.Close()
+
+		writeWatchedConfig(t, path, `{"server": {"port": 9090, "maxUploadSize": 20, "domain": "example.com", "proxy": {"host": "proxy.example.com"}}, "added": true}`)
+		require.NoError(t, w.Reload())
+
+		assert.Equal(t, 20.0, cfg.GetFloat("server.maxUploadSize"))
+		assert.Equal(t, 8080, cfg.GetInt("server.port")) // Non-reloadable
+		assert.Equal(t, "example.org", cfg.GetString("server.domain"))
+		assert.Equal(t, "proxy.example.org", cfg.GetString("server.proxy.host"))
+		source, ok := cfg.Source("server.port")
+		assert.True(t, ok)
+		assert.Equal(t, Source{Name: path, Kind: SourceFile}, source)
+		assert.True(t, cfg.GetBool("added"))
+		assert.False(t, cfg.Has("custom"))
+		assert.Contains(t, buf.String(), "non-reloadable")
+		assert.Contains(t, buf.String(), "server.port")
+
+		assert.Equal(t, []Change{{Key: "server.maxUploadSize", Old: 10.0, New: 20.0}}, changes)
+		assert.Equal(t, changes, serverChanges)
+		assert.Equal(t, []Change{
+			{Key: "added", Old: nil, New: true},
+			{Key: "custom", Old: "a", New: nil},
+			{Key: "server.maxUploadSize", Old: 10.0, New: 20.0},
+		}, allChanges)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		writeWatchedConfig(t, path, `{"server": {"maxUploadSize": 10}}`)
+		cfg, err := LoadFrom(path)
+		require.NoError(t, err)
+
+		cfg.OnChange("", func(_ Change) {
+			assert.Fail(t, "subscribers should not be notified")
+		})
+
+		logger, _ := newLogger()
+		w := cfg.Watch(WatchOptions{Logger: logger, Interval: -1, DisableSignal: true})
+		defer w.Close()
+
+		writeWatchedConfig(t, path, `{"server": {"maxUploadSize": "not a number"}}`)
+		require.Error(t, w.Reload())
+		assert.Equal(t, 10.0, cfg.GetFloat("server.maxUploadSize"))
+
+		writeWatchedConfig(t, path, `{"server": `)
+		require.Error(t, w.Reload())
+		assert.Equal(t, 10.0, cfg.GetFloat("server.maxUploadSize"))
+	})
+
+	t.Run("not_reloadable", func(t *testing.T) {
+		cfg := &Config{}
+		cfg.state.Store(&configState{config: object{}})
+		logger, _ := newLogger()
+		w := cfg.Watch(WatchOptions{Logger: logger, Interval: -1, DisableSignal: true})
+		defer w.Close()
+		require.Error(t, w.Reload())
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		cfg, err := LoadJSON(`{}`)
+		require.NoError(t, err)
+
+		unsubscribe := cfg.OnChange("", func(_ Change) {})
+		unsubscribe2 := cfg.OnChange("", func(_ Change) {})
+		require.Len(t, cfg.subscribers, 2)
+		unsubscribe()
+		require.Len(t, cfg.subscribers, 1)
+		unsubscribe() // Calling it twice has no effect
+		require.Len(t, cfg.subscribers, 1)
+		unsubscribe2()
+		assert.Empty(t, cfg.subscribers)
+	})
+
+	t.Run("file_change", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		writeWatchedConfig(t, path, `{"server": {"maxUploadSize": 10}}`)
+		cfg, err := LoadFrom(path)
+		require.NoError(t, err)
+
+		changed := make(chan Change, 1)
+		cfg.OnChange("server.maxUploadSize", func(change Change) {
+			changed <- change
+		})
+
+		logger, _ := newLogger()
+		w := cfg.Watch(WatchOptions{Logger: logger, Interval: 5 * time.Millisecond, DisableSignal: true})
+		defer w.Close()
+
+		writeWatchedConfig(t, path, `{"server": {"maxUploadSize": 200}}`)
+		future := time.Now().Add(time.Hour)
+		require.NoError(t, os.Chtimes(path, future, future))
+
+		select {
+		case change := <-changed:
+			assert.Equal(t, Change{Key: "server.maxUploadSize", Old: 10.0, New: 200.0}, change)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "config was not reloaded")
+		}
+		assert.Equal(t, 200.0, cfg.GetFloat("server.maxUploadSize"))
+	})
+
+	t.Run("file_change_invalid", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		writeWatchedConfig(t, path, `{"server": {"maxUploadSize": 10}}`)
+		cfg, err := LoadFrom(path)
+		require.NoError(t, err)
+
+		buf := &syncBuffer{}
+		logger := slog.New(slog.NewTextHandler(buf, nil))
+		w := cfg.Watch(WatchOptions{Logger: logger, Interval: 5 * time.Millisecond, DisableSignal: true})
+		defer w.Close()
+
+		require.NoError(t, os.Remove(path))
+		assert.Eventually(t, func() bool {
+			return bytes.Contains(buf.Bytes(), []byte("reload failed"))
+		}, 5*time.Second, 5*time.Millisecond)
+		assert.Equal(t, 10.0, cfg.GetFloat("server.maxUploadSize"))
+	})
+
+	t.Run("dotenv", func(t *testing.T) {
+		unsetEnv(t, "GOYAVE_TEST_WATCH_UPLOAD", "GOYAVE_TEST_WATCH_REMOVED", "GOYAVE_TEST_WATCH_PROCESS")
+		t.Setenv("GOYAVE_TEST_WATCH_PROCESS", "from-process")
+		t.Setenv("GOYAVE_ENV", "")
+		filesystem := fstest.MapFS{
+			"config.json": &fstest.MapFile{Data: []byte(`{"server": {"maxUploadSize": "${GOYAVE_TEST_WATCH_UPLOAD}"}}`)},
+			".env":        &fstest.MapFile{Data: []byte("GOYAVE_TEST_WATCH_UPLOAD=10\nGOYAVE_TEST_WATCH_REMOVED=a\nGOYAVE_TEST_WATCH_PROCESS=from-env")},
+		}
+		cfg, err := LoadLayered(LoadOptions{FS: filesystem})
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, cfg.GetFloat("server.maxUploadSize"))
+
+		logger, _ := newLogger()
+		w := cfg.Watch(WatchOptions{Logger: logger, Interval: -1, DisableSignal: true})
+		defer w.Close()
+
+		filesystem[".env"] = &fstest.MapFile{Data: []byte("GOYAVE_TEST_WATCH_UPLOAD=20\nGOYAVE_TEST_WATCH_PROCESS=from-env")}
+		assert.True(t, w.changed())
+		require.NoError(t, w.Reload())
+		assert.False(t, w.changed())
+
+		assert.Equal(t, 20.0, cfg.GetFloat("server.maxUploadSize"))
+		_, set := os.LookupEnv("GOYAVE_TEST_WATCH_REMOVED")
+		assert.False(t, set)
+		assert.Equal(t, "from-process", os.Getenv("GOYAVE_TEST_WATCH_PROCESS"))
+	})
+
+	t.Run("signal", func(t *testing.T) {
+		cfg, err := LoadJSON(`{"server": {"maxUploadSize": 10}}`)
+		require.NoError(t, err)
+
+		changed := make(chan Change, 1)
+		cfg.OnChange("server", func(change Change) {
+			changed <- change
+		})
+		cfg.reload = func() (*Config, error) {
+			return LoadJSON(`{"server": {"maxUploadSize": 20}}`)
+		}
+
+		logger, _ := newLogger()
+		w := cfg.Watch(WatchOptions{Logger: logger, Interval: -1})
+		defer w.Close()
+
+		w.signals <- syscall.SIGHUP
+		select {
+		case change := <-changed:
+			assert.Equal(t, Change{Key: "server.maxUploadSize", Old: 10.0, New: 20.0}, change)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "config was not reloaded")
+		}
+	})
+
+	t.Run("close", func(t *testing.T) {
+		cfg, err := LoadJSON(`{}`)
+		require.NoError(t, err)
+		w := cfg.Watch(WatchOptions{})
+		w.Close()
+		w.Close() // Calling it twice has no effect
+		select {
+		case <-w.done:
+		default:
+			assert.Fail(t, "watcher should be stopped")
+		}
+	})
+}
+
+type syncBuffer struct {
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte{}, b.buf.Bytes()...)
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// decodeFunc decodes a configuration source into the given object.
+type decodeFunc func(r io.Reader, o *object) error
+
+// getDecoder returns the decoder matching the extension of the given file.
+// Files with the ".yml" or ".yaml" extension are decoded as YAML, all other
+// files are decoded as JSON.
+func getDecoder(file string) decodeFunc {
+	switch strings.ToLower(path.Ext(file)) {
+	case ".yml", ".yaml":
+		return decodeYAML
+	default:
+		return decodeJSON
+	}
+}
+
+func decodeJSON(r io.Reader, o *object) error {
+	return json.NewDecoder(r).Decode(o)
+}
+
+// decodeYAML decodes a YAML document. The result is normalized so it
+// has the same representation as a decoded JSON document: categories
+// are `map[string]any`, arrays are `[]any` and all numbers are `float64`.
+// Timestamps are kept as strings. This way, YAML configurations are validated
+// exactly like JSON ones. An empty document results in an empty object.
+func decodeYAML(r io.Reader, o *object) error {
+	var document yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+	value, err := convertYAMLNode(&document)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return nil
+	}
+	m, ok := value.(map[string]any)
+	if !ok {
+		return errors.Errorf("line %d: the YAML document must be a mapping", document.Line)
+	}
+	for k, v := range m {
+		(*o)[k] = v
+	}
+	return nil
+}
+
+func convertYAMLNode(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return convertYAMLNode(node.Content[0])
+	case yaml.AliasNode:
+		return convertYAMLNode(node.Alias)
+	case yaml.MappingNode:
+		m := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if key.Kind != yaml.ScalarNode {
+				return nil, errors.Errorf("line %d: mapping keys must be scalars", key.Line)
+			}
+			value, err := convertYAMLNode(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			if key.ShortTag() == "!!merge" {
+				if err := mergeYAML(m, value, key.Line); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			m[key.Value] = value
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		s := make([]any, 0, len(node.Content))
+		for _, n := range node.Content {
+			value, err := convertYAMLNode(n)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, value)
+		}
+		return s, nil
+	}
+
+	// Scalar
+	if node.ShortTag() == "!!timestamp" {
+		return node.Value, nil
+	}
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case nil, string, bool, float64:
+		return v, nil
+	default:
+		return nil, errors.Errorf("line %d: unsupported YAML value of type %T", node.Line, v)
+	}
+}
+
+// mergeYAML adds the entries of the merged mapping(s) to the given map.
+// Explicit keys always take precedence over merged keys.
+func mergeYAML(m map[string]any, merged any, line int) error {
+	maps := []any{merged}
+	if s, ok := merged.([]any); ok {
+		maps = s
+	}
+	for _, merge := range maps {
+		mergeMap, ok := merge.(map[string]any)
+		if !ok {
+			return errors.Errorf("line %d: merge keys only support mappings", line)
+		}
+		for k, v := range mergeMap {
+			if _, exists := m[k]; !exists {
+				m[k] = v
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindConfigFile(t *testing.T) {
+	filesystem := fstest.MapFS{
+		"config.json":            &fstest.MapFile{},
+		"config.test.yml":        &fstest.MapFile{},
+		"config.production.yaml": &fstest.MapFile{},
+		"config.dir.json":        &fstest.MapFile{Mode: fs.ModeDir},
+	}
+
+	assert.Equal(t, "config.json", findConfigFile(filesystem, "config.json"))
+	assert.Equal(t, "config.test.yml", findConfigFile(filesystem, "config.test.json"))
+	assert.Equal(t, "config.production.yaml", findConfigFile(filesystem, "config.production.json"))
+	assert.Equal(t, "config.staging.json", findConfigFile(filesystem, "config.staging.json"))
+	assert.Equal(t, "config.dir.json", findConfigFile(filesystem, "config.dir.json"))
+	assert.Equal(t, "custom.toml", findConfigFile(filesystem, "custom.toml"))
+}
+
+func TestLoadYAML(t *testing.T) {
+	t.Run("Load", func(t *testing.T) {
+		t.Setenv("GOYAVE_ENV", "test_yaml")
+		cfg, err := Load()
+		require.NoError(t, err)
+
+		assert.Equal(t, "root level content", cfg.Get("rootLevel"))
+		assert.Equal(t, "test", cfg.Get("app.environment"))
+		assert.Equal(t, 1234, cfg.Get("server.port"))
+		assert.InEpsilon(t, 20.0, cfg.Get("server.maxUploadSize"), 0)
+		assert.Equal(t, "https", cfg.Get("server.proxy.protocol"))
+
+		// Default config also loaded
+		assert.Equal(t, "goyave", cfg.Get("app.name"))
+	})
+
+	t.Run("LoadFrom", func(t *testing.T) {
+		cfg, err := LoadFrom("../resources/custom_config.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, "value", cfg.Get("custom-entry"))
+	})
+
+	t.Run("LoadYAML", func(t *testing.T) {
+		cfg, err := LoadYAML(`
+custom-entry: value
+category:
+  entry: 123
+  array: [a, b]
+  ints: [1, 2]
+  nonStringKeys:
+    1: one
+    true: enabled
+  date: 2001-12-14
+  defaults: &defaults
+    host: localhost
+    port: 80
+  merged:
+    <<: *defaults
+    port: 8080
+`)
+		require.NoError(t, err)
+
+		cat, ok := cfg.current().config["category"].(object)
+		require.True(t, ok)
+
+		// Same representation as JSON
+		expected := &Entry{
+			Value:            123.0,
+			AuthorizedValues: []any{},
+			Type:             reflect.Float64,
+			IsSlice:          false,
+		}
+		assert.Equal(t, expected, cat["entry"])
+		expected = &Entry{
+			Value:            []any{"a", "b"},
+			AuthorizedValues: []any{},
+			Type:             reflect.Interface,
+			IsSlice:          true,
+		}
+		assert.Equal(t, expected, cat["array"])
+		assert.Equal(t, []any{1.0, 2.0}, cfg.Get("category.ints"))
+		assert.Equal(t, "one", cfg.Get("category.nonStringKeys.1"))
+		assert.Equal(t, "enabled", cfg.Get("category.nonStringKeys.true"))
+		assert.Equal(t, "2001-12-14", cfg.Get("category.date"))
+		assert.Equal(t, map[string]any{"host": "localhost", "port": 8080.0}, cfg.Map()["category"].(map[string]any)["merged"])
+	})
+
+	t.Run("LoadYAML Empty", func(t *testing.T) {
+		cfg, err := LoadYAML("")
+		require.NoError(t, err)
+		assert.Equal(t, "goyave", cfg.Get("app.name"))
+	})
+
+	t.Run("LoadYAML Invalid", func(t *testing.T) {
+		cfg, err := LoadYAML("a: [unclosed")
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+
+		cfg, err = LoadYAML("- not\n- an\n- object")
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+
+		cfg, err = LoadYAML("? [a, b]\n: c")
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+
+		cfg, err = LoadYAML("a:\n  <<: not a mapping")
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		cfg, err := LoadYAML("app:\n  name: 123")
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+		assert.Equal(t, "Config error: \n\t- \"app.name\" type must be string", err.Error())
+
+		cfg, err = LoadYAML("server:\n  proxy:\n    protocol: ftp")
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+
+		cfg, err = LoadYAML("server:\n  port: 12.5")
+		assert.Nil(t, cfg)
+		require.Error(t, err)
+	})
+
+	t.Run("Env Variables", func(t *testing.T) {
+		t.Setenv("TEST_YAML_PORT", "4567")
+		cfg, err := LoadYAML("server:\n  port: ${TEST_YAML_PORT}")
+		require.NoError(t, err)
+		assert.Equal(t, 4567, cfg.Get("server.port"))
+	})
+}
@@ -0,0 +1,209 @@
+package goyave
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+
+	errorutil "goyave.dev/goyave/v5/util/errors"
+)
+
+// MaxCookieSize the default maximum size of a cookie (name and value) in bytes.
+const MaxCookieSize = 4096
+
+var (
+	// ErrCookieTampered returned when a signed or encrypted cookie value is malformed,
+	// has been modified or has not been produced with any of the known keys.
+	ErrCookieTampered = errors.New("cookie value is invalid or has been tampered with")
+
+	// ErrCookieTooLarge returned when an encoded cookie exceeds the maximum size.
+	ErrCookieTooLarge = errors.New("cookie exceeds the maximum size")
+)
+
+type cookieKey struct {
+	aead    cipher.AEAD
+	hmacKey []byte
+}
+
+// CookieCodec signs (HMAC-SHA256) or encrypts (AES-256-GCM) cookie values.
+// The cookie name is bound to the value, so a value cannot be reused in another cookie.
+//
+// The codec supports key rotation: values are always encoded with the first key, and
+// can be decoded with any of the keys. Decoding methods report when a value was
+// decoded with a previous key so it can be encoded again with the current key.
+type CookieCodec struct {
+	keys []cookieKey
+
+	// MaxSize the maximum size of an encoded cookie (name and value) in bytes.
+	// Defaults to `MaxCookieSize`.
+	MaxSize int
+}
+
+// NewCookieCodec create a new `CookieCodec` using the given keys. The first key is the
+// current key, the others are previous keys only used for decoding.
+// Panics if no key is given or if a key is empty.
+func NewCookieCodec(keys ...string) *CookieCodec {
+	if len(keys) == 0 {
+		panic(errorutil.NewSkip("NewCookieCodec: at least one key is required", 3))
+	}
+	c := &CookieCodec{keys: make([]cookieKey, 0, len(keys)), MaxSize: MaxCookieSize}
+	for _, key := range keys {
+		if key == "" {
+			panic(errorutil.NewSkip("NewCookieCodec: empty key", 3))
+		}
+		encryptionKey := sha256.Sum256([]byte("goyave.cookie.encryption:" + key))
+		block, err := aes.NewCipher(encryptionKey[:])
+		if err != nil {
+			panic(errorutil.New(err))
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			panic(errorutil.New(err))
+		}
+		hmacKey := sha256.Sum256([]byte("goyave.cookie.signature:" + key))
+		c.keys = append(c.keys, cookieKey{aead: aead, hmacKey: hmacKey[:]})
+	}
+	return c
+}
+
+// CookieCodec returns a new `CookieCodec` using the application key ("app.key" config entry)
+// as current key and the "app.previousKeys" config entry as previous keys.
+// Panics if the application key is not set.
+func (s *Server) CookieCodec() *CookieCodec {
+	key := s.config.GetString("app.key")
+	if key == "" {
+		panic(errorutil.NewSkip("cannot create cookie codec: the \"app.key\" config entry is not set", 3))
+	}
+	keys := []string{key}
+	for _, k := range s.config.GetStringSlice("app.previousKeys") {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return NewCookieCodec(keys...)
+}
+
+func (k cookieKey) mac(name, value string) []byte {
+	mac := hmac.New(sha256.New, k.hmacKey)
+	mac.Write([]byte(name + "=" + value))
+	return mac.Sum(nil)
+}
+
+// Sign returns the given value followed by its signature.
+func (c *CookieCodec) Sign(name, value string) string {
+	return value + "." + base64.RawURLEncoding.EncodeToString(c.keys[0].mac(name, value))
+}
+
+// Verify checks the signature of the given signed value and returns the original value.
+// `rotated` is `true` if the value was signed with a previous key.
+// Returns `ErrCookieTampered` if the signature is invalid.
+func (c *CookieCodec) Verify(name, signed string) (value string, rotated bool, err error) {
+	i := strings.LastIndexByte(signed, '.')
+	if i < 0 {
+		return "", false, ErrCookieTampered
+	}
+	signature, decodeErr := base64.RawURLEncoding.DecodeString(signed[i+1:])
+	if decodeErr != nil {
+		return "", false, ErrCookieTampered
+	}
+	value = signed[:i]
+	for n, key := range c.keys {
+		if hmac.Equal(signature, key.mac(name, value)) {
+			return value, n > 0, nil
+		}
+	}
+	return "", false, ErrCookieTampered
+}
+
+// Encrypt returns the given value encrypted and encoded in base64.
+func (c *CookieCodec) Encrypt(name, value string) string {
+	aead := c.keys[0].aead
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		panic(errorutil.New(err))
+	}
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(value), []byte(name)))
+}
+
+// Decrypt returns the original value of the given encrypted value.
+// `rotated` is `true` if the value was encrypted with a previous key.
+// Returns `ErrCookieTampered` if the value cannot be decrypted.
+func (c *CookieCodec) Decrypt(name, encrypted string) (value string, rotated bool, err error) {
+	b, decodeErr := base64.RawURLEncoding.DecodeString(encrypted)
+	if decodeErr != nil {
+		return "", false, ErrCookieTampered
+	}
+	for n, key := range c.keys {
+		nonceSize := key.aead.NonceSize()
+		if len(b) < nonceSize {
+			return "", false, ErrCookieTampered
+		}
+		plaintext, openErr := key.aead.Open(nil, b[:nonceSize], b[nonceSize:], []byte(name))
+		if openErr == nil {
+			return string(plaintext), n > 0, nil
+		}
+	}
+	return "", false, ErrCookieTampered
+}
+
+// SetSigned signs the value of the given cookie and adds it to the response.
+// Returns `ErrCookieTooLarge` if the signed cookie exceeds the maximum size.
+func (c *CookieCodec) SetSigned(response *Response, cookie *http.Cookie) error {
+	cpy := *cookie
+	cpy.Value = c.Sign(cookie.Name, cookie.Value)
+	return c.set(response, &cpy)
+}
+
+// SetEncrypted encrypts the value of the given cookie and adds it to the response.
+// Returns `ErrCookieTooLarge` if the encrypted cookie exceeds the maximum size.
+func (c *CookieCodec) SetEncrypted(response *Response, cookie *http.Cookie) error {
+	cpy := *cookie
+	cpy.Value = c.Encrypt(cookie.Name, cookie.Value)
+	return c.set(response, &cpy)
+}
+
+func (c *CookieCodec) set(response *Response, cookie *http.Cookie) error {
+	maxSize := c.MaxSize
+	if maxSize <= 0 {
+		maxSize = MaxCookieSize
+	}
+	if len(cookie.Name)+len(cookie.Value)+1 > maxSize {
+		return ErrCookieTooLarge
+	}
+	response.Cookie(cookie)
+	return nil
+}
+
+// Signed returns the verified value of the signed cookie identified by the given name.
+// `rotated` is `true` if the value was signed with a previous key: the cookie should be
+// set again using `SetSigned` so it is signed with the current key.
+//
+// Returns `http.ErrNoCookie` if the cookie doesn't exist and `ErrCookieTampered` if the
+// signature is invalid.
+func (c *CookieCodec) Signed(request *Request, name string) (value string, rotated bool, err error) {
+	cookie, err := request.Request().Cookie(name)
+	if err != nil {
+		return "", false, err
+	}
+	return c.Verify(name, cookie.Value)
+}
+
+// Encrypted returns the decrypted value of the encrypted cookie identified by the given name.
+// `rotated` is `true` if the value was encrypted with a previous key: the cookie should be
+// set again using `SetEncrypted` so it is encrypted with the current key.
+//
+// Returns `http.ErrNoCookie` if the cookie doesn't exist and `ErrCookieTampered` if the
+// value cannot be decrypted.
+func (c *CookieCodec) Encrypted(request *Request, name string) (value string, rotated bool, err error) {
+	cookie, err := request.Request().Cookie(name)
+	if err != nil {
+		return "", false, err
+	}
+	return c.Decrypt(name, cookie.Value)
+}
@@ -0,0 +1,161 @@
+package goyave
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/config"
+)
+
+func TestCookieCodec(t *testing.T) {
+	t.Run("NewCookieCodec", func(t *testing.T) {
+		codec := NewCookieCodec("a", "b")
+		assert.Len(t, codec.keys, 2)
+		assert.Equal(t, MaxCookieSize, codec.MaxSize)
+
+		assert.Panics(t, func() { NewCookieCodec() })
+		assert.Panics(t, func() { NewCookieCodec("a", "") })
+	})
+
+	t.Run("Server.CookieCodec", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		server, err := New(Options{Config: cfg})
+		require.NoError(t, err)
+		assert.Panics(t, func() { server.CookieCodec() })
+
+		cfg.Set("app.key", "current-key")
+		cfg.Set("app.previousKeys", []string{"old-key", ""})
+		codec := server.CookieCodec()
+		assert.Len(t, codec.keys, 2)
+
+		value, rotated, err := codec.Verify("name", NewCookieCodec("old-key").Sign("name", "value"))
+		require.NoError(t, err)
+		assert.Equal(t, "value", value)
+		assert.True(t, rotated)
+	})
+
+	t.Run("Sign_Verify", func(t *testing.T) {
+		codec := NewCookieCodec("key")
+		signed := codec.Sign("name", "some.value")
+		assert.True(t, strings.HasPrefix(signed, "some.value."))
+
+		value, rotated, err := codec.Verify("name", signed)
+		require.NoError(t, err)
+		assert.Equal(t, "some.value", value)
+		assert.False(t, rotated)
+
+		cases := []string{
+			"",
+			"no-signature",
+			"some.value.%%%",
+			"other" + signed[len("some.value"):],
+			NewCookieCodec("other-key").Sign("name", "some.value"),
+		}
+		for _, c := range cases {
+			_, _, err := codec.Verify("name", c)
+			assert.ErrorIs(t, err, ErrCookieTampered, c)
+		}
+
+		_, _, err = codec.Verify("other-name", signed)
+		assert.ErrorIs(t, err, ErrCookieTampered, "value bound to the cookie name")
+
+		rotatingCodec := NewCookieCodec("new-key", "key")
+		value, rotated, err = rotatingCodec.Verify("name", signed)
+		require.NoError(t, err)
+		assert.Equal(t, "some.value", value)
+		assert.True(t, rotated)
+	})
+
+	t.Run("Encrypt_Decrypt", func(t *testing.T) {
+		codec := NewCookieCodec("key")
+		encrypted := codec.Encrypt("name", "secret value")
+		assert.NotContains(t, encrypted, "secret")
+		assert.NotEqual(t, encrypted, codec.Encrypt("name", "secret value"), "random nonce")
+
+		value, rotated, err := codec.Decrypt("name", encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, "secret value", value)
+		assert.False(t, rotated)
+
+		cases := []string{
+			"",
+			"%%%",
+			"c2hvcnQ",
+			encrypted[:len(encrypted)-2] + "AA",
+			NewCookieCodec("other-key").Encrypt("name", "secret value"),
+		}
+		for _, c := range cases {
+			_, _, err := codec.Decrypt("name", c)
+			assert.ErrorIs(t, err, ErrCookieTampered, c)
+		}
+
+		_, _, err = codec.Decrypt("other-name", encrypted)
+		assert.ErrorIs(t, err, ErrCookieTampered, "value bound to the cookie name")
+
+		rotatingCodec := NewCookieCodec("new-key", "key")
+		value, rotated, err = rotatingCodec.Decrypt("name", encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, "secret value", value)
+		assert.True(t, rotated)
+	})
+
+	t.Run("Set_Get", func(t *testing.T) {
+		server, err := New(Options{Config: config.LoadDefault()})
+		require.NoError(t, err)
+		codec := NewCookieCodec("key")
+
+		recorder := httptest.NewRecorder()
+		response := NewResponse(server, NewRequest(httptest.NewRequest(http.MethodGet, "/", nil)), recorder)
+		cookie := &http.Cookie{Name: "remember", Value: "user-1", HttpOnly: true}
+		require.NoError(t, codec.SetSigned(response, cookie))
+		require.NoError(t, codec.SetEncrypted(response, &http.Cookie{Name: "preferences", Value: "dark"}))
+		assert.Equal(t, "user-1", cookie.Value, "original cookie not modified")
+
+		cookies := recorder.Result().Cookies()
+		require.Len(t, cookies, 2)
+		assert.True(t, cookies[0].HttpOnly)
+
+		httpRequest := httptest.NewRequest(http.MethodGet, "/", nil)
+		for _, c := range cookies {
+			httpRequest.AddCookie(c)
+		}
+		request := NewRequest(httpRequest)
+
+		value, rotated, err := codec.Signed(request, "remember")
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", value)
+		assert.False(t, rotated)
+
+		value, rotated, err = codec.Encrypted(request, "preferences")
+		require.NoError(t, err)
+		assert.Equal(t, "dark", value)
+		assert.False(t, rotated)
+
+		_, _, err = codec.Signed(request, "preferences")
+		assert.ErrorIs(t, err, ErrCookieTampered)
+		_, _, err = codec.Signed(request, "missing")
+		assert.ErrorIs(t, err, http.ErrNoCookie)
+		_, _, err = codec.Encrypted(request, "missing")
+		assert.ErrorIs(t, err, http.ErrNoCookie)
+	})
+
+	t.Run("too_large", func(t *testing.T) {
+		server, err := New(Options{Config: config.LoadDefault()})
+		require.NoError(t, err)
+		codec := NewCookieCodec("key")
+		codec.MaxSize = 64
+
+		recorder := httptest.NewRecorder()
+		response := NewResponse(server, NewRequest(httptest.NewRequest(http.MethodGet, "/", nil)), recorder)
+		assert.ErrorIs(t, codec.SetSigned(response, &http.Cookie{Name: "name", Value: strings.Repeat("a", 64)}), ErrCookieTooLarge)
+		assert.ErrorIs(t, codec.SetEncrypted(response, &http.Cookie{Name: "name", Value: strings.Repeat("a", 64)}), ErrCookieTooLarge)
+		assert.Empty(t, recorder.Result().Cookies())
+
+		codec.MaxSize = 0
+		assert.NoError(t, codec.SetSigned(response, &http.Cookie{Name: "name", Value: strings.Repeat("a", 64)}))
+	})
+}
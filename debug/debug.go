@@ -0,0 +1,168 @@
+package debug
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/auth"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+func init() {
+	config.Register("debug.enabled", config.Entry{
+		Value:            false,
+		Type:             reflect.Bool,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("debug.prefix", config.Entry{
+		Value:            "/debug",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+}
+
+// Redacted the value replacing sensitive config entries in the
+// output of the config debug route.
+const Redacted = config.Redacted
+
+// SensitiveKeys if the last segment of a config entry's key contains
+// one of these strings (case-insensitive), its value is replaced with `Redacted`
+// in the output of the config debug route. Entries registered as sensitive
+// (`config.Entry.Sensitive`) are always redacted.
+var SensitiveKeys = []string{"password", "secret", "token", "key", "private", "dsn"}
+
+// RegisterRoutes mounts the debug routes in a new subrouter of the given router,
+// using the prefix defined by the "debug.prefix" config entry:
+//   - `/pprof/`: the `net/http/pprof` index and profiles (`/pprof/heap`, `/pprof/profile`, `/pprof/trace`, etc.)
+//   - `/vars`: runtime stats (goroutines, memory stats, etc.) in a format similar to `expvar`
+//   - `/config`: the current configuration, with sensitive entries redacted (see `SensitiveKeys`)
+//   - `/routes`: the route table of the root of the given router (see `goyave.Router.RouteTable()`)
+//   - `/services`: the names of the services registered on the server
+//
+// All debug routes are named with the "goyave.debug." prefix and require authentication using the given
+// authenticator. Panics if the authenticator is `nil`.
+//
+// The routes are only registered if the "app.debug" or the "debug.enabled" config entries are `true`.
+// Returns the created subrouter or `nil` if the routes were not registered.
+//
+// Profiling can expose sensitive information about your application and
+// some profiles can be expensive to compute. Only enable them when needed.
+func RegisterRoutes[T any](server *goyave.Server, router *goyave.Router, authenticator auth.Authenticator[T]) *goyave.Router {
+	if authenticator == nil {
+		panic(errors.New("debug routes require an authenticator"))
+	}
+	cfg := server.Config()
+	if !cfg.GetBool("app.debug") && !cfg.GetBool("debug.enabled") {
+		return nil
+	}
+
+	debugRouter := router.Subrouter(cfg.GetString("debug.prefix"))
+	debugRouter.SetMeta(auth.MetaAuth, true)
+	debugRouter.Middleware(auth.Middleware(authenticator))
+
+	// Not using a subrouter so the trailing slash of the index can be matched.
+	debugRouter.Get("/pprof", pprofIndex)
+	debugRouter.Get("/pprof/", pprofIndex).Name("goyave.debug.pprof")
+	debugRouter.Get("/pprof/cmdline", wrap(pprof.Cmdline)).Name("goyave.debug.pprof.cmdline")
+	debugRouter.Get("/pprof/profile", wrap(pprof.Profile)).Name("goyave.debug.pprof.profile")
+	debugRouter.Route([]string{http.MethodGet, http.MethodPost}, "/pprof/symbol", wrap(pprof.Symbol)).Name("goyave.debug.pprof.symbol")
+	debugRouter.Get("/pprof/trace", wrap(pprof.Trace)).Name("goyave.debug.pprof.trace")
+	debugRouter.Get("/pprof/{profile}", pprofProfile).Name("goyave.debug.pprof.named")
+
+	debugRouter.Get("/vars", vars).Name("goyave.debug.vars")
+	debugRouter.Get("/config", configHandler(cfg)).Name("goyave.debug.config")
+	debugRouter.Get("/routes", routes(router)).Name("goyave.debug.routes")
+	debugRouter.Get("/services", services(server)).Name("goyave.debug.services")
+	return debugRouter
+}
+
+func wrap(handler http.HandlerFunc) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		handler(response, request.Request())
+	}
+}
+
+func pprofIndex(response *goyave.Response, request *goyave.Request) {
+	path := request.URL().Path
+	if !strings.HasSuffix(path, "/") {
+		// The links in the index are relative, the trailing slash is required.
+		http.Redirect(response, request.Request(), path+"/", http.StatusMovedPermanently)
+		return
+	}
+	pprof.Index(response, request.Request())
+}
+
+func pprofProfile(response *goyave.Response, request *goyave.Request) {
+	pprof.Handler(request.RouteParams["profile"]).ServeHTTP(response, request.Request())
+}
+
+func vars(response *goyave.Response, _ *goyave.Request) {
+	memstats := &runtime.MemStats{}
+	runtime.ReadMemStats(memstats)
+	response.JSON(http.StatusOK, map[string]any{
+		"cmdline":    os.Args,
+		"goVersion":  runtime.Version(),
+		"goroutines": runtime.NumGoroutine(),
+		"gomaxprocs": runtime.GOMAXPROCS(0),
+		"numCPU":     runtime.NumCPU(),
+		"memstats":   memstats,
+	})
+}
+
+func configHandler(cfg *config.Config) goyave.Handler {
+	return func(response *goyave.Response, _ *goyave.Request) {
+		response.JSON(http.StatusOK, redact(cfg.RedactedMap()))
+	}
+}
+
+func redact(category map[string]any) map[string]any {
+	for k, v := range category {
+		if c, ok := v.(map[string]any); ok {
+			redact(c)
+			continue
+		}
+		if v != nil && v != "" && isSensitive(k) {
+			category[k] = Redacted
+		}
+	}
+	return category
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range SensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func routes(router *goyave.Router) goyave.Handler {
+	return func(response *goyave.Response, _ *goyave.Request) {
+		root := router
+		for root.GetParent() != nil {
+			root = root.GetParent()
+		}
+		response.JSON(http.StatusOK, root.RouteTable())
+	}
+}
+
+func services(server *goyave.Server) goyave.Handler {
+	return func(response *goyave.Response, _ *goyave.Request) {
+		services := server.Services()
+		names := make([]string, 0, len(services))
+		for _, s := range services {
+			names = append(names, s.Name())
+		}
+		response.JSON(http.StatusOK, names)
+	}
+}
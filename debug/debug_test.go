@@ -0,0 +1,152 @@
+package debug
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/auth"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+type testService struct{}
+
+func (testService) Name() string { return "test-service" }
+
+func TestRegisterRoutes(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		cfg.Set("app.debug", false)
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+		router := goyave.NewRouter(server.Server)
+		assert.Nil(t, RegisterRoutes(server.Server, router, &auth.ConfigBasicAuthenticator{}))
+		assert.Empty(t, router.GetSubrouters())
+	})
+
+	t.Run("nil_authenticator", func(t *testing.T) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		assert.Panics(t, func() {
+			RegisterRoutes[auth.BasicUser](server.Server, goyave.NewRouter(server.Server), nil)
+		})
+	})
+
+	cfg := config.LoadDefault()
+	cfg.Set("app.debug", false)
+	cfg.Set("debug.enabled", true)
+	cfg.Set("debug.prefix", "/_debug")
+	cfg.Set("auth.basic.username", "admin")
+	cfg.Set("auth.basic.password", "secret")
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+	server.RegisterService(testService{})
+
+	router := goyave.NewRouter(server.Server)
+	router.Get("/hello", func(response *goyave.Response, _ *goyave.Request) {
+		response.String(http.StatusOK, "hello")
+	}).Name("hello")
+	debugRouter := RegisterRoutes(server.Server, router, &auth.ConfigBasicAuthenticator{})
+	require.NotNil(t, debugRouter)
+	assert.NotNil(t, router.GetRoute("goyave.debug.pprof"))
+
+	serve := func(method, uri string, authenticate bool) *http.Response {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(method, uri, nil)
+		if authenticate {
+			req.SetBasicAuth("admin", "secret")
+		}
+		router.ServeHTTP(recorder, req)
+		return recorder.Result()
+	}
+
+	readBody := func(t *testing.T, resp *http.Response) string {
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+		return string(body)
+	}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		resp := serve(http.MethodGet, "/_debug/vars", false)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.NoError(t, resp.Body.Close())
+
+		resp = serve(http.MethodGet, "/hello", false)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NoError(t, resp.Body.Close())
+	})
+
+	t.Run("pprof", func(t *testing.T) {
+		resp := serve(http.MethodGet, "/_debug/pprof", true)
+		assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+		assert.Equal(t, "/_debug/pprof/", resp.Header.Get("Location"))
+		assert.NoError(t, resp.Body.Close())
+
+		resp = serve(http.MethodGet, "/_debug/pprof/", true)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, readBody(t, resp), "Types of profiles available")
+
+		resp = serve(http.MethodGet, "/_debug/pprof/goroutine?debug=1", true)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, readBody(t, resp), "goroutine profile")
+
+		resp = serve(http.MethodGet, "/_debug/pprof/cmdline", true)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NotEmpty(t, readBody(t, resp))
+
+		resp = serve(http.MethodGet, "/_debug/pprof/unknown", true)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.NoError(t, resp.Body.Close())
+	})
+
+	t.Run("vars", func(t *testing.T) {
+		resp := serve(http.MethodGet, "/_debug/vars", true)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		vars, err := testutil.ReadJSONBody[map[string]any](resp.Body)
+		assert.NoError(t, resp.Body.Close())
+		require.NoError(t, err)
+		assert.Contains(t, vars, "goroutines")
+		assert.Contains(t, vars, "memstats")
+		assert.Contains(t, vars, "goVersion")
+	})
+
+	t.Run("config", func(t *testing.T) {
+		resp := serve(http.MethodGet, "/_debug/config", true)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		cfgMap, err := testutil.ReadJSONBody[map[string]any](resp.Body)
+		assert.NoError(t, resp.Body.Close())
+		require.NoError(t, err)
+		basic := cfgMap["auth"].(map[string]any)["basic"].(map[string]any)
+		assert.Equal(t, "admin", basic["username"])
+		assert.Equal(t, Redacted, basic["password"])
+		assert.Empty(t, cfgMap["database"].(map[string]any)["password"]) // Empty values are not redacted
+		assert.Equal(t, "/_debug", cfgMap["debug"].(map[string]any)["prefix"])
+
+		// The server's config is not modified
+		assert.Equal(t, "secret", cfg.GetString("auth.basic.password"))
+	})
+
+	t.Run("routes", func(t *testing.T) {
+		resp := serve(http.MethodGet, "/_debug/routes", true)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		routes, err := testutil.ReadJSONBody[[]map[string]any](resp.Body)
+		assert.NoError(t, resp.Body.Close())
+		require.NoError(t, err)
+		names := lo.Map(routes, func(r map[string]any, _ int) any { return r["name"] })
+		assert.Contains(t, names, "hello")
+		assert.Contains(t, names, "goyave.debug.routes")
+	})
+
+	t.Run("services", func(t *testing.T) {
+		resp := serve(http.MethodGet, "/_debug/services", true)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		services, err := testutil.ReadJSONBody[[]string](resp.Body)
+		assert.NoError(t, resp.Body.Close())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"test-service"}, services)
+	})
+}
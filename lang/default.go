@@ -10,6 +10,14 @@ var enUS = &Language{
 		"auth.jwt-invalid":             "Your authentication token is invalid.",
 		"auth.jwt-not-valid-yet":       "Your authentication token is not valid yet.",
 		"auth.jwt-expired":             "Your authentication token is expired.",
+		"signed-url.invalid":           "Invalid or missing link signature.",
+		"signed-url.expired":           "This link has expired.",
+		"csrf.token-mismatch":          "Invalid or missing CSRF token.",
+		"csrf.origin-mismatch":         "Cross-site request rejected.",
+		"idempotency.missing-key":      "The Idempotency-Key header is required.",
+		"idempotency.invalid-key":      "The Idempotency-Key header is invalid.",
+		"idempotency.conflict":         "A request with the same idempotency key is still being processed.",
+		"idempotency.mismatch":         "The idempotency key has already been used for a different request.",
 	},
 	validation: validationLines{
 		rules: map[string]string{
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"database/sql"
+	"runtime"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/websocket"
+)
+
+// DBStatsCollector exposes the connection pool statistics (`sql.DBStats`)
+// of the server's database.
+//
+// If the database connection is set to "none" in the config, or if the
+// database pool cannot be retrieved, the collector doesn't produce any metric.
+type DBStatsCollector struct {
+	server *goyave.Server
+}
+
+var _ Collector = (*DBStatsCollector)(nil)
+
+// NewDBStatsCollector create a new collector for the database of the given server.
+// The database is retrieved at scrape time using `Server.DB()` so replacing
+// the database after the creation of the collector is supported.
+func NewDBStatsCollector(server *goyave.Server) *DBStatsCollector {
+	return &DBStatsCollector{server: server}
+}
+
+// Collect implementation of `Collector`.
+func (c *DBStatsCollector) Collect() []Family {
+	if c.server.Config().GetString("database.connection") == "none" {
+		return nil
+	}
+	db, err := c.server.DB().DB()
+	if err != nil {
+		return nil
+	}
+	return dbStatsFamilies(db.Stats())
+}
+
+func dbStatsFamilies(stats sql.DBStats) []Family {
+	gauge := func(name, help string, value float64) Family {
+		return Family{Name: name, Help: help, Type: TypeGauge, Samples: []Sample{{Value: value}}}
+	}
+	counter := func(name, help string, value float64) Family {
+		return Family{Name: name, Help: help, Type: TypeCounter, Samples: []Sample{{Value: value}}}
+	}
+	return []Family{
+		gauge("goyave_db_max_open_connections", "Maximum number of open connections to the database.", float64(stats.MaxOpenConnections)),
+		gauge("goyave_db_open_connections", "The number of established connections both in use and idle.", float64(stats.OpenConnections)),
+		gauge("goyave_db_in_use_connections", "The number of connections currently in use.", float64(stats.InUse)),
+		gauge("goyave_db_idle_connections", "The number of idle connections.", float64(stats.Idle)),
+		counter("goyave_db_wait_count_total", "The total number of connections waited for.", float64(stats.WaitCount)),
+		counter("goyave_db_wait_duration_seconds_total", "The total time blocked waiting for a new connection.", stats.WaitDuration.Seconds()),
+		counter("goyave_db_max_idle_closed_total", "The total number of connections closed due to SetMaxIdleConns.", float64(stats.MaxIdleClosed)),
+		counter("goyave_db_max_idle_time_closed_total", "The total number of connections closed due to SetConnMaxIdleTime.", float64(stats.MaxIdleTimeClosed)),
+		counter("goyave_db_max_lifetime_closed_total", "The total number of connections closed due to SetConnMaxLifetime.", float64(stats.MaxLifetimeClosed)),
+	}
+}
+
+// WebsocketCollector exposes the number of open websocket connections
+// for each of the given upgraders.
+type WebsocketCollector struct {
+	upgraders []*websocket.Upgrader
+}
+
+var _ Collector = (*WebsocketCollector)(nil)
+
+// NewWebsocketCollector create a new collector exposing the sum of
+// the open connections of all the given upgraders.
+func NewWebsocketCollector(upgraders ...*websocket.Upgrader) *WebsocketCollector {
+	return &WebsocketCollector{upgraders: upgraders}
+}
+
+// Collect implementation of `Collector`.
+func (c *WebsocketCollector) Collect() []Family {
+	var count int64
+	for _, u := range c.upgraders {
+		count += u.ConnectionCount()
+	}
+	return []Family{{
+		Name:    "goyave_websocket_connections",
+		Help:    "The number of currently open websocket connections.",
+		Type:    TypeGauge,
+		Samples: []Sample{{Value: float64(count)}},
+	}}
+}
+
+// RuntimeCollector exposes Go runtime statistics: goroutines, memory
+// and garbage collector.
+type RuntimeCollector struct{}
+
+var _ Collector = (*RuntimeCollector)(nil)
+
+// NewRuntimeCollector create a new Go runtime statistics collector.
+func NewRuntimeCollector() *RuntimeCollector {
+	return &RuntimeCollector{}
+}
+
+// Collect implementation of `Collector`.
+// Calls `runtime.ReadMemStats`, which stops the world for a very short time.
+func (c *RuntimeCollector) Collect() []Family {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	gauge := func(name, help string, value float64) Family {
+		return Family{Name: name, Help: help, Type: TypeGauge, Samples: []Sample{{Value: value}}}
+	}
+	return []Family{
+		gauge("go_goroutines", "Number of goroutines that currently exist.", float64(runtime.NumGoroutine())),
+		gauge("go_gomaxprocs", "Maximum number of CPUs that can be executing simultaneously.", float64(runtime.GOMAXPROCS(0))),
+		{
+			Name:    "go_info",
+			Help:    "Information about the Go environment.",
+			Type:    TypeGauge,
+			Samples: []Sample{{Labels: []Label{{Name: "version", Value: runtime.Version()}}, Value: 1}},
+		},
+		gauge("go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", float64(mem.Alloc)),
+		{Name: "go_memstats_alloc_bytes_total", Help: "Total number of bytes allocated, even if freed.", Type: TypeCounter, Samples: []Sample{{Value: float64(mem.TotalAlloc)}}},
+		gauge("go_memstats_sys_bytes", "Number of bytes obtained from system.", float64(mem.Sys)),
+		gauge("go_memstats_heap_alloc_bytes", "Number of heap bytes allocated and still in use.", float64(mem.HeapAlloc)),
+		gauge("go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use.", float64(mem.HeapInuse)),
+		gauge("go_memstats_heap_objects", "Number of allocated objects.", float64(mem.HeapObjects)),
+		gauge("go_memstats_stack_inuse_bytes", "Number of bytes in use by the stack allocator.", float64(mem.StackInuse)),
+		{Name: "go_memstats_mallocs_total", Help: "Total number of mallocs.", Type: TypeCounter, Samples: []Sample{{Value: float64(mem.Mallocs)}}},
+		{Name: "go_memstats_frees_total", Help: "Total number of frees.", Type: TypeCounter, Samples: []Sample{{Value: float64(mem.Frees)}}},
+		{Name: "go_gc_cycles_total", Help: "Number of completed GC cycles.", Type: TypeCounter, Samples: []Sample{{Value: float64(mem.NumGC)}}},
+		{Name: "go_gc_pause_seconds_total", Help: "Cumulative time spent in GC stop-the-world pauses.", Type: TypeCounter, Samples: []Sample{{Value: float64(mem.PauseTotalNs) / 1e9}}},
+		gauge("go_memstats_next_gc_bytes", "Number of heap bytes when next garbage collection will take place.", float64(mem.NextGC)),
+	}
+}
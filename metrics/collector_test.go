@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+	"goyave.dev/goyave/v5/websocket"
+
+	_ "goyave.dev/goyave/v5/database/dialect/sqlite"
+)
+
+func findFamily(families []Family, name string) (Family, bool) {
+	return lo.Find(families, func(f Family) bool { return f.Name == name })
+}
+
+func TestDBStatsCollector(t *testing.T) {
+	t.Run("no_database", func(t *testing.T) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		assert.Empty(t, NewDBStatsCollector(server.Server).Collect())
+	})
+
+	t.Run("database", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		cfg.Set("app.debug", false)
+		cfg.Set("database.connection", "sqlite3")
+		cfg.Set("database.name", "metrics_test.db")
+		cfg.Set("database.options", "mode=memory")
+		cfg.Set("database.maxOpenConnections", 7)
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+
+		families := NewDBStatsCollector(server.Server).Collect()
+		f, ok := findFamily(families, "goyave_db_max_open_connections")
+		require.True(t, ok)
+		assert.Equal(t, TypeGauge, f.Type)
+		assert.InEpsilon(t, 7.0, f.Samples[0].Value, 0)
+
+		f, ok = findFamily(families, "goyave_db_wait_count_total")
+		require.True(t, ok)
+		assert.Equal(t, TypeCounter, f.Type)
+	})
+}
+
+func TestWebsocketCollector(t *testing.T) {
+	collector := NewWebsocketCollector(websocket.New(nil), websocket.New(nil))
+	families := collector.Collect()
+	require.Len(t, families, 1)
+	assert.Equal(t, "goyave_websocket_connections", families[0].Name)
+	assert.Equal(t, []Sample{{Value: 0}}, families[0].Samples)
+}
+
+func TestRuntimeCollector(t *testing.T) {
+	families := NewRuntimeCollector().Collect()
+
+	f, ok := findFamily(families, "go_goroutines")
+	require.True(t, ok)
+	assert.Positive(t, f.Samples[0].Value)
+
+	f, ok = findFamily(families, "go_info")
+	require.True(t, ok)
+	assert.Equal(t, "version", f.Samples[0].Labels[0].Name)
+
+	_, ok = findFamily(families, "go_memstats_heap_alloc_bytes")
+	assert.True(t, ok)
+}
@@ -0,0 +1,246 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// Type of a metric, used in the `# TYPE` line of the Prometheus
+// text exposition format.
+type Type string
+
+// Supported metric types.
+const (
+	TypeCounter   Type = "counter"
+	TypeGauge     Type = "gauge"
+	TypeHistogram Type = "histogram"
+)
+
+// DefaultBuckets the default histogram buckets, tailored to measure
+// the latency of HTTP requests (in seconds).
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Sample a single value of a metric family identified by its label values.
+// For histograms, the suffix is used to differentiate `_bucket`, `_sum` and `_count`.
+type Sample struct {
+	Suffix string
+	Labels []Label
+	Value  float64
+}
+
+// Label a name/value pair attached to a sample.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Family a group of samples sharing the same name, help and type.
+type Family struct {
+	Name    string
+	Help    string
+	Type    Type
+	Samples []Sample
+}
+
+// Collector is anything that can produce metric families when the
+// registry is scraped. Counters, gauges and histograms are collectors,
+// but custom collectors can also be implemented to expose values computed
+// at scrape time (e.g. database connection pool statistics).
+type Collector interface {
+	Collect() []Family
+}
+
+// Registry holds collectors and writes their output in the Prometheus
+// text exposition format. This structure is safe for concurrent use.
+type Registry struct {
+	collectors []Collector
+	mu         sync.RWMutex
+}
+
+// NewRegistry create a new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		collectors: make([]Collector, 0, 8),
+	}
+}
+
+// Register add the given collectors to the registry.
+func (r *Registry) Register(collectors ...Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, collectors...)
+}
+
+// Gather collects all families from the registered collectors.
+// Families are sorted by name and families with identical names are merged.
+func (r *Registry) Gather() []Family {
+	r.mu.RLock()
+	collectors := slices.Clone(r.collectors)
+	r.mu.RUnlock()
+
+	byName := make(map[string]*Family, len(collectors))
+	names := make([]string, 0, len(collectors))
+	for _, c := range collectors {
+		for _, f := range c.Collect() {
+			if existing, ok := byName[f.Name]; ok {
+				existing.Samples = append(existing.Samples, f.Samples...)
+				continue
+			}
+			family := f
+			byName[f.Name] = &family
+			names = append(names, f.Name)
+		}
+	}
+	sort.Strings(names)
+
+	families := make([]Family, 0, len(names))
+	for _, n := range names {
+		families = append(families, *byName[n])
+	}
+	return families
+}
+
+// WriteText writes all gathered metrics to the given writer using
+// the Prometheus text exposition format (version 0.0.4).
+func (r *Registry) WriteText(w io.Writer) error {
+	var builder strings.Builder
+	for _, f := range r.Gather() {
+		if f.Help != "" {
+			builder.WriteString("# HELP ")
+			builder.WriteString(f.Name)
+			builder.WriteByte(' ')
+			builder.WriteString(escapeHelp(f.Help))
+			builder.WriteByte('\n')
+		}
+		builder.WriteString("# TYPE ")
+		builder.WriteString(f.Name)
+		builder.WriteByte(' ')
+		builder.WriteString(string(f.Type))
+		builder.WriteByte('\n')
+		for _, s := range f.Samples {
+			builder.WriteString(f.Name)
+			builder.WriteString(s.Suffix)
+			writeLabels(&builder, s.Labels)
+			builder.WriteByte(' ')
+			builder.WriteString(formatFloat(s.Value))
+			builder.WriteByte('\n')
+		}
+	}
+	_, err := io.WriteString(w, builder.String())
+	return errors.New(err)
+}
+
+func writeLabels(builder *strings.Builder, labels []Label) {
+	if len(labels) == 0 {
+		return
+	}
+	builder.WriteByte('{')
+	for i, l := range labels {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(l.Name)
+		builder.WriteString(`="`)
+		builder.WriteString(escapeLabelValue(l.Value))
+		builder.WriteByte('"')
+	}
+	builder.WriteByte('}')
+}
+
+var (
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// vec common implementation of labeled metrics. Children are identified
+// by their label values joined with a separator that cannot appear in
+// valid UTF-8 label values.
+type vec[T any] struct {
+	children   map[string]*T
+	newChild   func() *T
+	labelNames []string
+	keys       []string
+	mu         sync.RWMutex
+}
+
+const labelSeparator = "\xff"
+
+func newVec[T any](labelNames []string, newChild func() *T) vec[T] {
+	return vec[T]{
+		labelNames: labelNames,
+		children:   make(map[string]*T),
+		newChild:   newChild,
+	}
+}
+
+func (v *vec[T]) with(labelValues ...string) *T {
+	if len(labelValues) != len(v.labelNames) {
+		panic(errors.NewSkip(fmt.Errorf("metrics: expected %d label values, %d given", len(v.labelNames), len(labelValues)), 4))
+	}
+	key := strings.Join(labelValues, labelSeparator)
+	v.mu.RLock()
+	child, ok := v.children[key]
+	v.mu.RUnlock()
+	if ok {
+		return child
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if child, ok := v.children[key]; ok {
+		return child
+	}
+	child = v.newChild()
+	v.children[key] = child
+	v.keys = append(v.keys, key)
+	sort.Strings(v.keys)
+	return child
+}
+
+// each calls the given function for each child in a stable order.
+func (v *vec[T]) each(f func(labels []Label, child *T)) {
+	v.mu.RLock()
+	keys := slices.Clone(v.keys)
+	children := make([]*T, len(keys))
+	for i, k := range keys {
+		children[i] = v.children[k]
+	}
+	v.mu.RUnlock()
+
+	for i, k := range keys {
+		values := strings.Split(k, labelSeparator)
+		labels := make([]Label, len(v.labelNames))
+		for j, name := range v.labelNames {
+			labels[j] = Label{Name: name, Value: values[j]}
+		}
+		f(labels, children[i])
+	}
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("WriteText", func(t *testing.T) {
+		registry := NewRegistry()
+		counter := NewCounterVec("test_requests_total", "Total requests.\nSecond line", "route", "status")
+		gauge := NewGauge("test_in_flight", "In flight")
+		registry.Register(gauge, counter)
+
+		counter.With("users.index", "200").Inc()
+		counter.With("users.index", "200").Add(2)
+		counter.With("users.\"show\"\n\\", "404").Inc()
+		gauge.Set(3)
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, registry.WriteText(buf))
+
+		expected := `# HELP test_in_flight In flight
+# TYPE test_in_flight gauge
+test_in_flight 3
+# HELP test_requests_total Total requests.\nSecond line
+# TYPE test_requests_total counter
+test_requests_total{route="users.\"show\"\n\\",status="404"} 1
+test_requests_total{route="users.index",status="200"} 3
+`
+		assert.Equal(t, expected, buf.String())
+	})
+
+	t.Run("merge_families", func(t *testing.T) {
+		registry := NewRegistry()
+		registry.Register(
+			NewGaugeFunc("test_merged", "help", func() float64 { return 1 }),
+			NewGaugeFunc("test_merged", "help", func() float64 { return 2 }),
+		)
+
+		families := registry.Gather()
+		require.Len(t, families, 1)
+		assert.Len(t, families[0].Samples, 2)
+	})
+
+	t.Run("no_help", func(t *testing.T) {
+		registry := NewRegistry()
+		registry.Register(NewCounter("test_counter", ""))
+		buf := &bytes.Buffer{}
+		require.NoError(t, registry.WriteText(buf))
+		assert.Equal(t, "# TYPE test_counter counter\ntest_counter 0\n", buf.String())
+	})
+}
+
+func TestFormatFloat(t *testing.T) {
+	assert.Equal(t, "+Inf", formatFloat(math.Inf(1)))
+	assert.Equal(t, "-Inf", formatFloat(math.Inf(-1)))
+	assert.Equal(t, "NaN", formatFloat(math.NaN()))
+	assert.Equal(t, "0.25", formatFloat(0.25))
+	assert.Equal(t, "12", formatFloat(12))
+}
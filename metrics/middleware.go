@@ -0,0 +1,166 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+func init() {
+	config.Register("metrics.enabled", config.Entry{
+		Value:            false,
+		Type:             reflect.Bool,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("metrics.path", config.Entry{
+		Value:            "/metrics",
+		Type:             reflect.String,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+}
+
+// RouteName the name of the route registered by `RegisterRoute`.
+const RouteName = "goyave.metrics"
+
+// ContentType the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Middleware records the amount of requests and their latency. Requests are
+// labeled by route name, method and response status.
+//
+// The route name is used instead of the raw path to keep the cardinality
+// of the metrics low. If the matched route doesn't have a name, its full URI
+// definition (e.g. `/users/{userId:[0-9]+}`) is used instead.
+//
+// The metrics are recorded at the very end of the request's lifecycle
+// so the status set by status handlers is taken into account.
+//
+// This middleware should be used as a global middleware so requests not matching
+// any route are also recorded.
+type Middleware struct {
+	goyave.Component
+	requests *CounterVec
+	duration *HistogramVec
+	inFlight *Gauge
+}
+
+// NewMiddleware create a new metrics middleware and registers its collectors
+// into the given registry:
+//   - `goyave_http_requests_total` (counter)
+//   - `goyave_http_request_duration_seconds` (histogram)
+//   - `goyave_http_requests_in_flight` (gauge)
+//
+// If the given buckets are empty, `DefaultBuckets` are used.
+func NewMiddleware(registry *Registry, buckets []float64) *Middleware {
+	m := &Middleware{
+		requests: NewCounterVec("goyave_http_requests_total", "Total number of HTTP requests.", "route", "method", "status"),
+		duration: NewHistogramVec("goyave_http_request_duration_seconds", "HTTP request latency in seconds.", buckets, "route", "method", "status"),
+		inFlight: NewGauge("goyave_http_requests_in_flight", "Number of HTTP requests currently being served."),
+	}
+	registry.Register(m.requests, m.duration, m.inFlight)
+	return m
+}
+
+// Handle adds the metrics chained writer to the response.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		m.inFlight.Inc()
+		response.SetWriter(&writer{
+			writer:     response.Writer(),
+			middleware: m,
+			request:    request,
+			response:   response,
+		})
+		next(response, request)
+	}
+}
+
+func (m *Middleware) record(request *goyave.Request, status int) {
+	m.inFlight.Dec()
+	route := RouteLabel(request.Route)
+	s := strconv.Itoa(status)
+	m.requests.With(route, request.Method(), s).Inc()
+	m.duration.With(route, request.Method(), s).Observe(time.Since(request.Now).Seconds())
+}
+
+// RouteLabel returns the value used for the "route" label of the given route.
+// Returns the name of the route if it has one, its full URI otherwise.
+func RouteLabel(route *goyave.Route) string {
+	if route == nil {
+		return ""
+	}
+	if name := route.GetName(); name != "" {
+		return name
+	}
+	return route.GetFullURI()
+}
+
+// writer chained writer recording the request metrics when closed.
+type writer struct {
+	writer     io.Writer
+	middleware *Middleware
+	request    *goyave.Request
+	response   *goyave.Response
+}
+
+var _ io.Closer = (*writer)(nil)
+var _ goyave.PreWriter = (*writer)(nil)
+
+func (w *writer) PreWrite(b []byte) {
+	if pr, ok := w.writer.(goyave.PreWriter); ok {
+		pr.PreWrite(b)
+	}
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	n, err := w.writer.Write(b)
+	return n, errors.New(err)
+}
+
+func (w *writer) Close() error {
+	w.middleware.record(w.request, w.response.GetStatus())
+	if wr, ok := w.writer.(io.Closer); ok {
+		return wr.Close()
+	}
+	return nil
+}
+
+// Handler returns a handler writing the metrics of the given registry
+// using the Prometheus text exposition format.
+func Handler(registry *Registry) goyave.Handler {
+	return func(response *goyave.Response, _ *goyave.Request) {
+		response.Header().Set("Content-Type", ContentType)
+		response.Status(http.StatusOK)
+		if err := registry.WriteText(response); err != nil {
+			response.Error(err)
+		}
+	}
+}
+
+// RegisterRoute registers a GET route named "goyave.metrics" exposing the metrics
+// of the given registry. The route's path is defined by the "metrics.path"
+// config entry.
+//
+// The route is only registered if the "metrics.enabled" config entry is `true`.
+// Returns `nil` if the route was not registered.
+//
+// The returned route can be used to add authentication or other middleware:
+//
+//	if route := metrics.RegisterRoute(server, router, registry); route != nil {
+//		route.SetMeta(auth.MetaAuth, true)
+//	}
+func RegisterRoute(server *goyave.Server, router *goyave.Router, registry *Registry) *goyave.Route {
+	cfg := server.Config()
+	if !cfg.GetBool("metrics.enabled") {
+		return nil
+	}
+	return router.Get(cfg.GetString("metrics.path"), Handler(registry)).Name(RouteName)
+}
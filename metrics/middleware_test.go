@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func TestMiddleware(t *testing.T) {
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+	registry := NewRegistry()
+	middleware := NewMiddleware(registry, []float64{1})
+
+	router := goyave.NewRouter(server.Server)
+	router.GlobalMiddleware(middleware)
+	router.Get("/users/{id}", func(response *goyave.Response, _ *goyave.Request) {
+		response.String(http.StatusOK, "user")
+	}).Name("users.show")
+	router.Post("/users", func(response *goyave.Response, _ *goyave.Request) {
+		response.Status(http.StatusUnprocessableEntity)
+	})
+
+	serve := func(method, uri string) *http.Response {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(method, uri, nil))
+		return recorder.Result()
+	}
+
+	resp := serve(http.MethodGet, "/users/1")
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, "user", string(body))
+
+	serve(http.MethodGet, "/users/2")
+	serve(http.MethodPost, "/users")
+	serve(http.MethodGet, "/unknown")
+
+	assert.InEpsilon(t, 2.0, middleware.requests.With("users.show", http.MethodGet, "200").Value(), 0)
+	// Status set by status handlers is recorded
+	assert.InEpsilon(t, 1.0, middleware.requests.With("/users", http.MethodPost, "422").Value(), 0)
+	assert.InEpsilon(t, 1.0, middleware.requests.With(goyave.RouteNotFound, http.MethodGet, "404").Value(), 0)
+	assert.Equal(t, uint64(2), middleware.duration.With("users.show", http.MethodGet, "200").Count())
+	assert.Zero(t, middleware.inFlight.Value())
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, registry.WriteText(buf))
+	assert.Contains(t, buf.String(), `goyave_http_requests_total{route="users.show",method="GET",status="200"} 2`)
+	assert.Contains(t, buf.String(), `goyave_http_request_duration_seconds_bucket{route="users.show",method="GET",status="200",le="+Inf"} 2`)
+}
+
+func TestRouteLabel(t *testing.T) {
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+	router := goyave.NewRouter(server.Server)
+	route := router.Subrouter("/users").Get("/{id:[0-9]+}", nil)
+	assert.Equal(t, "/users/{id:[0-9]+}", RouteLabel(route))
+	route.Name("users.show")
+	assert.Equal(t, "users.show", RouteLabel(route))
+	assert.Empty(t, RouteLabel(nil))
+}
+
+func TestRegisterRoute(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(NewGauge("test_gauge", "help"))
+
+	t.Run("disabled", func(t *testing.T) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		router := goyave.NewRouter(server.Server)
+		assert.Nil(t, RegisterRoute(server.Server, router, registry))
+		assert.Nil(t, router.GetRoute(RouteName))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		cfg.Set("metrics.enabled", true)
+		cfg.Set("metrics.path", "/custom-metrics")
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+		router := goyave.NewRouter(server.Server)
+		route := RegisterRoute(server.Server, router, registry)
+		require.NotNil(t, route)
+		assert.Equal(t, RouteName, route.GetName())
+		assert.Equal(t, "/custom-metrics", route.GetFullURI())
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/custom-metrics", nil))
+		resp := recorder.Result()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, ContentType, resp.Header.Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(string(body), "# HELP test_gauge help\n"))
+	})
+}
@@ -0,0 +1,343 @@
+package metrics
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// atomicFloat a float64 that can be updated concurrently.
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+func (f *atomicFloat) add(delta float64) {
+	for {
+		old := f.bits.Load()
+		n := math.Float64bits(math.Float64frombits(old) + delta)
+		if f.bits.CompareAndSwap(old, n) {
+			return
+		}
+	}
+}
+
+func (f *atomicFloat) set(value float64) {
+	f.bits.Store(math.Float64bits(value))
+}
+
+func (f *atomicFloat) load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+//------------------------------
+
+// Counter a cumulative metric that can only increase.
+type Counter struct {
+	name  string
+	help  string
+	value atomicFloat
+}
+
+var _ Collector = (*Counter)(nil)
+
+// NewCounter create a new unlabeled Counter.
+func NewCounter(name, help string) *Counter {
+	return &Counter{name: name, help: help}
+}
+
+// Inc increments the counter by 1.
+func (c *Counter) Inc() {
+	c.value.add(1)
+}
+
+// Add adds the given value to the counter. Panics if the value is negative.
+func (c *Counter) Add(value float64) {
+	if value < 0 {
+		panic(errors.NewSkip("metrics: counter cannot decrease", 3))
+	}
+	c.value.add(value)
+}
+
+// Value returns the current value of the counter.
+func (c *Counter) Value() float64 {
+	return c.value.load()
+}
+
+// Collect implementation of `Collector`.
+func (c *Counter) Collect() []Family {
+	return []Family{{Name: c.name, Help: c.help, Type: TypeCounter, Samples: []Sample{{Value: c.Value()}}}}
+}
+
+// CounterVec a set of counters identified by their label values.
+type CounterVec struct {
+	name string
+	help string
+	vec[Counter]
+}
+
+var _ Collector = (*CounterVec)(nil)
+
+// NewCounterVec create a new CounterVec using the given label names.
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		name: name,
+		help: help,
+		vec:  newVec(labelNames, func() *Counter { return &Counter{name: name, help: help} }),
+	}
+}
+
+// With returns the counter identified by the given label values, creating
+// it if it doesn't exist yet.
+// Panics if the number of values doesn't match the number of label names.
+func (c *CounterVec) With(labelValues ...string) *Counter {
+	return c.with(labelValues...)
+}
+
+// Collect implementation of `Collector`.
+func (c *CounterVec) Collect() []Family {
+	family := Family{Name: c.name, Help: c.help, Type: TypeCounter}
+	c.each(func(labels []Label, child *Counter) {
+		family.Samples = append(family.Samples, Sample{Labels: labels, Value: child.Value()})
+	})
+	return []Family{family}
+}
+
+//------------------------------
+
+// Gauge a metric representing a single value that can go up and down.
+type Gauge struct {
+	name  string
+	help  string
+	value atomicFloat
+}
+
+var _ Collector = (*Gauge)(nil)
+
+// NewGauge create a new unlabeled Gauge.
+func NewGauge(name, help string) *Gauge {
+	return &Gauge{name: name, help: help}
+}
+
+// Set the gauge to the given value.
+func (g *Gauge) Set(value float64) {
+	g.value.set(value)
+}
+
+// Inc increments the gauge by 1.
+func (g *Gauge) Inc() {
+	g.value.add(1)
+}
+
+// Dec decrements the gauge by 1.
+func (g *Gauge) Dec() {
+	g.value.add(-1)
+}
+
+// Add adds the given value (can be negative) to the gauge.
+func (g *Gauge) Add(value float64) {
+	g.value.add(value)
+}
+
+// Value returns the current value of the gauge.
+func (g *Gauge) Value() float64 {
+	return g.value.load()
+}
+
+// Collect implementation of `Collector`.
+func (g *Gauge) Collect() []Family {
+	return []Family{{Name: g.name, Help: g.help, Type: TypeGauge, Samples: []Sample{{Value: g.Value()}}}}
+}
+
+// GaugeVec a set of gauges identified by their label values.
+type GaugeVec struct {
+	name string
+	help string
+	vec[Gauge]
+}
+
+var _ Collector = (*GaugeVec)(nil)
+
+// NewGaugeVec create a new GaugeVec using the given label names.
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{
+		name: name,
+		help: help,
+		vec:  newVec(labelNames, func() *Gauge { return &Gauge{name: name, help: help} }),
+	}
+}
+
+// With returns the gauge identified by the given label values, creating
+// it if it doesn't exist yet.
+// Panics if the number of values doesn't match the number of label names.
+func (g *GaugeVec) With(labelValues ...string) *Gauge {
+	return g.with(labelValues...)
+}
+
+// Collect implementation of `Collector`.
+func (g *GaugeVec) Collect() []Family {
+	family := Family{Name: g.name, Help: g.help, Type: TypeGauge}
+	g.each(func(labels []Label, child *Gauge) {
+		family.Samples = append(family.Samples, Sample{Labels: labels, Value: child.Value()})
+	})
+	return []Family{family}
+}
+
+// GaugeFunc a gauge whose value is computed by calling a function at scrape time.
+type GaugeFunc struct {
+	fn   func() float64
+	name string
+	help string
+}
+
+var _ Collector = (*GaugeFunc)(nil)
+
+// NewGaugeFunc create a new GaugeFunc. The given function must be safe for concurrent use.
+func NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	return &GaugeFunc{name: name, help: help, fn: fn}
+}
+
+// Collect implementation of `Collector`.
+func (g *GaugeFunc) Collect() []Family {
+	return []Family{{Name: g.name, Help: g.help, Type: TypeGauge, Samples: []Sample{{Value: g.fn()}}}}
+}
+
+//------------------------------
+
+// Histogram samples observations and counts them in configurable buckets.
+// Buckets are cumulative and always include an implicit `+Inf` bucket.
+type Histogram struct {
+	name    string
+	help    string
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+	mu      sync.Mutex
+}
+
+var _ Collector = (*Histogram)(nil)
+
+// NewHistogram create a new unlabeled Histogram. If the given buckets are empty,
+// `DefaultBuckets` are used.
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	return newHistogram(name, help, normalizeBuckets(buckets))
+}
+
+func newHistogram(name, help string, buckets []float64) *Histogram {
+	return &Histogram{
+		name:    name,
+		help:    help,
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func normalizeBuckets(buckets []float64) []float64 {
+	if len(buckets) == 0 {
+		return slices.Clone(DefaultBuckets)
+	}
+	b := slices.Clone(buckets)
+	sort.Float64s(b)
+	b = slices.Compact(b)
+	if math.IsInf(b[len(b)-1], 1) {
+		b = b[:len(b)-1]
+	}
+	return b
+}
+
+// Observe adds a single observation to the histogram.
+func (h *Histogram) Observe(value float64) {
+	i := sort.SearchFloat64s(h.buckets, value)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if i < len(h.counts) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += value
+}
+
+// Count returns the total number of observations.
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+// Sum returns the sum of all observations.
+func (h *Histogram) Sum() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.sum
+}
+
+func (h *Histogram) samples(labels []Label) []Sample {
+	h.mu.Lock()
+	counts := slices.Clone(h.counts)
+	count := h.count
+	sum := h.sum
+	h.mu.Unlock()
+
+	samples := make([]Sample, 0, len(counts)+3)
+	var cumulative uint64
+	for i, c := range counts {
+		cumulative += c
+		samples = append(samples, Sample{
+			Suffix: "_bucket",
+			Labels: append(slices.Clone(labels), Label{Name: "le", Value: formatFloat(h.buckets[i])}),
+			Value:  float64(cumulative),
+		})
+	}
+	samples = append(samples,
+		Sample{Suffix: "_bucket", Labels: append(slices.Clone(labels), Label{Name: "le", Value: "+Inf"}), Value: float64(count)},
+		Sample{Suffix: "_sum", Labels: labels, Value: sum},
+		Sample{Suffix: "_count", Labels: labels, Value: float64(count)},
+	)
+	return samples
+}
+
+// Collect implementation of `Collector`.
+func (h *Histogram) Collect() []Family {
+	return []Family{{Name: h.name, Help: h.help, Type: TypeHistogram, Samples: h.samples(nil)}}
+}
+
+// HistogramVec a set of histograms identified by their label values.
+// All histograms share the same buckets.
+type HistogramVec struct {
+	name string
+	help string
+	vec[Histogram]
+}
+
+var _ Collector = (*HistogramVec)(nil)
+
+// NewHistogramVec create a new HistogramVec using the given buckets and label names.
+// If the given buckets are empty, `DefaultBuckets` are used.
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	b := normalizeBuckets(buckets)
+	return &HistogramVec{
+		name: name,
+		help: help,
+		vec:  newVec(labelNames, func() *Histogram { return newHistogram(name, help, b) }),
+	}
+}
+
+// With returns the histogram identified by the given label values, creating
+// it if it doesn't exist yet.
+// Panics if the number of values doesn't match the number of label names.
+func (h *HistogramVec) With(labelValues ...string) *Histogram {
+	return h.with(labelValues...)
+}
+
+// Collect implementation of `Collector`.
+func (h *HistogramVec) Collect() []Family {
+	family := Family{Name: h.name, Help: h.help, Type: TypeHistogram}
+	h.each(func(labels []Label, child *Histogram) {
+		family.Samples = append(family.Samples, child.samples(labels)...)
+	})
+	return []Family{family}
+}
@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounter(t *testing.T) {
+	c := NewCounter("test_counter", "help")
+	c.Inc()
+	c.Add(1.5)
+	assert.InEpsilon(t, 2.5, c.Value(), 0)
+
+	assert.Panics(t, func() {
+		c.Add(-1)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		c := NewCounter("test_counter", "help")
+		wg := sync.WaitGroup{}
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c.Inc()
+			}()
+		}
+		wg.Wait()
+		assert.InEpsilon(t, 100.0, c.Value(), 0)
+	})
+}
+
+func TestVec(t *testing.T) {
+	v := NewCounterVec("test_counter", "help", "a", "b")
+	assert.Same(t, v.With("1", "2"), v.With("1", "2"))
+	assert.NotSame(t, v.With("1", "2"), v.With("2", "1"))
+	assert.Panics(t, func() {
+		v.With("1")
+	})
+}
+
+func TestGauge(t *testing.T) {
+	g := NewGauge("test_gauge", "help")
+	g.Set(5)
+	g.Inc()
+	g.Dec()
+	g.Dec()
+	g.Add(-0.5)
+	assert.InEpsilon(t, 3.5, g.Value(), 0)
+
+	vec := NewGaugeVec("test_gauge_vec", "help", "name")
+	vec.With("a").Set(2)
+	families := vec.Collect()
+	require.Len(t, families, 1)
+	assert.Equal(t, []Sample{{Labels: []Label{{Name: "name", Value: "a"}}, Value: 2}}, families[0].Samples)
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram("test_histogram", "help", []float64{1, 0.5, 0.5})
+	h.Observe(0.1)
+	h.Observe(0.5)
+	h.Observe(0.7)
+	h.Observe(3)
+	assert.Equal(t, uint64(4), h.Count())
+	assert.InEpsilon(t, 4.3, h.Sum(), 0.0001)
+
+	registry := NewRegistry()
+	registry.Register(h)
+	buf := &bytes.Buffer{}
+	require.NoError(t, registry.WriteText(buf))
+
+	expected := `# HELP test_histogram help
+# TYPE test_histogram histogram
+test_histogram_bucket{le="0.5"} 2
+test_histogram_bucket{le="1"} 3
+test_histogram_bucket{le="+Inf"} 4
+test_histogram_sum 4.3
+test_histogram_count 4
+`
+	assert.Equal(t, expected, buf.String())
+
+	t.Run("default_buckets", func(t *testing.T) {
+		h := NewHistogram("test_histogram", "help", nil)
+		assert.Equal(t, DefaultBuckets, h.buckets)
+	})
+
+	t.Run("vec", func(t *testing.T) {
+		vec := NewHistogramVec("test_histogram", "help", []float64{1}, "route")
+		vec.With("a").Observe(2)
+		families := vec.Collect()
+		require.Len(t, families, 1)
+		assert.Equal(t, []Sample{
+			{Suffix: "_bucket", Labels: []Label{{Name: "route", Value: "a"}, {Name: "le", Value: "1"}}, Value: 0},
+			{Suffix: "_bucket", Labels: []Label{{Name: "route", Value: "a"}, {Name: "le", Value: "+Inf"}}, Value: 1},
+			{Suffix: "_sum", Labels: []Label{{Name: "route", Value: "a"}}, Value: 2},
+			{Suffix: "_count", Labels: []Label{{Name: "route", Value: "a"}}, Value: 1},
+		}, families[0].Samples)
+	})
+}
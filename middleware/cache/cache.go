@@ -0,0 +1,460 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+func init() {
+	config.Register("cache.sweepInterval", config.Entry{
+		Value:            600,
+		Type:             reflect.Int,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+}
+
+// HeaderStatus the response header indicating if the response was served from the cache:
+//   - "HIT": the response was served from the cache
+//   - "STALE": the response was served from the cache but is stale, it is being revalidated in the background
+//   - "MISS": the response was generated by the handler
+const HeaderStatus = "X-Cache"
+
+// ExtraTags the key used in `Context.Extra` to store the tags (`[]string`) associated
+// with the response of the current request. Use `Tag()` to add tags.
+type ExtraTags struct{}
+
+// ExtraInvalidatedTags the key used in `Context.Extra` to store the tags (`[]string`)
+// to invalidate once the current request has been handled. Use `Invalidate()` to add tags.
+type ExtraInvalidatedTags struct{}
+
+// cacheableStatuses the status codes that are heuristically cacheable (RFC 9110, 15.1).
+var cacheableStatuses = map[int]struct{}{
+	http.StatusOK:                   {},
+	http.StatusNonAuthoritativeInfo: {},
+	http.StatusNoContent:            {},
+	http.StatusMultipleChoices:      {},
+	http.StatusMovedPermanently:     {},
+	http.StatusPermanentRedirect:    {},
+	http.StatusNotFound:             {},
+	http.StatusMethodNotAllowed:     {},
+	http.StatusGone:                 {},
+	http.StatusRequestURITooLong:    {},
+	http.StatusNotImplemented:       {},
+}
+
+// Tag associates the given tags with the response of the current request so it can
+// be invalidated later using `Invalidate()` or `Store.InvalidateTags()`.
+func Tag(request *goyave.Request, tags ...string) {
+	existing, _ := request.Extra[ExtraTags{}].([]string)
+	request.Extra[ExtraTags{}] = append(existing, tags...)
+}
+
+// Invalidate deletes all the cached responses associated with at least one of the given tags
+// once the current request has been handled by the handler, if it didn't fail.
+// The current route must use the cache middleware for this to have an effect. Otherwise, use
+// `Store.InvalidateTags()` directly.
+func Invalidate(request *goyave.Request, tags ...string) {
+	existing, _ := request.Extra[ExtraInvalidatedTags{}].([]string)
+	request.Extra[ExtraInvalidatedTags{}] = append(existing, tags...)
+}
+
+// Middleware caching the full responses (status, headers and body) to "GET" and "HEAD" requests.
+//
+// Responses are identified by the request method, host, path, query (see `QueryParams`), the values of
+// the request headers listed in `VaryHeaders` and the user (see `UserID`). The "Cache-Control" response
+// header set by the handler is honored:
+//   - "no-store" and "no-cache" prevent the response from being cached
+//   - "private" prevents the response from being cached, unless `UserID` is defined
+//   - "s-maxage" or "max-age" define how long the response stays fresh (defaults to `TTL`)
+//   - "stale-while-revalidate" defines how long a stale response can still be served while
+//     it is revalidated in the background (defaults to `StaleWhileRevalidate`)
+//
+// Responses with a status that is not heuristically cacheable, setting cookies or having a
+// "Vary: *" header are not cached. If the response has a "Vary" header, the cached response is
+// only served to requests having the same values for the listed headers. Only the last variant is
+// kept, so headers frequently varying should be listed in `VaryHeaders` instead.
+//
+// Requests with a "Cache-Control: no-cache" header bypass the cache and replace the cached response.
+// If `UserID` is not defined, requests with an "Authorization" header bypass the cache entirely so
+// responses are not shared between users.
+//
+// When the server starts, a background sweeper deleting the expired entries from the store
+// is started every "cache.sweepInterval" seconds (if not zero). It is stopped when the server shuts down.
+type Middleware struct {
+	goyave.Component
+
+	// Store the response storage. Defaults to a new `MemoryStore` limited to 1000 entries.
+	Store Store
+
+	// UserID returns the identifier of the user making the request. If defined,
+	// the cached responses are scoped by user.
+	UserID func(request *goyave.Request) string
+
+	// QueryParams the names of the query parameters identifying the response.
+	// If `nil`, all the query parameters are used.
+	QueryParams []string
+
+	// VaryHeaders the names of the request headers identifying the response,
+	// for example "Accept-Language".
+	VaryHeaders []string
+
+	// TTL how long a response stays fresh if the handler doesn't define it with
+	// the "Cache-Control" header. Defaults to 1 minute.
+	TTL time.Duration
+
+	// StaleWhileRevalidate how long a stale response can still be served while it is
+	// revalidated in the background, if the handler doesn't define it with the
+	// "Cache-Control" header. Zero disables stale-while-revalidate.
+	StaleWhileRevalidate time.Duration
+
+	revalidating sync.Map
+	sweeper      sync.Once
+}
+
+// Init the middleware and register the sweeper lifecycle hooks.
+func (m *Middleware) Init(server *goyave.Server) {
+	m.Component.Init(server)
+	if m.Store == nil {
+		m.Store = NewMemoryStore(1000, 0)
+	}
+	m.sweeper.Do(func() {
+		m.registerSweeper(server)
+	})
+}
+
+// Handle serves the cached response if there is a usable one, or executes the
+// request and caches its response.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		defer m.invalidate(response, request)
+
+		method := request.Method()
+		if (method != http.MethodGet && method != http.MethodHead) ||
+			(m.UserID == nil && request.Header().Get("Authorization") != "") {
+			next(response, request)
+			return
+		}
+
+		key := m.key(request)
+		now := time.Now()
+		if _, noCache := parseCacheControl(request.Header().Get("Cache-Control"))["no-cache"]; !noCache {
+			entry, err := m.Store.Get(request.Context(), key)
+			if err != nil {
+				m.Logger().Error(err)
+			} else if entry != nil && entry.IsUsable(now) && matchVary(entry, request) {
+				if entry.IsFresh(now) {
+					m.serve(response, request, entry, "HIT", now)
+					return
+				}
+				m.serve(response, request, entry, "STALE", now)
+				m.revalidate(next, request, key)
+				return
+			}
+		}
+
+		writer := &writer{Writer: response.Writer()}
+		response.SetWriter(writer)
+		response.Header().Set(HeaderStatus, "MISS")
+		next(response, request)
+
+		if response.Hijacked() || response.GetError() != nil {
+			return
+		}
+		entry := m.newEntry(request, responseStatus(response), response.Header(), writer.body.Bytes(), now)
+		if entry == nil {
+			return
+		}
+		if err := m.Store.Set(context.WithoutCancel(request.Context()), key, entry); err != nil {
+			m.Logger().Error(err)
+		}
+	}
+}
+
+func (m *Middleware) invalidate(response *goyave.Response, request *goyave.Request) {
+	tags, _ := request.Extra[ExtraInvalidatedTags{}].([]string)
+	if len(tags) == 0 || response.GetError() != nil {
+		return
+	}
+	if err := m.Store.InvalidateTags(context.WithoutCancel(request.Context()), tags...); err != nil {
+		m.Logger().Error(err)
+	}
+}
+
+func (m *Middleware) key(request *goyave.Request) string {
+	query := request.URL().Query()
+	if m.QueryParams != nil {
+		selected := url.Values{}
+		for _, name := range m.QueryParams {
+			if values, ok := query[name]; ok {
+				selected[name] = values
+			}
+		}
+		query = selected
+	}
+	builder := strings.Builder{}
+	builder.WriteString(request.Method() + " " + strings.ToLower(request.Request().Host) + request.URL().Path + "?" + query.Encode() + "\n")
+	for _, name := range m.VaryHeaders {
+		builder.WriteString(http.CanonicalHeaderKey(name) + ": " + strings.Join(request.Header().Values(name), ", ") + "\n")
+	}
+	if m.UserID != nil {
+		builder.WriteString(m.UserID(request))
+	}
+	hash := sha256.Sum256([]byte(builder.String()))
+	return hex.EncodeToString(hash[:])
+}
+
+func (m *Middleware) newEntry(request *goyave.Request, status int, header http.Header, body []byte, now time.Time) *Entry {
+	if _, ok := cacheableStatuses[status]; !ok || len(header.Values("Set-Cookie")) > 0 {
+		return nil
+	}
+	directives := parseCacheControl(header.Get("Cache-Control"))
+	if _, ok := directives["no-store"]; ok {
+		return nil
+	}
+	if _, ok := directives["no-cache"]; ok {
+		return nil
+	}
+	if _, ok := directives["private"]; ok && m.UserID == nil {
+		return nil
+	}
+
+	ttl := m.TTL
+	if ttl == 0 {
+		ttl = time.Minute
+	}
+	if seconds, ok := directiveSeconds(directives, "s-maxage"); ok {
+		ttl = seconds
+	} else if seconds, ok := directiveSeconds(directives, "max-age"); ok {
+		ttl = seconds
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	stale := m.StaleWhileRevalidate
+	if seconds, ok := directiveSeconds(directives, "stale-while-revalidate"); ok {
+		stale = seconds
+	}
+
+	var vary map[string]string
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" {
+				return nil
+			}
+			if name == "" {
+				continue
+			}
+			if vary == nil {
+				vary = map[string]string{}
+			}
+			vary[http.CanonicalHeaderKey(name)] = strings.Join(request.Header().Values(name), ", ")
+		}
+	}
+
+	h := header.Clone()
+	h.Del(HeaderStatus)
+	h.Del("Age")
+	tags, _ := request.Extra[ExtraTags{}].([]string)
+	return &Entry{
+		StoredAt:   now,
+		ExpiresAt:  now.Add(ttl),
+		StaleUntil: now.Add(ttl + max(stale, 0)),
+		Header:     h,
+		Vary:       vary,
+		Body:       slices.Clone(body),
+		Tags:       slices.Clone(tags),
+		Status:     status,
+	}
+}
+
+func (m *Middleware) serve(response *goyave.Response, request *goyave.Request, entry *Entry, cacheStatus string, now time.Time) {
+	header := response.Header()
+	for k, v := range entry.Header {
+		header[k] = slices.Clone(v)
+	}
+	header.Set(HeaderStatus, cacheStatus)
+	header.Set("Age", strconv.FormatInt(int64(now.Sub(entry.StoredAt)/time.Second), 10))
+	response.Status(entry.Status)
+	if len(entry.Body) > 0 && request.Method() != http.MethodHead {
+		if _, err := response.Write(entry.Body); err != nil {
+			panic(errors.New(err))
+		}
+	}
+}
+
+// revalidate executes the handler in the background with a copy of the given request
+// and caches its response. Only one revalidation per key is executed at the same time.
+func (m *Middleware) revalidate(next goyave.Handler, request *goyave.Request, key string) {
+	if _, loaded := m.revalidating.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+
+	ctx := context.WithoutCancel(request.Context())
+	bgRequest := goyave.NewRequest(request.Request().Clone(ctx))
+	bgRequest.Data = request.Data
+	bgRequest.User = request.User
+	bgRequest.Query = request.Query
+	bgRequest.Lang = request.Lang
+	bgRequest.Route = request.Route
+	bgRequest.RouteParams = request.RouteParams
+	bgRequest.Extra = maps.Clone(request.Extra)
+	delete(bgRequest.Extra, ExtraTags{})
+	delete(bgRequest.Extra, ExtraInvalidatedTags{})
+
+	go func() {
+		defer m.revalidating.Delete(key)
+		defer func() {
+			if err := recover(); err != nil {
+				m.Logger().Error(errors.New(err))
+			}
+		}()
+
+		recorder := &recorder{header: http.Header{}}
+		bgResponse := goyave.NewResponse(m.Server(), bgRequest, recorder)
+		now := time.Now()
+		next(bgResponse, bgRequest)
+		if bgResponse.Hijacked() || bgResponse.GetError() != nil {
+			return
+		}
+		entry := m.newEntry(bgRequest, responseStatus(bgResponse), recorder.header, recorder.body.Bytes(), now)
+		if entry == nil {
+			return
+		}
+		if err := m.Store.Set(ctx, key, entry); err != nil {
+			m.Logger().Error(err)
+		}
+	}()
+}
+
+func (m *Middleware) registerSweeper(server *goyave.Server) {
+	interval := time.Duration(server.Config().GetInt("cache.sweepInterval")) * time.Second
+	if interval <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	server.RegisterStartupHook(func(s *goyave.Server) {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case now := <-ticker.C:
+					if err := m.Store.Sweep(ctx, now); err != nil && ctx.Err() == nil {
+						s.Logger.Error(errors.New(err))
+					}
+				}
+			}
+		}()
+	})
+	server.RegisterShutdownHook(func(_ *goyave.Server) {
+		cancel()
+	})
+}
+
+func matchVary(entry *Entry, request *goyave.Request) bool {
+	for name, value := range entry.Vary {
+		if strings.Join(request.Header().Values(name), ", ") != value {
+			return false
+		}
+	}
+	return true
+}
+
+// responseStatus returns the status that will be sent for the given response
+// once the handler has returned.
+func responseStatus(response *goyave.Response) int {
+	status := response.GetStatus()
+	if status == 0 {
+		if response.IsEmpty() {
+			return http.StatusNoContent
+		}
+		return http.StatusOK
+	}
+	return status
+}
+
+// parseCacheControl returns the directives of the given "Cache-Control" header value.
+// Directive names are lowercased.
+func parseCacheControl(header string) map[string]string {
+	directives := map[string]string{}
+	for _, directive := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if name == "" {
+			continue
+		}
+		directives[strings.ToLower(name)] = strings.Trim(value, `"`)
+	}
+	return directives
+}
+
+func directiveSeconds(directives map[string]string, name string) (time.Duration, bool) {
+	value, ok := directives[name]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// writer captures the response body so it can be cached.
+type writer struct {
+	io.Writer
+	body bytes.Buffer
+}
+
+func (w *writer) PreWrite(b []byte) {
+	if pr, ok := w.Writer.(goyave.PreWriter); ok {
+		pr.PreWrite(b)
+	}
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.Writer.Write(b)
+}
+
+func (w *writer) Close() error {
+	if wr, ok := w.Writer.(io.Closer); ok {
+		return wr.Close()
+	}
+	return nil
+}
+
+// recorder a minimal `http.ResponseWriter` used for background revalidation.
+type recorder struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *recorder) WriteHeader(_ int) {}
@@ -0,0 +1,384 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func prepareCacheTest(t *testing.T, middleware *Middleware, handler goyave.Handler) (*goyave.Router, *atomic.Int32) {
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+	router := goyave.NewRouter(server.Server)
+	router.Middleware(middleware)
+
+	calls := &atomic.Int32{}
+	router.Route([]string{http.MethodGet, http.MethodHead, http.MethodPost}, "/resource", func(response *goyave.Response, request *goyave.Request) {
+		n := calls.Add(1)
+		if handler != nil {
+			handler(response, request)
+			return
+		}
+		response.Header().Set("Content-Type", "text/plain")
+		response.String(http.StatusOK, fmt.Sprintf("response %d", n))
+	})
+	return router, calls
+}
+
+func serveCacheTest(t *testing.T, router *goyave.Router, method, path string, header http.Header) (*http.Response, string) {
+	req := httptest.NewRequest(method, path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	res := recorder.Result()
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, res.Body.Close())
+	require.NoError(t, err)
+	return res, string(body)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("default_store", func(t *testing.T) {
+		middleware := &Middleware{}
+		prepareCacheTest(t, middleware, nil)
+		require.IsType(t, &MemoryStore{}, middleware.Store)
+		assert.Equal(t, 1000, middleware.Store.(*MemoryStore).MaxEntries)
+	})
+
+	t.Run("hit", func(t *testing.T) {
+		router, calls := prepareCacheTest(t, &Middleware{}, nil)
+
+		res, body := serveCacheTest(t, router, http.MethodGet, "/resource?a=1&b=2", nil)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "MISS", res.Header.Get(HeaderStatus))
+		assert.Equal(t, "response 1", body)
+
+		res, body = serveCacheTest(t, router, http.MethodGet, "/resource?b=2&a=1", nil)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "HIT", res.Header.Get(HeaderStatus))
+		assert.Equal(t, "0", res.Header.Get("Age"))
+		assert.Equal(t, "text/plain", res.Header.Get("Content-Type"))
+		assert.Equal(t, "response 1", body)
+
+		_, body = serveCacheTest(t, router, http.MethodGet, "/resource?a=2", nil)
+		assert.Equal(t, "response 2", body, "different query")
+
+		res, _ = serveCacheTest(t, router, http.MethodHead, "/resource?a=1&b=2", nil)
+		assert.Equal(t, "MISS", res.Header.Get(HeaderStatus), "different method")
+		res, body = serveCacheTest(t, router, http.MethodHead, "/resource?a=1&b=2", nil)
+		assert.Equal(t, "HIT", res.Header.Get(HeaderStatus))
+		assert.Empty(t, body)
+
+		_, body = serveCacheTest(t, router, http.MethodGet, "/resource?a=1&b=2", http.Header{"Cache-Control": {"no-cache"}})
+		assert.Equal(t, "response 4", body, "client requested revalidation")
+		_, body = serveCacheTest(t, router, http.MethodGet, "/resource?a=1&b=2", nil)
+		assert.Equal(t, "response 4", body, "cached response replaced")
+
+		_, body = serveCacheTest(t, router, http.MethodPost, "/resource", nil)
+		assert.Equal(t, "response 5", body)
+		_, body = serveCacheTest(t, router, http.MethodPost, "/resource", nil)
+		assert.Equal(t, "response 6", body, "unsafe methods not cached")
+		assert.Equal(t, int32(6), calls.Load())
+	})
+
+	t.Run("query_params", func(t *testing.T) {
+		router, _ := prepareCacheTest(t, &Middleware{QueryParams: []string{"page"}}, nil)
+		serveCacheTest(t, router, http.MethodGet, "/resource?page=1&utm_source=a", nil)
+		res, _ := serveCacheTest(t, router, http.MethodGet, "/resource?page=1&utm_source=b", nil)
+		assert.Equal(t, "HIT", res.Header.Get(HeaderStatus))
+		res, _ = serveCacheTest(t, router, http.MethodGet, "/resource?page=2", nil)
+		assert.Equal(t, "MISS", res.Header.Get(HeaderStatus))
+	})
+
+	t.Run("vary", func(t *testing.T) {
+		router, _ := prepareCacheTest(t, &Middleware{VaryHeaders: []string{"Accept-Language"}}, func(response *goyave.Response, request *goyave.Request) {
+			response.Header().Set("Vary", "Accept-Encoding")
+			response.String(http.StatusOK, request.Header().Get("Accept-Language")+" "+request.Header().Get("Accept-Encoding"))
+		})
+
+		_, body := serveCacheTest(t, router, http.MethodGet, "/resource", http.Header{"Accept-Language": {"en"}, "Accept-Encoding": {"gzip"}})
+		assert.Equal(t, "en gzip", body)
+		_, body = serveCacheTest(t, router, http.MethodGet, "/resource", http.Header{"Accept-Language": {"fr"}, "Accept-Encoding": {"gzip"}})
+		assert.Equal(t, "fr gzip", body)
+		res, body := serveCacheTest(t, router, http.MethodGet, "/resource", http.Header{"Accept-Language": {"en"}, "Accept-Encoding": {"gzip"}})
+		assert.Equal(t, "HIT", res.Header.Get(HeaderStatus))
+		assert.Equal(t, "en gzip", body)
+		res, body = serveCacheTest(t, router, http.MethodGet, "/resource", http.Header{"Accept-Language": {"en"}, "Accept-Encoding": {"br"}})
+		assert.Equal(t, "MISS", res.Header.Get(HeaderStatus), "response Vary header honored")
+		assert.Equal(t, "en br", body)
+	})
+
+	t.Run("user", func(t *testing.T) {
+		router, _ := prepareCacheTest(t, &Middleware{}, nil)
+		serveCacheTest(t, router, http.MethodGet, "/resource", http.Header{"Authorization": {"Bearer a"}})
+		res, _ := serveCacheTest(t, router, http.MethodGet, "/resource", http.Header{"Authorization": {"Bearer a"}})
+		assert.Empty(t, res.Header.Get(HeaderStatus), "authenticated requests bypass the cache without UserID")
+
+		router, _ = prepareCacheTest(t, &Middleware{UserID: func(request *goyave.Request) string {
+			return request.Header().Get("Authorization")
+		}}, func(response *goyave.Response, request *goyave.Request) {
+			response.Header().Set("Cache-Control", "private, max-age=60")
+			response.String(http.StatusOK, request.Header().Get("Authorization"))
+		})
+		serveCacheTest(t, router, http.MethodGet, "/resource", http.Header{"Authorization": {"Bearer a"}})
+		res, body := serveCacheTest(t, router, http.MethodGet, "/resource", http.Header{"Authorization": {"Bearer a"}})
+		assert.Equal(t, "HIT", res.Header.Get(HeaderStatus))
+		assert.Equal(t, "Bearer a", body)
+		res, body = serveCacheTest(t, router, http.MethodGet, "/resource", http.Header{"Authorization": {"Bearer b"}})
+		assert.Equal(t, "MISS", res.Header.Get(HeaderStatus))
+		assert.Equal(t, "Bearer b", body)
+	})
+
+	t.Run("host", func(t *testing.T) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		router := goyave.NewRouter(server.Server)
+		router.Middleware(&Middleware{})
+		for _, tenant := range []string{"acme", "other"} {
+			tenant := tenant
+			router.Host(tenant+".example.com").Get("/dashboard", func(response *goyave.Response, _ *goyave.Request) {
+				response.String(http.StatusOK, tenant)
+			})
+		}
+
+		res, body := serveCacheTest(t, router, http.MethodGet, "http://acme.example.com/dashboard", nil)
+		assert.Equal(t, "MISS", res.Header.Get(HeaderStatus))
+		assert.Equal(t, "acme", body)
+		res, body = serveCacheTest(t, router, http.MethodGet, "http://other.example.com/dashboard", nil)
+		assert.Equal(t, "MISS", res.Header.Get(HeaderStatus))
+		assert.Equal(t, "other", body)
+		res, body = serveCacheTest(t, router, http.MethodGet, "http://ACME.example.com/dashboard", nil)
+		assert.Equal(t, "HIT", res.Header.Get(HeaderStatus))
+		assert.Equal(t, "acme", body)
+	})
+
+	t.Run("not_cacheable", func(t *testing.T) {
+		cases := []struct {
+			handler goyave.Handler
+			desc    string
+		}{
+			{desc: "no-store", handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("Cache-Control", "no-store")
+				response.String(http.StatusOK, "a")
+			}},
+			{desc: "no-cache", handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("Cache-Control", "No-Cache")
+				response.String(http.StatusOK, "a")
+			}},
+			{desc: "private", handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("Cache-Control", "private")
+				response.String(http.StatusOK, "a")
+			}},
+			{desc: "max-age=0", handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("Cache-Control", "max-age=0")
+				response.String(http.StatusOK, "a")
+			}},
+			{desc: "status", handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.String(http.StatusCreated, "a")
+			}},
+			{desc: "cookie", handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Cookie(&http.Cookie{Name: "a", Value: "b"})
+				response.String(http.StatusOK, "a")
+			}},
+			{desc: "vary_all", handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("Vary", "*")
+				response.String(http.StatusOK, "a")
+			}},
+			{desc: "error", handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Error(fmt.Errorf("test error"))
+			}},
+		}
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				store := NewMemoryStore(0, 0)
+				router, calls := prepareCacheTest(t, &Middleware{Store: store}, c.handler)
+				serveCacheTest(t, router, http.MethodGet, "/resource", nil)
+				serveCacheTest(t, router, http.MethodGet, "/resource", nil)
+				assert.Equal(t, int32(2), calls.Load())
+				assert.Equal(t, 0, store.Len())
+			})
+		}
+	})
+
+	t.Run("cache_control", func(t *testing.T) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		middleware := &Middleware{TTL: time.Hour}
+		middleware.Init(server.Server)
+		request := server.NewTestRequest(http.MethodGet, "/", nil)
+
+		cases := []struct {
+			cacheControl string
+			ttl          time.Duration
+			stale        time.Duration
+		}{
+			{cacheControl: "", ttl: time.Hour},
+			{cacheControl: "public", ttl: time.Hour},
+			{cacheControl: "max-age=30", ttl: 30 * time.Second},
+			{cacheControl: "max-age=30, s-maxage=\"10\"", ttl: 10 * time.Second},
+			{cacheControl: "Max-Age=invalid", ttl: time.Hour},
+			{cacheControl: "max-age=30, stale-while-revalidate=60", ttl: 30 * time.Second, stale: time.Minute},
+		}
+		for _, c := range cases {
+			now := time.Now()
+			entry := middleware.newEntry(request, http.StatusOK, http.Header{"Cache-Control": {c.cacheControl}}, nil, now)
+			require.NotNil(t, entry, c.cacheControl)
+			assert.Equal(t, now.Add(c.ttl), entry.ExpiresAt, c.cacheControl)
+			assert.Equal(t, now.Add(c.ttl+c.stale), entry.StaleUntil, c.cacheControl)
+		}
+
+		middleware = &Middleware{}
+		middleware.Init(server.Server)
+		now := time.Now()
+		entry := middleware.newEntry(request, http.StatusOK, http.Header{}, nil, now)
+		require.NotNil(t, entry)
+		assert.Equal(t, now.Add(time.Minute), entry.ExpiresAt, "default TTL")
+	})
+
+	t.Run("stale_while_revalidate", func(t *testing.T) {
+		store := NewMemoryStore(0, 0)
+		revalidated := make(chan struct{}, 1)
+		var calls atomic.Int32
+		router, _ := prepareCacheTest(t, &Middleware{Store: store, StaleWhileRevalidate: time.Minute}, func(response *goyave.Response, request *goyave.Request) {
+			n := calls.Add(1)
+			Tag(request, "resource")
+			response.Header().Set("Cache-Control", "max-age=1")
+			response.String(http.StatusOK, fmt.Sprintf("response %d", n))
+			if n > 1 {
+				revalidated <- struct{}{}
+			}
+		})
+
+		_, body := serveCacheTest(t, router, http.MethodGet, "/resource", nil)
+		assert.Equal(t, "response 1", body)
+
+		// Make the entry stale
+		for _, elem := range store.entries {
+			entry := elem.Value.(*memoryEntry).entry
+			entry.StoredAt = entry.StoredAt.Add(-2 * time.Second)
+			entry.ExpiresAt = entry.ExpiresAt.Add(-2 * time.Second)
+			assert.Equal(t, []string{"resource"}, entry.Tags)
+		}
+
+		res, body := serveCacheTest(t, router, http.MethodGet, "/resource", nil)
+		assert.Equal(t, "STALE", res.Header.Get(HeaderStatus))
+		assert.Equal(t, "2", res.Header.Get("Age"))
+		assert.Equal(t, "response 1", body)
+
+		select {
+		case <-revalidated:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timeout waiting for revalidation")
+		}
+		assert.Eventually(t, func() bool {
+			res, body := serveCacheTest(t, router, http.MethodGet, "/resource", nil)
+			return res.Header.Get(HeaderStatus) == "HIT" && body == "response 2"
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("revalidation_panic", func(t *testing.T) {
+		store := NewMemoryStore(0, 0)
+		var calls atomic.Int32
+		middleware := &Middleware{Store: store, StaleWhileRevalidate: time.Minute}
+		router, _ := prepareCacheTest(t, middleware, func(response *goyave.Response, _ *goyave.Request) {
+			if calls.Add(1) > 1 {
+				panic("test panic")
+			}
+			response.String(http.StatusOK, "response")
+		})
+		serveCacheTest(t, router, http.MethodGet, "/resource", nil)
+		for _, elem := range store.entries {
+			elem.Value.(*memoryEntry).entry.ExpiresAt = time.Now()
+		}
+		res, body := serveCacheTest(t, router, http.MethodGet, "/resource", nil)
+		assert.Equal(t, "STALE", res.Header.Get(HeaderStatus))
+		assert.Equal(t, "response", body)
+		assert.Eventually(t, func() bool {
+			revalidating := false
+			middleware.revalidating.Range(func(_, _ any) bool {
+				revalidating = true
+				return false
+			})
+			return !revalidating
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, int32(2), calls.Load())
+
+		res, _ = serveCacheTest(t, router, http.MethodGet, "/resource", nil)
+		assert.Equal(t, "STALE", res.Header.Get(HeaderStatus), "stale entry kept after failed revalidation")
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		store := NewMemoryStore(0, 0)
+		router, calls := prepareCacheTest(t, &Middleware{Store: store}, func(response *goyave.Response, request *goyave.Request) {
+			if request.Method() == http.MethodPost {
+				Invalidate(request, "resource")
+				return
+			}
+			Tag(request, "resource")
+			Tag(request, "other")
+			response.String(http.StatusOK, "response")
+		})
+		serveCacheTest(t, router, http.MethodGet, "/resource", nil)
+		assert.Equal(t, 1, store.Len())
+		serveCacheTest(t, router, http.MethodPost, "/resource", nil)
+		assert.Equal(t, 0, store.Len())
+		res, _ := serveCacheTest(t, router, http.MethodGet, "/resource", nil)
+		assert.Equal(t, "MISS", res.Header.Get(HeaderStatus))
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("store_error", func(t *testing.T) {
+		router, calls := prepareCacheTest(t, &Middleware{Store: &errorStore{}}, nil)
+		res, _ := serveCacheTest(t, router, http.MethodGet, "/resource", nil)
+		assert.Equal(t, http.StatusOK, res.StatusCode, "request handled normally if the store fails")
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("sweeper", func(t *testing.T) {
+		store := NewMemoryStore(0, 0)
+		require.NoError(t, store.Set(context.Background(), "expired", newTestEntry("expired", -time.Second)))
+		cfg := config.LoadDefault()
+		cfg.Set("server.port", 0)
+		cfg.Set("cache.sweepInterval", 1)
+		server, err := goyave.New(goyave.Options{Config: cfg})
+		require.NoError(t, err)
+		server.Router().GlobalMiddleware(&Middleware{Store: store})
+
+		wg := sync.WaitGroup{}
+		wg.Add(2)
+		server.RegisterStartupHook(func(s *goyave.Server) {
+			assert.Eventually(t, func() bool { return store.Len() == 0 }, 5*time.Second, 100*time.Millisecond)
+			s.Stop()
+			wg.Done()
+		})
+		go func() {
+			assert.NoError(t, server.Start())
+			wg.Done()
+		}()
+		wg.Wait()
+	})
+}
+
+type errorStore struct {
+	MemoryStore
+}
+
+func (s *errorStore) Get(_ context.Context, _ string) (*Entry, error) {
+	return nil, io.ErrUnexpectedEOF
+}
+
+func (s *errorStore) Set(_ context.Context, _ string, _ *Entry) error {
+	return io.ErrUnexpectedEOF
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	errorutil "goyave.dev/goyave/v5/util/errors"
+)
+
+// Model the database model used by `GORMStore`. Use it with `AutoMigrate`
+// or create the "cache_entries" table using your migration tool.
+type Model struct {
+	StaleUntil time.Time `gorm:"index;not null"`
+	ID         string    `gorm:"primaryKey;size:64"`
+
+	// Tags the tags of the entry, separated and surrounded by commas.
+	Tags string `gorm:"not null"`
+
+	// Data the entry encoded in JSON.
+	Data []byte `gorm:"not null"`
+}
+
+// TableName returns "cache_entries".
+func (Model) TableName() string {
+	return "cache_entries"
+}
+
+// GORMStore a `Store` keeping the entries in a database table using GORM. See `Model`.
+// Tags must not contain commas.
+type GORMStore struct {
+	DB *gorm.DB
+}
+
+// NewGORMStore create a new `GORMStore` using the given database.
+func NewGORMStore(db *gorm.DB) *GORMStore {
+	return &GORMStore{DB: db}
+}
+
+// Get returns the entry identified by the given key.
+func (s *GORMStore) Get(ctx context.Context, key string) (*Entry, error) {
+	model := &Model{}
+	err := s.DB.WithContext(ctx).Where("id = ? AND stale_until > ?", key, time.Now()).Take(model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errorutil.New(err)
+	}
+	entry := &Entry{}
+	if err := json.Unmarshal(model.Data, entry); err != nil {
+		return nil, errorutil.New(err)
+	}
+	return entry, nil
+}
+
+// Set the entry identified by the given key.
+func (s *GORMStore) Set(ctx context.Context, key string, entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return errorutil.New(err)
+	}
+	tags := ","
+	if len(entry.Tags) > 0 {
+		tags = "," + strings.Join(entry.Tags, ",") + ","
+	}
+	model := &Model{ID: key, Tags: tags, Data: data, StaleUntil: entry.StaleUntil}
+	return errorutil.New(s.DB.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(model).Error)
+}
+
+// Delete the entry identified by the given key.
+func (s *GORMStore) Delete(ctx context.Context, key string) error {
+	return errorutil.New(s.DB.WithContext(ctx).Where("id = ?", key).Delete(&Model{}).Error)
+}
+
+// InvalidateTags deletes all the entries associated with at least one of the given tags.
+func (s *GORMStore) InvalidateTags(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	escaper := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+	db := s.DB.WithContext(ctx)
+	conditions := s.DB.WithContext(ctx)
+	for i, tag := range tags {
+		pattern := "%," + escaper.Replace(tag) + ",%"
+		if i == 0 {
+			conditions = conditions.Where("tags LIKE ? ESCAPE '!'", pattern)
+		} else {
+			conditions = conditions.Or("tags LIKE ? ESCAPE '!'", pattern)
+		}
+	}
+	return errorutil.New(db.Where(conditions).Delete(&Model{}).Error)
+}
+
+// Sweep deletes all the entries that are not usable anymore at the given time.
+func (s *GORMStore) Sweep(ctx context.Context, now time.Time) error {
+	return errorutil.New(s.DB.WithContext(ctx).Where("stale_until <= ?", now).Delete(&Model{}).Error)
+}
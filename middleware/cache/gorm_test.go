@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestGORMStore(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:cache_gorm_test?mode=memory"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		assert.NoError(t, sqlDB.Close())
+	})
+	require.NoError(t, db.AutoMigrate(&Model{}))
+
+	store := NewGORMStore(db)
+	assert.Equal(t, db, store.DB)
+	testStore(t, store)
+
+	var count int64
+	require.NoError(t, db.Model(&Model{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	t.Run("error", func(t *testing.T) {
+		store := NewGORMStore(db.Table("missing_table"))
+		_, err := store.Get(context.Background(), "a")
+		assert.Error(t, err)
+		assert.Error(t, store.Set(context.Background(), "a", newTestEntry("a", time.Hour)))
+		assert.Error(t, store.InvalidateTags(context.Background(), "a"))
+
+		require.NoError(t, db.Create(&Model{ID: "invalid", Data: []byte("{"), StaleUntil: time.Now().Add(time.Hour)}).Error)
+		_, err = NewGORMStore(db).Get(context.Background(), "invalid")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,227 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Entry a cached response.
+type Entry struct {
+	// StoredAt the time at which the response was generated.
+	StoredAt time.Time `json:"storedAt"`
+
+	// ExpiresAt the time until which the entry is fresh.
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// StaleUntil the time until which the entry can still be served while
+	// it is revalidated in the background. Equal to `ExpiresAt` if
+	// stale-while-revalidate is disabled.
+	StaleUntil time.Time `json:"staleUntil"`
+
+	// Header the response headers.
+	Header http.Header `json:"header,omitempty"`
+
+	// Vary the values of the request headers listed in the "Vary" response header.
+	// The entry is only used for requests having the same values.
+	Vary map[string]string `json:"vary,omitempty"`
+
+	// Body the response body.
+	Body []byte `json:"body,omitempty"`
+
+	// Tags the tags associated with the entry. See `Tag()`.
+	Tags []string `json:"tags,omitempty"`
+
+	// Status the response status code.
+	Status int `json:"status"`
+}
+
+// IsFresh returns true if the entry can be served without revalidation at the given time.
+func (e *Entry) IsFresh(now time.Time) bool {
+	return now.Before(e.ExpiresAt)
+}
+
+// IsUsable returns true if the entry can be served at the given time, either
+// because it is fresh or because it is stale but can still be served while revalidating.
+func (e *Entry) IsUsable(now time.Time) bool {
+	return now.Before(e.StaleUntil)
+}
+
+func (e *Entry) size() int64 {
+	size := int64(len(e.Body))
+	for k, v := range e.Header {
+		size += int64(len(k))
+		for _, s := range v {
+			size += int64(len(s))
+		}
+	}
+	return size
+}
+
+// Store persists the cached responses.
+type Store interface {
+	// Get returns the entry identified by the given key, or `nil` if it doesn't
+	// exist or is not usable anymore (see `Entry.IsUsable()`).
+	Get(ctx context.Context, key string) (*Entry, error)
+
+	// Set the entry identified by the given key, replacing the existing one.
+	Set(ctx context.Context, key string, entry *Entry) error
+
+	// Delete the entry identified by the given key.
+	Delete(ctx context.Context, key string) error
+
+	// InvalidateTags deletes all the entries associated with at least one of the given tags.
+	InvalidateTags(ctx context.Context, tags ...string) error
+
+	// Sweep deletes all the entries that are not usable anymore at the given time.
+	Sweep(ctx context.Context, now time.Time) error
+}
+
+type memoryEntry struct {
+	entry *Entry
+	key   string
+	size  int64
+}
+
+// MemoryStore a `Store` keeping the entries in memory. When the maximum number
+// of entries or the maximum total size is reached, the least recently used entries
+// are evicted. The entries are not shared between multiple instances of the application.
+type MemoryStore struct {
+	entries map[string]*list.Element
+	tags    map[string]map[string]struct{}
+	lru     *list.List
+	size    int64
+
+	// MaxEntries the maximum number of entries. Zero means no limit.
+	MaxEntries int
+
+	// MaxSize the maximum total size of the stored bodies and headers in bytes.
+	// Zero means no limit.
+	MaxSize int64
+
+	mu sync.Mutex
+}
+
+// NewMemoryStore create a new empty `MemoryStore` with the given limits.
+// A zero limit means no limit.
+func NewMemoryStore(maxEntries int, maxSize int64) *MemoryStore {
+	return &MemoryStore{
+		entries:    map[string]*list.Element{},
+		tags:       map[string]map[string]struct{}{},
+		lru:        list.New(),
+		MaxEntries: maxEntries,
+		MaxSize:    maxSize,
+	}
+}
+
+// Get returns the entry identified by the given key and marks it as recently used.
+func (s *MemoryStore) Get(_ context.Context, key string) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elem, ok := s.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	e := elem.Value.(*memoryEntry)
+	if !e.entry.IsUsable(time.Now()) {
+		s.remove(elem)
+		return nil, nil
+	}
+	s.lru.MoveToFront(elem)
+	return e.entry, nil
+}
+
+// Set the entry identified by the given key, evicting the least recently
+// used entries if a limit is exceeded. Entries larger than `MaxSize` are not stored.
+func (s *MemoryStore) Set(_ context.Context, key string, entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem, ok := s.entries[key]; ok {
+		s.remove(elem)
+	}
+	e := &memoryEntry{key: key, entry: entry, size: entry.size()}
+	if s.MaxSize > 0 && e.size > s.MaxSize {
+		return nil
+	}
+	s.entries[key] = s.lru.PushFront(e)
+	s.size += e.size
+	for _, tag := range entry.Tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = map[string]struct{}{}
+			s.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	for (s.MaxEntries > 0 && s.lru.Len() > s.MaxEntries) || (s.MaxSize > 0 && s.size > s.MaxSize) {
+		s.remove(s.lru.Back())
+	}
+	return nil
+}
+
+// Delete the entry identified by the given key.
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elem, ok := s.entries[key]; ok {
+		s.remove(elem)
+	}
+	return nil
+}
+
+// InvalidateTags deletes all the entries associated with at least one of the given tags.
+func (s *MemoryStore) InvalidateTags(_ context.Context, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			if elem, ok := s.entries[key]; ok {
+				s.remove(elem)
+			}
+		}
+	}
+	return nil
+}
+
+// Sweep deletes all the entries that are not usable anymore at the given time.
+func (s *MemoryStore) Sweep(_ context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, elem := range s.entries {
+		if !elem.Value.(*memoryEntry).entry.IsUsable(now) {
+			s.remove(elem)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of entries in the store.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
+
+// Size returns the total size of the stored bodies and headers in bytes.
+func (s *MemoryStore) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+func (s *MemoryStore) remove(elem *list.Element) {
+	e := s.lru.Remove(elem).(*memoryEntry)
+	delete(s.entries, e.key)
+	s.size -= e.size
+	for _, tag := range e.entry.Tags {
+		if keys, ok := s.tags[tag]; ok {
+			delete(keys, e.key)
+			if len(keys) == 0 {
+				delete(s.tags, tag)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEntry(body string, ttl time.Duration, tags ...string) *Entry {
+	now := time.Now()
+	return &Entry{
+		StoredAt:   now,
+		ExpiresAt:  now.Add(ttl),
+		StaleUntil: now.Add(ttl),
+		Header:     http.Header{"Content-Type": {"text/plain"}},
+		Body:       []byte(body),
+		Tags:       tags,
+		Status:     http.StatusOK,
+	}
+}
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	entry, err := store.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+
+	require.NoError(t, store.Set(ctx, "a", newTestEntry("a", time.Hour, "users", "user:1")))
+	require.NoError(t, store.Set(ctx, "b", newTestEntry("b", time.Hour, "users")))
+	require.NoError(t, store.Set(ctx, "c", newTestEntry("c", time.Hour, "posts", "under_score")))
+	require.NoError(t, store.Set(ctx, "d", newTestEntry("d", time.Hour)))
+	require.NoError(t, store.Set(ctx, "expired", newTestEntry("expired", -time.Second)))
+
+	entry, err = store.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, "a", string(entry.Body))
+	assert.Equal(t, []string{"users", "user:1"}, entry.Tags)
+	assert.Equal(t, "text/plain", entry.Header.Get("Content-Type"))
+	assert.Equal(t, http.StatusOK, entry.Status)
+
+	entry, err = store.Get(ctx, "expired")
+	require.NoError(t, err)
+	assert.Nil(t, entry, "unusable entries are not returned")
+
+	require.NoError(t, store.Set(ctx, "d", newTestEntry("updated", time.Hour)))
+	entry, err = store.Get(ctx, "d")
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, "updated", string(entry.Body))
+
+	require.NoError(t, store.InvalidateTags(ctx, "user:1", "under%"))
+	entry, err = store.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+	entry, err = store.Get(ctx, "b")
+	require.NoError(t, err)
+	assert.NotNil(t, entry, "entries with other tags are kept")
+	entry, err = store.Get(ctx, "c")
+	require.NoError(t, err)
+	assert.NotNil(t, entry, "tags are matched exactly")
+
+	require.NoError(t, store.InvalidateTags(ctx, "users", "posts"))
+	require.NoError(t, store.InvalidateTags(ctx))
+	for _, key := range []string{"b", "c"} {
+		entry, err = store.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Nil(t, entry)
+	}
+
+	require.NoError(t, store.Delete(ctx, "d"))
+	entry, err = store.Get(ctx, "d")
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+
+	require.NoError(t, store.Set(ctx, "e", newTestEntry("e", time.Hour)))
+	require.NoError(t, store.Set(ctx, "stale", newTestEntry("stale", -time.Second)))
+	require.NoError(t, store.Sweep(ctx, time.Now()))
+	entry, err = store.Get(ctx, "e")
+	require.NoError(t, err)
+	assert.NotNil(t, entry)
+}
+
+func TestMemoryStore(t *testing.T) {
+	t.Run("Store", func(t *testing.T) {
+		store := NewMemoryStore(0, 0)
+		testStore(t, store)
+		assert.Equal(t, 1, store.Len())
+	})
+
+	t.Run("max_entries", func(t *testing.T) {
+		ctx := context.Background()
+		store := NewMemoryStore(2, 0)
+		require.NoError(t, store.Set(ctx, "a", newTestEntry("a", time.Hour, "tag")))
+		require.NoError(t, store.Set(ctx, "b", newTestEntry("b", time.Hour)))
+		_, err := store.Get(ctx, "a")
+		require.NoError(t, err)
+		require.NoError(t, store.Set(ctx, "c", newTestEntry("c", time.Hour)))
+
+		assert.Equal(t, 2, store.Len())
+		entry, err := store.Get(ctx, "b")
+		require.NoError(t, err)
+		assert.Nil(t, entry, "least recently used entry evicted")
+		entry, err = store.Get(ctx, "a")
+		require.NoError(t, err)
+		assert.NotNil(t, entry)
+	})
+
+	t.Run("max_size", func(t *testing.T) {
+		ctx := context.Background()
+		entrySize := newTestEntry(strings.Repeat("a", 100), time.Hour).size()
+		store := NewMemoryStore(0, entrySize*2)
+		require.NoError(t, store.Set(ctx, "a", newTestEntry(strings.Repeat("a", 100), time.Hour)))
+		require.NoError(t, store.Set(ctx, "b", newTestEntry(strings.Repeat("b", 100), time.Hour)))
+		assert.Equal(t, entrySize*2, store.Size())
+		require.NoError(t, store.Set(ctx, "c", newTestEntry(strings.Repeat("c", 100), time.Hour, "tag")))
+		assert.Equal(t, 2, store.Len())
+		assert.Equal(t, entrySize*2, store.Size())
+		entry, err := store.Get(ctx, "a")
+		require.NoError(t, err)
+		assert.Nil(t, entry)
+
+		require.NoError(t, store.Set(ctx, "large", newTestEntry(strings.Repeat("l", 1000), time.Hour)))
+		entry, err = store.Get(ctx, "large")
+		require.NoError(t, err)
+		assert.Nil(t, entry, "entries larger than the limit are not stored")
+		assert.Equal(t, 2, store.Len())
+
+		require.NoError(t, store.InvalidateTags(ctx, "tag"))
+		assert.Equal(t, entrySize, store.Size())
+		assert.Empty(t, store.tags)
+	})
+}
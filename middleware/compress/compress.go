@@ -2,8 +2,13 @@ package compress
 
 import (
 	"compress/gzip"
+	"compress/zlib"
 	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/samber/lo"
 	"goyave.dev/goyave/v5"
@@ -11,6 +16,29 @@ import (
 	"goyave.dev/goyave/v5/util/httputil"
 )
 
+// DefaultExcludedContentTypes the content types that are not compressed by default
+// because they are already compressed. Entries ending with "/*" match all subtypes.
+var DefaultExcludedContentTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"image/avif",
+	"image/heic",
+	"video/*",
+	"audio/*",
+	"font/woff",
+	"font/woff2",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/zstd",
+	"application/x-bzip2",
+	"application/x-xz",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+}
+
 // Encoder is an interface that wraps the methods returning the information
 // necessary for the compress middleware to work.
 //
@@ -27,30 +55,121 @@ type Encoder interface {
 	Encoding() string
 }
 
+// PooledEncoder an `Encoder` reusing its writers. `Release` is called by the
+// middleware with the writer returned by `NewWriter` once it has been closed.
+type PooledEncoder interface {
+	Encoder
+	Release(io.WriteCloser)
+}
+
 type compressWriter struct {
 	io.WriteCloser
-	http.ResponseWriter
+	response    *goyave.Response
 	childWriter io.Writer
+	encoder     Encoder
+	middleware  *Middleware
+	buffer      []byte
+	prepared    bool
+	decided     bool
 }
 
 func (w *compressWriter) PreWrite(b []byte) {
+	if !w.decided {
+		h := w.response.Header()
+		if !w.prepared {
+			w.prepared = true
+			if h.Get("Content-Type") == "" {
+				h.Set("Content-Type", http.DetectContentType(b))
+			}
+			if !w.response.IsHeaderWritten() && !lo.ContainsBy(h.Values("Vary"), func(v string) bool {
+				return strings.Contains(strings.ToLower(v), "accept-encoding")
+			}) {
+				h.Add("Vary", "Accept-Encoding")
+			}
+		}
+		if w.shouldBuffer(len(w.buffer) + len(b)) {
+			// Wait until enough bytes are written to decide.
+			w.response.DeferHeader()
+			return
+		}
+		w.decided = true
+		if len(w.buffer) > 0 {
+			b = append(w.buffer, b...)
+		}
+		if w.middleware.shouldCompress(w.response, len(b)) {
+			w.WriteCloser = w.encoder.NewWriter(w.childWriter)
+			h.Set("Content-Encoding", w.encoder.Encoding())
+			h.Del("Content-Length")
+			if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+				// The compressed representation is not byte-for-byte identical.
+				h.Set("ETag", "W/"+etag)
+			}
+		}
+	}
 	if pr, ok := w.childWriter.(goyave.PreWriter); ok {
 		pr.PreWrite(b)
 	}
-	h := w.ResponseWriter.Header()
-	if h.Get("Content-Type") == "" {
-		h.Set("Content-Type", http.DetectContentType(b))
-	}
-	h.Del("Content-Length")
+}
+
+// shouldBuffer returns true if the response would be compressed if it was large enough
+// but the given amount of written bytes is lower than `MinSize` and the `Content-Length`
+// header is not set.
+func (w *compressWriter) shouldBuffer(size int) bool {
+	return w.middleware.MinSize > 0 && size < w.middleware.MinSize &&
+		w.response.Header().Get("Content-Length") == "" &&
+		w.middleware.shouldCompress(w.response, w.middleware.MinSize)
 }
 
 func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.buffer = append(w.buffer, b...)
+		return len(b), nil
+	}
+	if len(w.buffer) > 0 {
+		buffer := w.buffer
+		w.buffer = nil
+		if _, err := w.write(buffer); err != nil {
+			return 0, err
+		}
+	}
+	return w.write(b)
+}
+
+func (w *compressWriter) write(b []byte) (int, error) {
+	if w.WriteCloser == nil {
+		n, err := w.childWriter.Write(b)
+		return n, errors.New(err)
+	}
 	n, err := w.WriteCloser.Write(b)
 	return n, errors.New(err)
 }
 
+// flush writes the buffered bytes uncompressed if the handler returned
+// before reaching `MinSize`.
+func (w *compressWriter) flush() error {
+	if w.decided || len(w.buffer) == 0 {
+		return nil
+	}
+	w.decided = true
+	if pr, ok := w.childWriter.(goyave.PreWriter); ok {
+		pr.PreWrite(w.buffer)
+	}
+	w.response.WriteHeader(w.response.GetStatus())
+	buffer := w.buffer
+	w.buffer = nil
+	_, err := w.write(buffer)
+	return err
+}
+
 func (w *compressWriter) Close() error {
-	err := errors.New(w.WriteCloser.Close())
+	err := w.flush()
+	if w.WriteCloser != nil {
+		err = errors.New(w.WriteCloser.Close())
+		if pooled, ok := w.encoder.(PooledEncoder); ok && err == nil {
+			pooled.Release(w.WriteCloser)
+		}
+		w.WriteCloser = nil
+	}
 
 	if wr, ok := w.childWriter.(io.Closer); ok {
 		return errors.New(wr.Close())
@@ -60,10 +179,12 @@ func (w *compressWriter) Close() error {
 }
 
 // Gzip encoder for the gzip format using Go's standard `compress/gzip` package.
+// Writers are pooled.
 //
 // Takes a compression level as parameter. Accepted values are defined by constants
 // in the standard `compress/gzip` package.
 type Gzip struct {
+	pool  sync.Pool
 	Level int
 }
 
@@ -72,9 +193,13 @@ func (w *Gzip) Encoding() string {
 	return "gzip"
 }
 
-// NewWriter returns a new `compress/gzip.Writer` using the compression level
-// defined in this Gzip encoder.
+// NewWriter returns a `compress/gzip.Writer` using the compression level
+// defined in this Gzip encoder, reusing a released writer if possible.
 func (w *Gzip) NewWriter(wr io.Writer) io.WriteCloser {
+	if writer, ok := w.pool.Get().(*gzip.Writer); ok {
+		writer.Reset(wr)
+		return writer
+	}
 	writer, err := gzip.NewWriterLevel(wr, w.Level)
 	if err != nil {
 		panic(errors.New(err))
@@ -82,6 +207,49 @@ func (w *Gzip) NewWriter(wr io.Writer) io.WriteCloser {
 	return writer
 }
 
+// Release puts the given writer back in the pool.
+func (w *Gzip) Release(writer io.WriteCloser) {
+	if gw, ok := writer.(*gzip.Writer); ok {
+		w.pool.Put(gw)
+	}
+}
+
+// Deflate encoder for the "deflate" format (zlib data format, RFC 1950) using
+// Go's standard `compress/zlib` package. Writers are pooled.
+//
+// Takes a compression level as parameter. Accepted values are defined by constants
+// in the standard `compress/zlib` package.
+type Deflate struct {
+	pool  sync.Pool
+	Level int
+}
+
+// Encoding returns "deflate".
+func (w *Deflate) Encoding() string {
+	return "deflate"
+}
+
+// NewWriter returns a `compress/zlib.Writer` using the compression level
+// defined in this Deflate encoder, reusing a released writer if possible.
+func (w *Deflate) NewWriter(wr io.Writer) io.WriteCloser {
+	if writer, ok := w.pool.Get().(*zlib.Writer); ok {
+		writer.Reset(wr)
+		return writer
+	}
+	writer, err := zlib.NewWriterLevel(wr, w.Level)
+	if err != nil {
+		panic(errors.New(err))
+	}
+	return writer
+}
+
+// Release puts the given writer back in the pool.
+func (w *Deflate) Release(writer io.WriteCloser) {
+	if zw, ok := writer.(*zlib.Writer); ok {
+		w.pool.Put(zw)
+	}
+}
+
 // Middleware compresses HTTP responses.
 //
 // This middleware supports multiple algorithms thanks to the `Encoders` slice.
@@ -100,6 +268,22 @@ func (w *Gzip) NewWriter(wr io.Writer) io.WriteCloser {
 // header is removed from the request to avoid potential clashes with potential other
 // encoding middleware.
 //
+// The decision to compress is made at the first call of `Write()`, right before the
+// header is written. The `Vary: Accept-Encoding` header is added to the response at
+// this moment. If `MinSize` is set and the response doesn't have a `Content-Length` header,
+// the written bytes are buffered and the header is not written until `MinSize` is reached
+// or the handler returns, so the decision can be made on the actual size of the body.
+// The response is not compressed if:
+//   - its header was already written explicitly with `WriteHeader()`
+//   - it already has a `Content-Encoding` header
+//   - it is a partial response (`206 Partial Content` or `Content-Range` header)
+//   - its size is lower than `MinSize`. The size is read from the `Content-Length` header if
+//     set, otherwise it is the amount of bytes written
+//   - its content type is not allowed by `ContentTypes` or `ExcludedContentTypes`
+//
+// If the response is compressed, the `Content-Length` header is removed and strong
+// `ETag` are converted to weak `ETag`.
+//
 // If not set at the first call of `Write()`, the middleware will automatically detect
 // and set the `Content-Type` header using `http.DetectContentType()`.
 //
@@ -110,11 +294,25 @@ func (w *Gzip) NewWriter(wr io.Writer) io.WriteCloser {
 //	compressMiddleware := &compress.Middleware{
 //		Encoders: []compress.Encoder{
 //			&compress.Gzip{Level: gzip.BestCompression},
+//			&compress.Deflate{Level: zlib.BestCompression},
 //		},
+//		MinSize: 1024,
 //	}
 type Middleware struct {
 	goyave.Component
 	Encoders []Encoder
+
+	// ContentTypes if not empty, only the responses having one of these content types
+	// are compressed. Entries ending with "/*" match all subtypes (e.g. "text/*").
+	ContentTypes []string
+
+	// ExcludedContentTypes the responses having one of these content types are not compressed.
+	// Entries ending with "/*" match all subtypes. If `nil`, `DefaultExcludedContentTypes` is used.
+	ExcludedContentTypes []string
+
+	// MinSize the minimum size of a response body in bytes for it to be compressed.
+	// Compressing small bodies is often counterproductive. Zero means no minimum.
+	MinSize int
 }
 
 // Handle implementation of `goyave.Middleware`.
@@ -130,14 +328,20 @@ func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
 
 		respWriter := response.Writer()
 		compressWriter := &compressWriter{
-			WriteCloser:    encoder.NewWriter(respWriter),
-			ResponseWriter: response,
-			childWriter:    respWriter,
+			response:    response,
+			childWriter: respWriter,
+			encoder:     encoder,
+			middleware:  m,
 		}
 		response.SetWriter(compressWriter)
-		response.Header().Set("Content-Encoding", encoder.Encoding())
 
 		next(response, request)
+
+		// The body is complete: write what's left in the buffer so the
+		// parent middleware can see the whole response.
+		if err := compressWriter.flush(); err != nil {
+			m.Logger().Error(err)
+		}
 	}
 }
 
@@ -165,3 +369,45 @@ func (m *Middleware) getEncoder(response *goyave.Response, request *goyave.Reque
 
 	return nil
 }
+
+func (m *Middleware) shouldCompress(response *goyave.Response, size int) bool {
+	h := response.Header()
+	if response.IsHeaderWritten() {
+		return false
+	}
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" || response.GetStatus() == http.StatusPartialContent {
+		return false
+	}
+
+	if m.MinSize > 0 {
+		if contentLength, err := strconv.Atoi(h.Get("Content-Length")); err == nil {
+			size = contentLength
+		}
+		if size < m.MinSize {
+			return false
+		}
+	}
+
+	contentType, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		return len(m.ContentTypes) == 0
+	}
+	if len(m.ContentTypes) > 0 && !matchContentType(m.ContentTypes, contentType) {
+		return false
+	}
+	excluded := m.ExcludedContentTypes
+	if excluded == nil {
+		excluded = DefaultExcludedContentTypes
+	}
+	return !matchContentType(excluded, contentType)
+}
+
+func matchContentType(patterns []string, contentType string) bool {
+	return lo.ContainsBy(patterns, func(pattern string) bool {
+		pattern = strings.ToLower(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+			return strings.HasPrefix(contentType, prefix+"/")
+		}
+		return pattern == contentType
+	})
+}
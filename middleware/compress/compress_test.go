@@ -3,9 +3,11 @@ package compress
 import (
 	"bytes"
 	"compress/gzip"
+	"compress/zlib"
+	"fmt"
 	"io"
 	"net/http"
-	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -165,6 +167,319 @@ func TestCompressMiddleware(t *testing.T) {
 
 }
 
+func readBody(t *testing.T, result *http.Response) string {
+	t.Helper()
+	var reader io.Reader = result.Body
+	switch result.Header.Get("Content-Encoding") {
+	case "gzip":
+		r, err := gzip.NewReader(result.Body)
+		require.NoError(t, err)
+		reader = r
+	case "deflate":
+		r, err := zlib.NewReader(result.Body)
+		require.NoError(t, err)
+		reader = r
+	}
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.NoError(t, result.Body.Close())
+	return string(body)
+}
+
+func TestCompressMiddlewareConditions(t *testing.T) {
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+
+	longBody := strings.Repeat("hello world ", 100)
+
+	compressMiddleware := &Middleware{
+		Encoders: []Encoder{
+			&Gzip{Level: gzip.BestCompression},
+			&Deflate{Level: zlib.BestCompression},
+		},
+		MinSize: 512,
+	}
+
+	cases := []struct {
+		handler          goyave.Handler
+		middleware       *Middleware
+		desc             string
+		acceptEncoding   string
+		wantEncoding     string
+		wantBody         string
+		wantETag         string
+		wantVary         []string
+		wantStatus       int
+		wantContentRange bool
+	}{
+		{
+			desc:           "deflate",
+			acceptEncoding: "deflate",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.String(http.StatusOK, longBody)
+			},
+			wantEncoding: "deflate",
+			wantBody:     longBody,
+			wantStatus:   http.StatusOK,
+			wantVary:     []string{"Accept-Encoding"},
+		},
+		{
+			desc:           "below_threshold",
+			acceptEncoding: "gzip",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.String(http.StatusOK, "hello world")
+			},
+			wantBody:   "hello world",
+			wantStatus: http.StatusOK,
+			wantVary:   []string{"Accept-Encoding"},
+		},
+		{
+			desc:           "threshold_content_length",
+			acceptEncoding: "gzip",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("Content-Length", "1211")
+				response.Status(http.StatusOK)
+				_, _ = response.Write([]byte("hello world"))
+				_, _ = response.Write([]byte(longBody))
+			},
+			wantEncoding: "gzip",
+			wantBody:     "hello world" + longBody,
+			wantStatus:   http.StatusOK,
+			wantVary:     []string{"Accept-Encoding"},
+		},
+		{
+			desc:           "small_writes_above_threshold",
+			acceptEncoding: "gzip",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("ETag", `"abc"`)
+				for i := 0; i < 100; i++ {
+					_, _ = fmt.Fprint(response, "hello world ")
+				}
+			},
+			wantEncoding: "gzip",
+			wantBody:     longBody,
+			wantETag:     `W/"abc"`,
+			wantStatus:   http.StatusOK,
+			wantVary:     []string{"Accept-Encoding"},
+		},
+		{
+			desc:           "small_writes_below_threshold",
+			acceptEncoding: "gzip",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Status(http.StatusCreated)
+				for i := 0; i < 3; i++ {
+					_, _ = fmt.Fprint(response, "hello world ")
+				}
+			},
+			wantBody:   "hello world hello world hello world ",
+			wantStatus: http.StatusCreated,
+			wantVary:   []string{"Accept-Encoding"},
+		},
+		{
+			desc:           "header_already_written",
+			acceptEncoding: "gzip",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.WriteHeader(http.StatusOK)
+				_, _ = response.Write([]byte(longBody))
+			},
+			wantBody:   longBody,
+			wantStatus: http.StatusOK,
+		},
+		{
+			desc:           "excluded_content_type",
+			acceptEncoding: "gzip",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("Content-Type", "image/png")
+				response.String(http.StatusOK, longBody)
+			},
+			wantBody:   longBody,
+			wantStatus: http.StatusOK,
+			wantVary:   []string{"Accept-Encoding"},
+		},
+		{
+			desc:           "excluded_content_type_wildcard",
+			acceptEncoding: "gzip",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("Content-Type", "video/mp4")
+				response.String(http.StatusOK, longBody)
+			},
+			wantBody:   longBody,
+			wantStatus: http.StatusOK,
+			wantVary:   []string{"Accept-Encoding"},
+		},
+		{
+			desc:           "allowed_content_types",
+			acceptEncoding: "gzip",
+			middleware: &Middleware{
+				Encoders:     []Encoder{&Gzip{Level: gzip.BestCompression}},
+				ContentTypes: []string{"application/json", "text/*"},
+			},
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("Content-Type", "text/html; charset=utf-8")
+				response.String(http.StatusOK, longBody)
+			},
+			wantEncoding: "gzip",
+			wantBody:     longBody,
+			wantStatus:   http.StatusOK,
+			wantVary:     []string{"Accept-Encoding"},
+		},
+		{
+			desc:           "not_allowed_content_type",
+			acceptEncoding: "gzip",
+			middleware: &Middleware{
+				Encoders:     []Encoder{&Gzip{Level: gzip.BestCompression}},
+				ContentTypes: []string{"application/json", "text/*"},
+			},
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("Content-Type", "application/xml")
+				response.String(http.StatusOK, longBody)
+			},
+			wantBody:   longBody,
+			wantStatus: http.StatusOK,
+			wantVary:   []string{"Accept-Encoding"},
+		},
+		{
+			desc:           "custom_excluded_content_types",
+			acceptEncoding: "gzip",
+			middleware: &Middleware{
+				Encoders:             []Encoder{&Gzip{Level: gzip.BestCompression}},
+				ExcludedContentTypes: []string{},
+			},
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("Content-Type", "image/png")
+				response.String(http.StatusOK, longBody)
+			},
+			wantEncoding: "gzip",
+			wantBody:     longBody,
+			wantStatus:   http.StatusOK,
+			wantVary:     []string{"Accept-Encoding"},
+		},
+		{
+			desc:           "strong_etag",
+			acceptEncoding: "gzip",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("ETag", `"abc"`)
+				response.String(http.StatusOK, longBody)
+			},
+			wantEncoding: "gzip",
+			wantBody:     longBody,
+			wantETag:     `W/"abc"`,
+			wantStatus:   http.StatusOK,
+			wantVary:     []string{"Accept-Encoding"},
+		},
+		{
+			desc:           "weak_etag",
+			acceptEncoding: "gzip",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("ETag", `W/"abc"`)
+				response.String(http.StatusOK, longBody)
+			},
+			wantEncoding: "gzip",
+			wantBody:     longBody,
+			wantETag:     `W/"abc"`,
+			wantStatus:   http.StatusOK,
+			wantVary:     []string{"Accept-Encoding"},
+		},
+		{
+			desc:           "etag_not_compressed",
+			acceptEncoding: "gzip",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("ETag", `"abc"`)
+				response.String(http.StatusOK, "hello world")
+			},
+			wantBody:   "hello world",
+			wantETag:   `"abc"`,
+			wantStatus: http.StatusOK,
+			wantVary:   []string{"Accept-Encoding"},
+		},
+		{
+			desc:           "partial_content",
+			acceptEncoding: "gzip",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("Content-Range", "bytes 0-1199/5000")
+				response.String(http.StatusPartialContent, longBody)
+			},
+			wantBody:         longBody,
+			wantStatus:       http.StatusPartialContent,
+			wantVary:         []string{"Accept-Encoding"},
+			wantContentRange: true,
+		},
+		{
+			desc:           "already_encoded",
+			acceptEncoding: "gzip",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Set("Content-Encoding", "br")
+				response.String(http.StatusOK, longBody)
+			},
+			wantEncoding: "br",
+			wantBody:     longBody,
+			wantStatus:   http.StatusOK,
+			wantVary:     []string{"Accept-Encoding"},
+		},
+		{
+			desc:           "existing_vary",
+			acceptEncoding: "gzip",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Header().Add("Vary", "Origin")
+				response.Header().Add("Vary", "accept-encoding")
+				response.String(http.StatusOK, longBody)
+			},
+			wantEncoding: "gzip",
+			wantBody:     longBody,
+			wantStatus:   http.StatusOK,
+			wantVary:     []string{"Origin", "accept-encoding"},
+		},
+		{
+			desc:           "empty_body",
+			acceptEncoding: "gzip",
+			middleware: &Middleware{
+				Encoders: []Encoder{&Gzip{Level: gzip.BestCompression}},
+			},
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.Status(http.StatusNoContent)
+			},
+			wantBody:   "",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			desc: "no_accepted_encoding",
+			handler: func(response *goyave.Response, _ *goyave.Request) {
+				response.String(http.StatusOK, longBody)
+			},
+			wantBody:   longBody,
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			middleware := compressMiddleware
+			if c.middleware != nil {
+				middleware = c.middleware
+			}
+			request := testutil.NewTestRequest(http.MethodGet, "/", nil)
+			if c.acceptEncoding != "" {
+				request.Header().Set("Accept-Encoding", c.acceptEncoding)
+			}
+			result := server.TestMiddleware(middleware, request, c.handler)
+
+			assert.Equal(t, c.wantStatus, result.StatusCode)
+			assert.Equal(t, c.wantEncoding, result.Header.Get("Content-Encoding"))
+			assert.Equal(t, c.wantVary, result.Header.Values("Vary"))
+			assert.Equal(t, c.wantETag, result.Header.Get("ETag"))
+			assert.Equal(t, c.wantContentRange, result.Header.Get("Content-Range") != "")
+			if c.wantEncoding == "br" {
+				body, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				assert.NoError(t, result.Body.Close())
+				assert.Equal(t, c.wantBody, string(body))
+				return
+			}
+			assert.Equal(t, c.wantBody, readBody(t, result))
+		})
+	}
+}
+
 func TestGzipEncoder(t *testing.T) {
 	encoder := &Gzip{
 		Level: gzip.BestCompression,
@@ -188,6 +503,47 @@ func TestGzipEncoder(t *testing.T) {
 	})
 }
 
+func TestDeflateEncoder(t *testing.T) {
+	encoder := &Deflate{
+		Level: zlib.BestCompression,
+	}
+	assert.Equal(t, "deflate", encoder.Encoding())
+	assert.Equal(t, zlib.BestCompression, encoder.Level)
+
+	buf := bytes.NewBuffer([]byte{})
+	writer := encoder.NewWriter(buf)
+	if assert.NotNil(t, writer) {
+		_, ok := writer.(*zlib.Writer)
+		assert.True(t, ok)
+	}
+	_, err := writer.Write([]byte("hello world"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	encoder.Release(writer)
+
+	// Released writer is reset and reused
+	buf2 := bytes.NewBuffer([]byte{})
+	writer2 := encoder.NewWriter(buf2)
+	_, err = writer2.Write([]byte("hello world"))
+	require.NoError(t, err)
+	require.NoError(t, writer2.Close())
+	assert.Equal(t, buf.Bytes(), buf2.Bytes())
+
+	reader, err := zlib.NewReader(buf2)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(body))
+
+	assert.Panics(t, func() {
+		// Invalid level
+		encoder := &Deflate{
+			Level: -3,
+		}
+		encoder.NewWriter(bytes.NewBuffer([]byte{}))
+	})
+}
+
 func TestCompressWriter(t *testing.T) {
 	encoder := &Gzip{
 		Level: gzip.BestCompression,
@@ -199,20 +555,40 @@ func TestCompressWriter(t *testing.T) {
 		closed: false,
 	}
 
-	response := httptest.NewRecorder()
+	request := testutil.NewTestRequest(http.MethodGet, "/", nil)
+	response, _ := testutil.NewTestResponse(request)
 
 	writer := &compressWriter{
-		WriteCloser:    encoder.NewWriter(closeableWriter),
-		ResponseWriter: response,
-		childWriter:    closeableWriter,
+		response:    response,
+		childWriter: closeableWriter,
+		encoder:     encoder,
+		middleware:  &Middleware{},
 	}
 
 	writer.PreWrite([]byte("hello world"))
 
 	assert.True(t, closeableWriter.preWritten)
+	assert.True(t, writer.decided)
+	assert.NotNil(t, writer.WriteCloser)
+	assert.Equal(t, "gzip", response.Header().Get("Content-Encoding"))
+
+	_, err := writer.Write([]byte("hello world"))
+	require.NoError(t, err)
 
 	require.NoError(t, writer.Close())
 	assert.True(t, closeableWriter.closed)
+	assert.Nil(t, writer.WriteCloser)
+
+	reader, err := gzip.NewReader(buf)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(body))
+
+	// The writer has been released and is reused
+	w := encoder.NewWriter(io.Discard)
+	assert.NotNil(t, w)
+	assert.NoError(t, w.Close())
 }
 
 type testEncoder struct {
@@ -0,0 +1,341 @@
+package csrf
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"html/template"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/cors"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// MetaExempt the CSRF middleware skips the routes having this meta (or any of
+// their parent routers) set to `true`.
+const MetaExempt = "goyave.csrf-exempt"
+
+// ExtraToken the key used in `Context.Extra` to store the CSRF token (`string`) of the
+// client so it can be embedded in forms or pages. Use `Token()` to retrieve it.
+type ExtraToken struct{}
+
+// RegisterViewHelpers registers the `csrfToken` and `csrfField` view helpers (see `goyave.RegisterViewHelper()`).
+// `csrfToken` returns the CSRF token of the client and `csrfField` renders a hidden "_csrf" form
+// field containing it. Like all view helpers, they must be registered before the views are loaded,
+// that is before creating the server if the views are loaded with the `goyave.Options.ViewsFS` option.
+func RegisterViewHelpers() {
+	goyave.RegisterViewHelper("csrfToken", func(request *goyave.Request) any {
+		return func() string {
+			return Token(request)
+		}
+	})
+	goyave.RegisterViewHelper("csrfField", func(request *goyave.Request) any {
+		return func() template.HTML {
+			return template.HTML(`<input type="hidden" name="_csrf" value="` + template.HTMLEscapeString(Token(request)) + `">`)
+		}
+	})
+}
+
+// Store persists the CSRF token of a client. The storage defines the strategy
+// used by the middleware:
+//   - Synchronizer token: the token is stored server-side (for example in the session, see `session.CSRFStore`)
+//     and the client has to submit it with each unsafe request.
+//   - Double-submit cookie: the token is stored in a cookie (see `CookieStore`) and the client
+//     has to submit the same value with each unsafe request.
+type Store interface {
+	// Get returns the token stored for the client of the given request,
+	// or an empty string if there is none.
+	Get(request *goyave.Request) string
+
+	// Save persists the given token for the client of the given request.
+	Save(response *goyave.Response, request *goyave.Request, token string)
+}
+
+// CookieStore a `Store` implementing the double-submit cookie strategy. The cookie is
+// not `HttpOnly` so it can be read by client scripts and sent back in a header.
+type CookieStore struct {
+	// Name the name of the cookie. Defaults to "csrf_token".
+	Name string
+
+	// Path the path of the cookie. Defaults to "/".
+	Path string
+
+	// Domain the domain of the cookie. Defaults to the "server.domain" config entry
+	// if the store is used by the middleware.
+	Domain string
+
+	// SameSite the SameSite attribute of the cookie. Defaults to `http.SameSiteLaxMode`.
+	SameSite http.SameSite
+
+	// Secure if true, the cookie is only sent over HTTPS.
+	Secure bool
+}
+
+func (s *CookieStore) name() string {
+	if s.Name == "" {
+		return "csrf_token"
+	}
+	return s.Name
+}
+
+// Get returns the value of the token cookie.
+func (s *CookieStore) Get(request *goyave.Request) string {
+	cookie, err := request.Request().Cookie(s.name())
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// Save sets the token cookie.
+func (s *CookieStore) Save(response *goyave.Response, _ *goyave.Request, token string) {
+	response.Cookie(&http.Cookie{
+		Name:     s.name(),
+		Value:    token,
+		Path:     valueOr(s.Path, "/"),
+		Domain:   s.Domain,
+		SameSite: valueOr(s.SameSite, http.SameSiteLaxMode),
+		Secure:   s.Secure,
+	})
+}
+
+// Middleware protecting unsafe requests (all methods except GET, HEAD, OPTIONS and TRACE)
+// against Cross-Site Request Forgery.
+//
+// For every request, the CSRF token of the client is retrieved from the store (or generated and
+// saved if the client doesn't have one yet) and put in the request's extra with the key `ExtraToken`.
+//
+// For unsafe requests:
+//   - the origin (scheme, host and port) of the `Origin` header (or the `Referer` header if there
+//     is no `Origin`) must match the origin of the application or one of the `TrustedOrigins`.
+//     If "server.proxy.host" is set, the origin of the application is the origin of the proxy
+//     base URL. Otherwise, the scheme is "https" if the request uses TLS, "http" otherwise,
+//     and the host is the "server.domain" config entry (or the request host if not set) with the
+//     port of the request. Routes belonging to a host router always use the request host.
+//     The check is skipped if none of these headers are present.
+//   - the token submitted in the header (`HeaderName`) or the form field (`FieldName`) must
+//     match the stored token. The form field is read from the request's `Data`, so
+//     this middleware must be executed after the `parse` middleware.
+//
+// If the check fails, returns "403 Forbidden" with a localized error message.
+//
+// Routes (or routers) with the `MetaExempt` meta set to `true` are not checked.
+// If `SkipPreflighted` is enabled, requests to routes with CORS options that cannot
+// be sent without a CORS preflight request are not checked either.
+type Middleware struct {
+	goyave.Component
+
+	// Store the token storage. Defaults to a `CookieStore` with default settings (double-submit cookie).
+	Store Store
+
+	// HeaderName the name of the header in which the token can be submitted.
+	// Defaults to "X-CSRF-Token".
+	HeaderName string
+
+	// FieldName the name of the form field in which the token can be submitted.
+	// Defaults to "_csrf".
+	FieldName string
+
+	// TrustedOrigins additional origins (e.g. "https://app.example.org") unsafe requests
+	// are allowed to originate from. An entry without scheme (e.g. "app.example.org") trusts
+	// the host regardless of the scheme and port.
+	TrustedOrigins []string
+
+	// SkipPreflighted if true, requests to routes having CORS options (`cors.Options`) are
+	// not checked if their content type is not one of the types allowed in simple requests
+	// ("application/x-www-form-urlencoded", "multipart/form-data" and "text/plain"). Such
+	// requests are always preceded by a CORS preflight request, which already protects
+	// against cross-site requests. This is typically the case of JSON APIs.
+	SkipPreflighted bool
+}
+
+// Init the middleware. Sets the default cookie domain if the store is the default `CookieStore`.
+func (m *Middleware) Init(server *goyave.Server) {
+	m.Component.Init(server)
+	if m.Store == nil {
+		m.Store = &CookieStore{Domain: server.Config().GetString("server.domain")}
+	}
+}
+
+// Handle checks the CSRF token and origin of unsafe requests.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		token := m.Store.Get(request)
+		if token == "" {
+			token = generateToken()
+			m.Store.Save(response, request, token)
+		}
+		request.Extra[ExtraToken{}] = token
+
+		if isSafeMethod(request.Method()) || m.isExempt(request) {
+			next(response, request)
+			return
+		}
+
+		if !m.checkOrigin(request) {
+			response.JSON(http.StatusForbidden, map[string]string{"error": request.Lang.Get("csrf.origin-mismatch")})
+			return
+		}
+
+		submitted := m.submittedToken(request)
+		if submitted == "" || subtle.ConstantTimeCompare([]byte(submitted), []byte(token)) != 1 {
+			response.JSON(http.StatusForbidden, map[string]string{"error": request.Lang.Get("csrf.token-mismatch")})
+			return
+		}
+		next(response, request)
+	}
+}
+
+func (m *Middleware) isExempt(request *goyave.Request) bool {
+	if exempt, ok := request.Route.LookupMeta(MetaExempt); ok && exempt == true {
+		return true
+	}
+	if !m.SkipPreflighted {
+		return false
+	}
+	corsOptions, ok := request.Route.LookupMeta(goyave.MetaCORS)
+	if !ok || corsOptions == (*cors.Options)(nil) {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(request.Header().Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return mediaType != "application/x-www-form-urlencoded" && mediaType != "multipart/form-data" && mediaType != "text/plain"
+}
+
+func (m *Middleware) checkOrigin(request *goyave.Request) bool {
+	origin := request.Header().Get("Origin")
+	if origin == "" {
+		origin = request.Referrer()
+		if origin == "" {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	normalized := normalizeOrigin(u.Scheme, u.Host)
+
+	return normalized == m.expectedOrigin(request) || slices.ContainsFunc(m.TrustedOrigins, func(o string) bool {
+		if !strings.Contains(o, "://") {
+			return strings.EqualFold(o, u.Hostname())
+		}
+		trusted, err := url.Parse(o)
+		return err == nil && normalizeOrigin(trusted.Scheme, trusted.Host) == normalized
+	})
+}
+
+// expectedOrigin returns the origin of the application as seen by the client.
+func (m *Middleware) expectedOrigin(request *goyave.Request) string {
+	requestHost := request.Request().Host
+	hostRouter := isInHostRouter(request.Route)
+	if m.Config().Has("server.proxy.host") {
+		base, err := url.Parse(m.Server().ProxyBaseURL())
+		if err != nil {
+			return ""
+		}
+		host := base.Host
+		if hostRouter {
+			host = withPort((&url.URL{Host: requestHost}).Hostname(), base.Port())
+		}
+		return normalizeOrigin(base.Scheme, host)
+	}
+
+	scheme := "http"
+	if request.Request().TLS != nil {
+		scheme = "https"
+	}
+	host := requestHost
+	if domain := m.Config().GetString("server.domain"); domain != "" && !hostRouter {
+		host = withPort(domain, (&url.URL{Host: requestHost}).Port())
+	}
+	return normalizeOrigin(scheme, host)
+}
+
+// isInHostRouter returns true if the given route belongs to a host router (see `goyave.Router.Host()`).
+// The host of such routes is the host of the request instead of "server.domain" or "server.proxy.host".
+func isInHostRouter(route *goyave.Route) bool {
+	if route == nil {
+		return false
+	}
+	for router := route.GetParent(); router != nil; router = router.GetParent() {
+		if router.GetHost() != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func withPort(host, port string) string {
+	if port == "" {
+		return host
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// normalizeOrigin returns the lowercased origin made of the given scheme and host.
+// The default port of the scheme is omitted.
+func normalizeOrigin(scheme, host string) string {
+	u := &url.URL{Host: host}
+	scheme = strings.ToLower(scheme)
+	port := u.Port()
+	if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+		port = ""
+	}
+	return scheme + "://" + withPort(strings.ToLower(u.Hostname()), port)
+}
+
+func (m *Middleware) submittedToken(request *goyave.Request) string {
+	if token := request.Header().Get(valueOr(m.HeaderName, "X-CSRF-Token")); token != "" {
+		return token
+	}
+	if data, ok := request.Data.(map[string]any); ok {
+		if token, ok := data[valueOr(m.FieldName, "_csrf")].(string); ok {
+			return token
+		}
+	}
+	return ""
+}
+
+// Token returns the CSRF token of the client set by the CSRF middleware,
+// or an empty string if the middleware was not executed.
+//
+// In view templates, the token is available with the `csrfToken` helper, and
+// `csrfField` renders a hidden "_csrf" form field containing it (see `RegisterViewHelpers()`).
+func Token(request *goyave.Request) string {
+	token, _ := request.Extra[ExtraToken{}].(string)
+	return token
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+func generateToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(errors.New(err))
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// valueOr returns the given value, or the fallback if the value is the zero value.
+func valueOr[T comparable](value, fallback T) T {
+	var zero T
+	if value == zero {
+		return fallback
+	}
+	return value
+}
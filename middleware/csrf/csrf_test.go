@@ -0,0 +1,197 @@
+package csrf
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/cors"
+	"goyave.dev/goyave/v5/middleware/parse"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+const (
+	tokenMismatch  = "{\"error\":\"Invalid or missing CSRF token.\"}\n"
+	originMismatch = "{\"error\":\"Cross-site request rejected.\"}\n"
+)
+
+func prepareCSRFTest(t *testing.T, middleware *Middleware) *goyave.Router {
+	cfg := config.LoadDefault()
+	cfg.Set("server.domain", "example.org")
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+	router := goyave.NewRouter(server.Server)
+	router.GlobalMiddleware(&parse.Middleware{})
+	router.Middleware(middleware)
+	handler := func(response *goyave.Response, request *goyave.Request) {
+		response.String(http.StatusOK, Token(request))
+	}
+	router.Get("/form", handler)
+	router.Post("/form", handler)
+	router.Post("/webhook", handler).SetMeta(MetaExempt, true)
+	api := router.Subrouter("/api")
+	api.CORS(cors.Default())
+	api.Post("/users", handler)
+	return router
+}
+
+func serveCSRFTest(t *testing.T, router *goyave.Router, req *http.Request) (*http.Response, string) {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	res := recorder.Result()
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, res.Body.Close())
+	require.NoError(t, err)
+	return res, string(body)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("generate_token", func(t *testing.T) {
+		router := prepareCSRFTest(t, &Middleware{})
+		res, body := serveCSRFTest(t, router, httptest.NewRequest(http.MethodGet, "/form", nil))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.Len(t, res.Cookies(), 1)
+		cookie := res.Cookies()[0]
+		assert.Equal(t, "csrf_token", cookie.Name)
+		assert.Equal(t, body, cookie.Value)
+		assert.Equal(t, "/", cookie.Path)
+		assert.Equal(t, "example.org", cookie.Domain)
+		assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+		assert.False(t, cookie.HttpOnly)
+		assert.Len(t, body, 43)
+
+		req := httptest.NewRequest(http.MethodGet, "/form", nil)
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "existing"})
+		res, body = serveCSRFTest(t, router, req)
+		assert.Empty(t, res.Cookies())
+		assert.Equal(t, "existing", body)
+	})
+
+	cases := []struct {
+		header         http.Header
+		desc           string
+		path           string
+		body           string
+		cookie         string
+		expectedBody   string
+		expectedStatus int
+	}{
+		{desc: "header_token", path: "/form", cookie: "token", header: http.Header{"X-Csrf-Token": {"token"}}, expectedStatus: http.StatusOK, expectedBody: "token"},
+		{desc: "form_token", path: "/form", cookie: "token", body: "_csrf=token", header: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}}, expectedStatus: http.StatusOK, expectedBody: "token"},
+		{desc: "wrong_token", path: "/form", cookie: "token", header: http.Header{"X-Csrf-Token": {"other"}}, expectedStatus: http.StatusForbidden, expectedBody: tokenMismatch},
+		{desc: "missing_token", path: "/form", cookie: "token", expectedStatus: http.StatusForbidden, expectedBody: tokenMismatch},
+		{desc: "missing_cookie", path: "/form", header: http.Header{"X-Csrf-Token": {"token"}}, expectedStatus: http.StatusForbidden, expectedBody: tokenMismatch},
+		{desc: "same_origin", path: "/form", cookie: "token", header: http.Header{"X-Csrf-Token": {"token"}, "Origin": {"http://example.org"}}, expectedStatus: http.StatusOK, expectedBody: "token"},
+		{desc: "same_origin_default_port", path: "/form", cookie: "token", header: http.Header{"X-Csrf-Token": {"token"}, "Origin": {"http://EXAMPLE.org:80"}}, expectedStatus: http.StatusOK, expectedBody: "token"},
+		{desc: "other_scheme", path: "/form", cookie: "token", header: http.Header{"X-Csrf-Token": {"token"}, "Origin": {"https://example.org"}}, expectedStatus: http.StatusForbidden, expectedBody: originMismatch},
+		{desc: "other_port", path: "/form", cookie: "token", header: http.Header{"X-Csrf-Token": {"token"}, "Origin": {"http://example.org:8081"}}, expectedStatus: http.StatusForbidden, expectedBody: originMismatch},
+		{desc: "trusted_origin", path: "/form", cookie: "token", header: http.Header{"X-Csrf-Token": {"token"}, "Origin": {"https://app.example.com"}}, expectedStatus: http.StatusOK, expectedBody: "token"},
+		{desc: "trusted_full_origin", path: "/form", cookie: "token", header: http.Header{"X-Csrf-Token": {"token"}, "Origin": {"https://admin.example.com"}}, expectedStatus: http.StatusOK, expectedBody: "token"},
+		{desc: "trusted_full_origin_other_port", path: "/form", cookie: "token", header: http.Header{"X-Csrf-Token": {"token"}, "Origin": {"https://admin.example.com:8443"}}, expectedStatus: http.StatusForbidden, expectedBody: originMismatch},
+		{desc: "cross_origin", path: "/form", cookie: "token", header: http.Header{"X-Csrf-Token": {"token"}, "Origin": {"https://evil.com"}}, expectedStatus: http.StatusForbidden, expectedBody: originMismatch},
+		{desc: "null_origin", path: "/form", cookie: "token", header: http.Header{"X-Csrf-Token": {"token"}, "Origin": {"null"}}, expectedStatus: http.StatusForbidden, expectedBody: originMismatch},
+		{desc: "cross_referer", path: "/form", cookie: "token", header: http.Header{"X-Csrf-Token": {"token"}, "Referer": {"https://evil.com/page"}}, expectedStatus: http.StatusForbidden, expectedBody: originMismatch},
+		{desc: "exempt", path: "/webhook", expectedStatus: http.StatusOK},
+		{desc: "preflighted", path: "/api/users", body: "{}", header: http.Header{"Content-Type": {"application/json"}}, expectedStatus: http.StatusOK},
+		{desc: "not_preflighted", path: "/api/users", body: "a=b", header: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}}, expectedStatus: http.StatusForbidden, expectedBody: tokenMismatch},
+		{desc: "preflighted_not_cors", path: "/form", body: "{}", header: http.Header{"Content-Type": {"application/json"}}, expectedStatus: http.StatusForbidden, expectedBody: tokenMismatch},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			router := prepareCSRFTest(t, &Middleware{TrustedOrigins: []string{"app.example.com", "https://admin.example.com"}, SkipPreflighted: true})
+			req := httptest.NewRequest(http.MethodPost, c.path, strings.NewReader(c.body))
+			for k, v := range c.header {
+				req.Header[k] = v
+			}
+			if c.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "csrf_token", Value: c.cookie})
+			}
+			res, body := serveCSRFTest(t, router, req)
+			assert.Equal(t, c.expectedStatus, res.StatusCode)
+			if c.expectedBody != "" {
+				assert.Equal(t, c.expectedBody, body)
+			}
+		})
+	}
+
+	t.Run("origin_proxy", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		cfg.Set("server.domain", "internal.example.org")
+		cfg.Set("server.proxy.host", "example.org")
+		cfg.Set("server.proxy.protocol", "https")
+		cfg.Set("server.proxy.port", 443)
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+		router := goyave.NewRouter(server.Server)
+		router.Middleware(&Middleware{})
+		handler := func(response *goyave.Response, _ *goyave.Request) {
+			response.Status(http.StatusNoContent)
+		}
+		router.Post("/form", handler)
+		router.Host("{tenant}.example.org").Post("/form", handler)
+
+		serve := func(url, origin string) int {
+			req := httptest.NewRequest(http.MethodPost, url, nil)
+			req.Header.Set("Origin", origin)
+			req.Header.Set("X-Csrf-Token", "token")
+			req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "token"})
+			res, _ := serveCSRFTest(t, router, req)
+			return res.StatusCode
+		}
+
+		assert.Equal(t, http.StatusNoContent, serve("http://example.org/form", "https://example.org"))
+		assert.Equal(t, http.StatusForbidden, serve("http://example.org/form", "http://example.org"))
+		assert.Equal(t, http.StatusForbidden, serve("http://example.org/form", "https://internal.example.org"))
+		assert.Equal(t, http.StatusNoContent, serve("http://acme.example.org/form", "https://acme.example.org"))
+		assert.Equal(t, http.StatusForbidden, serve("http://acme.example.org/form", "https://other.example.org"))
+	})
+
+	t.Run("synchronizer_token", func(t *testing.T) {
+		store := &testStore{token: "server-side"}
+		router := prepareCSRFTest(t, &Middleware{Store: store, HeaderName: "X-Token"})
+
+		req := httptest.NewRequest(http.MethodPost, "/form", nil)
+		req.Header.Set("X-Token", "server-side")
+		res, _ := serveCSRFTest(t, router, req)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Empty(t, res.Cookies())
+
+		store.token = ""
+		res, body := serveCSRFTest(t, router, httptest.NewRequest(http.MethodGet, "/form", nil))
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, store.token, body)
+	})
+}
+
+func TestViewHelpers(t *testing.T) {
+	RegisterViewHelpers()
+	cfg := config.LoadDefault()
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+	require.NoError(t, server.LoadViews(fstest.MapFS{
+		"form.html": {Data: []byte(`{{csrfToken}}|{{csrfField}}`)},
+	}))
+
+	request := server.NewTestRequest(http.MethodGet, "/", nil)
+	request.Extra[ExtraToken{}] = `a"b`
+	response, recorder := server.NewTestResponse(request)
+	response.View(http.StatusOK, "form", nil)
+	assert.Equal(t, `a&#34;b|<input type="hidden" name="_csrf" value="a&#34;b">`, recorder.Body.String())
+}
+
+type testStore struct {
+	token string
+}
+
+func (s *testStore) Get(_ *goyave.Request) string {
+	return s.token
+}
+
+func (s *testStore) Save(_ *goyave.Response, _ *goyave.Request, token string) {
+	s.token = token
+}
@@ -0,0 +1,98 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	errorutil "goyave.dev/goyave/v5/util/errors"
+)
+
+// Model the database model used by `GORMStore`. Use it with `AutoMigrate`
+// or create the "idempotency_keys" table using your migration tool.
+type Model struct {
+	ExpiresAt   time.Time `gorm:"index;not null"`
+	ID          string    `gorm:"primaryKey;size:64"`
+	Fingerprint string    `gorm:"size:64;not null"`
+	Header      []byte
+	Body        []byte
+	Status      int `gorm:"not null"`
+}
+
+// TableName returns "idempotency_keys".
+func (Model) TableName() string {
+	return "idempotency_keys"
+}
+
+// GORMStore a `Store` keeping the records in a database table using GORM. See `Model`.
+type GORMStore struct {
+	DB *gorm.DB
+}
+
+// NewGORMStore create a new `GORMStore` using the given database.
+func NewGORMStore(db *gorm.DB) *GORMStore {
+	return &GORMStore{DB: db}
+}
+
+// Lock reserves the given key if it is not already used. The reservation relies on
+// the primary key constraint so it is safe across multiple application instances.
+func (s *GORMStore) Lock(ctx context.Context, key string, fingerprint string, expiresAt time.Time) (*Record, error) {
+	db := s.DB.WithContext(ctx)
+	if err := db.Where("id = ? AND expires_at <= ?", key, time.Now()).Delete(&Model{}).Error; err != nil {
+		return nil, errorutil.New(err)
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&Model{ID: key, Fingerprint: fingerprint, ExpiresAt: expiresAt})
+	if result.Error != nil {
+		return nil, errorutil.New(result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return nil, nil
+	}
+
+	model := &Model{}
+	if err := db.Where("id = ?", key).Take(model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// The record was deleted in the meantime.
+			return s.Lock(ctx, key, fingerprint, expiresAt)
+		}
+		return nil, errorutil.New(err)
+	}
+	record := &Record{Fingerprint: model.Fingerprint, Status: model.Status, Body: model.Body}
+	if len(model.Header) > 0 {
+		if err := json.Unmarshal(model.Header, &record.Header); err != nil {
+			return nil, errorutil.New(err)
+		}
+	}
+	return record, nil
+}
+
+// Save the completed record of the given key.
+func (s *GORMStore) Save(ctx context.Context, key string, record *Record, expiresAt time.Time) error {
+	header, err := json.Marshal(record.Header)
+	if err != nil {
+		return errorutil.New(err)
+	}
+	model := &Model{
+		ID:          key,
+		Fingerprint: record.Fingerprint,
+		Header:      header,
+		Body:        record.Body,
+		Status:      record.Status,
+		ExpiresAt:   expiresAt,
+	}
+	return errorutil.New(s.DB.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(model).Error)
+}
+
+// Delete the record of the given key.
+func (s *GORMStore) Delete(ctx context.Context, key string) error {
+	return errorutil.New(s.DB.WithContext(ctx).Where("id = ?", key).Delete(&Model{}).Error)
+}
+
+// Sweep deletes all the records expired at the given time.
+func (s *GORMStore) Sweep(ctx context.Context, now time.Time) error {
+	return errorutil.New(s.DB.WithContext(ctx).Where("expires_at <= ?", now).Delete(&Model{}).Error)
+}
@@ -0,0 +1,39 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestGORMStore(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:idempotency_gorm_test?mode=memory"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		assert.NoError(t, sqlDB.Close())
+	})
+	require.NoError(t, db.AutoMigrate(&Model{}))
+
+	store := NewGORMStore(db)
+	assert.Equal(t, db, store.DB)
+	testStore(t, store)
+
+	var count int64
+	require.NoError(t, db.Model(&Model{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	t.Run("error", func(t *testing.T) {
+		store := NewGORMStore(db.Table("missing_table"))
+		_, err := store.Lock(context.Background(), "a", "f", time.Now().Add(time.Hour))
+		assert.Error(t, err)
+		assert.Error(t, store.Save(context.Background(), "a", &Record{}, time.Now()))
+	})
+}
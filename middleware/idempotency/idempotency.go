@@ -0,0 +1,325 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+func init() {
+	config.Register("idempotency.expiry", config.Entry{
+		Value:            86400,
+		Type:             reflect.Int,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+	config.Register("idempotency.sweepInterval", config.Entry{
+		Value:            600,
+		Type:             reflect.Int,
+		IsSlice:          false,
+		AuthorizedValues: []any{},
+	})
+}
+
+// HeaderReplayed the response header set to "true" when a stored response is replayed.
+const HeaderReplayed = "Idempotent-Replayed"
+
+// maxKeyLength the maximum length of an idempotency key.
+const maxKeyLength = 255
+
+// excludedHeaders the response headers that are not stored, because they depend on the
+// encoding negotiated with the client or because they are specific to the original request
+// (e.g. session or CSRF cookies).
+var excludedHeaders = []string{"Content-Encoding", "Content-Length", "Vary", "Set-Cookie"}
+
+// Middleware making unsafe requests idempotent, following the IETF "Idempotency-Key HTTP Header
+// Field" draft. Clients send a unique key in the "Idempotency-Key" header; the first response
+// obtained with a key is stored and replayed as-is for all the retries using the same key, without
+// executing the handler again.
+//
+// Keys are scoped by user (see `UserID`) and by route, and expire after "idempotency.expiry" seconds.
+// A retry is rejected with "409 Conflict" if the original request is still being processed, and
+// with "422 Unprocessable Entity" if its payload differs from the original request's payload.
+// Responses with a status code of 500 or above are not stored, so the request can be retried.
+// The "Content-Encoding", "Content-Length", "Vary" and "Set-Cookie" headers are not stored:
+// the body is stored uncompressed and the replayed response is encoded again if needed.
+//
+// The payload is identified by the request method, URI and body. If the body was parsed by the
+// parse middleware before this middleware, the parsed data is used instead of the raw body.
+//
+// When the server starts, a background sweeper deleting the expired records from the store
+// is started every "idempotency.sweepInterval" seconds (if not zero). It is stopped when
+// the server shuts down.
+type Middleware struct {
+	goyave.Component
+
+	// Store the record storage. Defaults to a new `MemoryStore`.
+	Store Store
+
+	// UserID returns the identifier of the user making the request so keys from
+	// different users don't collide. If `nil`, keys are only scoped by route.
+	UserID func(request *goyave.Request) string
+
+	// HeaderName the name of the request header containing the key.
+	// Defaults to "Idempotency-Key".
+	HeaderName string
+
+	// Methods the request methods for which the middleware is enabled.
+	// Defaults to "POST" and "PATCH".
+	Methods []string
+
+	// Required if `true`, requests without key are rejected with "400 Bad Request".
+	// Otherwise they are handled normally.
+	Required bool
+
+	sweeper sync.Once
+}
+
+// Init the middleware and register the sweeper lifecycle hooks.
+func (m *Middleware) Init(server *goyave.Server) {
+	m.Component.Init(server)
+	if m.Store == nil {
+		m.Store = NewMemoryStore()
+	}
+	m.sweeper.Do(func() {
+		m.registerSweeper(server)
+	})
+}
+
+// Handle replays the stored response if the request key was already used, or
+// executes the request and stores its response.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		if !m.isEnabled(request.Method()) {
+			next(response, request)
+			return
+		}
+
+		key, ok := m.key(request)
+		if !ok {
+			response.JSON(http.StatusBadRequest, map[string]string{"error": request.Lang.Get("idempotency.invalid-key")})
+			return
+		}
+		if key == "" {
+			if m.Required {
+				response.JSON(http.StatusBadRequest, map[string]string{"error": request.Lang.Get("idempotency.missing-key")})
+				return
+			}
+			next(response, request)
+			return
+		}
+
+		fingerprint, err := m.fingerprint(request)
+		if err != nil {
+			response.Error(err)
+			return
+		}
+
+		key = m.storeKey(request, key)
+		expiresAt := time.Now().Add(time.Duration(m.Config().GetInt("idempotency.expiry")) * time.Second)
+		record, err := m.Store.Lock(request.Context(), key, fingerprint, expiresAt)
+		if err != nil {
+			response.Error(err)
+			return
+		}
+		if record != nil {
+			m.replay(response, request, record, fingerprint)
+			return
+		}
+
+		saved := false
+		defer func() {
+			if !saved {
+				// Release the key if the request failed so it can be retried.
+				if err := m.Store.Delete(context.WithoutCancel(request.Context()), key); err != nil {
+					m.Logger().Error(err)
+				}
+			}
+		}()
+
+		writer := &writer{Writer: response.Writer()}
+		response.SetWriter(writer)
+		next(response, request)
+
+		if response.Hijacked() || response.GetError() != nil || response.GetStatus() >= http.StatusInternalServerError {
+			return
+		}
+		status := response.GetStatus()
+		if status == 0 {
+			status = http.StatusOK
+			if response.IsEmpty() {
+				status = http.StatusNoContent
+			}
+		}
+		record = &Record{
+			Fingerprint: fingerprint,
+			Status:      status,
+			Header:      recordHeader(response.Header()),
+			Body:        writer.body.Bytes(),
+		}
+		if err := m.Store.Save(context.WithoutCancel(request.Context()), key, record, expiresAt); err != nil {
+			m.Logger().Error(err)
+			return
+		}
+		saved = true
+	}
+}
+
+// recordHeader returns a copy of the given header without the `excludedHeaders`.
+func recordHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range excludedHeaders {
+		header.Del(name)
+	}
+	return header
+}
+
+func (m *Middleware) isEnabled(method string) bool {
+	if m.Methods == nil {
+		return method == http.MethodPost || method == http.MethodPatch
+	}
+	return slices.Contains(m.Methods, method)
+}
+
+// key returns the idempotency key of the request, unquoting it if it is sent as
+// a structured field string. Returns `false` if the key is invalid.
+func (m *Middleware) key(request *goyave.Request) (string, bool) {
+	headerName := m.HeaderName
+	if headerName == "" {
+		headerName = "Idempotency-Key"
+	}
+	key := strings.TrimSpace(request.Header().Get(headerName))
+	if len(key) >= 2 && key[0] == '"' && key[len(key)-1] == '"' {
+		key = key[1 : len(key)-1]
+		if key == "" {
+			return "", false
+		}
+	}
+	if len(key) > maxKeyLength {
+		return "", false
+	}
+	for _, c := range key {
+		if c < 0x20 || c > 0x7e || c == '"' {
+			return "", false
+		}
+	}
+	return key, true
+}
+
+func (m *Middleware) storeKey(request *goyave.Request, key string) string {
+	userID := ""
+	if m.UserID != nil {
+		userID = m.UserID(request)
+	}
+	route := request.URL().Path
+	if request.Route != nil {
+		route = request.Route.GetFullURI()
+	}
+	hash := sha256.Sum256([]byte(userID + "\x00" + request.Method() + " " + route + "\x00" + key))
+	return hex.EncodeToString(hash[:])
+}
+
+func (m *Middleware) fingerprint(request *goyave.Request) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(request.Method() + " " + request.URL().RequestURI() + "\n"))
+	if request.Data != nil {
+		if err := json.NewEncoder(hash).Encode(request.Data); err != nil {
+			return "", errors.New(err)
+		}
+	} else if body := request.Body(); body != nil && body != http.NoBody {
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			return "", errors.New(err)
+		}
+		// Restore the body so it can be read by the next handlers.
+		request.Request().Body = io.NopCloser(bytes.NewReader(raw))
+		hash.Write(raw)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (m *Middleware) replay(response *goyave.Response, request *goyave.Request, record *Record, fingerprint string) {
+	if record.Fingerprint != fingerprint {
+		response.JSON(http.StatusUnprocessableEntity, map[string]string{"error": request.Lang.Get("idempotency.mismatch")})
+		return
+	}
+	if record.InFlight() {
+		response.JSON(http.StatusConflict, map[string]string{"error": request.Lang.Get("idempotency.conflict")})
+		return
+	}
+	header := response.Header()
+	for k, v := range record.Header {
+		header[k] = slices.Clone(v)
+	}
+	header.Set(HeaderReplayed, "true")
+	response.Status(record.Status)
+	if len(record.Body) > 0 {
+		if _, err := response.Write(record.Body); err != nil {
+			panic(errors.New(err))
+		}
+	}
+}
+
+func (m *Middleware) registerSweeper(server *goyave.Server) {
+	interval := time.Duration(server.Config().GetInt("idempotency.sweepInterval")) * time.Second
+	if interval <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	server.RegisterStartupHook(func(s *goyave.Server) {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case now := <-ticker.C:
+					if err := m.Store.Sweep(ctx, now); err != nil && ctx.Err() == nil {
+						s.Logger.Error(errors.New(err))
+					}
+				}
+			}
+		}()
+	})
+	server.RegisterShutdownHook(func(_ *goyave.Server) {
+		cancel()
+	})
+}
+
+// writer captures the response body so it can be stored.
+type writer struct {
+	io.Writer
+	body bytes.Buffer
+}
+
+func (w *writer) PreWrite(b []byte) {
+	if pr, ok := w.Writer.(goyave.PreWriter); ok {
+		pr.PreWrite(b)
+	}
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.Writer.Write(b)
+}
+
+func (w *writer) Close() error {
+	if wr, ok := w.Writer.(io.Closer); ok {
+		return wr.Close()
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package idempotency
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/middleware/compress"
+	"goyave.dev/goyave/v5/middleware/parse"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+type testHandler struct {
+	block   chan struct{}
+	started chan struct{}
+	calls   atomic.Int32
+}
+
+func prepareIdempotencyTest(t *testing.T, middleware *Middleware, withParse bool) (*goyave.Router, *testHandler) {
+	cfg := config.LoadDefault()
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+	router := goyave.NewRouter(server.Server)
+	if withParse {
+		router.GlobalMiddleware(&parse.Middleware{})
+	}
+	router.Middleware(middleware)
+
+	handler := &testHandler{}
+	router.Post("/orders", func(response *goyave.Response, request *goyave.Request) {
+		n := handler.calls.Add(1)
+		if handler.started != nil {
+			close(handler.started)
+			<-handler.block
+		}
+		body, _ := io.ReadAll(request.Body())
+		response.Header().Set("X-Order", "order")
+		response.String(http.StatusCreated, "order "+string(rune('0'+n))+" "+string(body))
+	})
+	router.Post("/empty", func(_ *goyave.Response, _ *goyave.Request) {
+		handler.calls.Add(1)
+	})
+	router.Post("/error", func(response *goyave.Response, _ *goyave.Request) {
+		handler.calls.Add(1)
+		response.Status(http.StatusServiceUnavailable)
+	})
+	router.Post("/panic", func(_ *goyave.Response, _ *goyave.Request) {
+		handler.calls.Add(1)
+		panic("test panic")
+	})
+	router.Get("/orders", func(response *goyave.Response, _ *goyave.Request) {
+		handler.calls.Add(1)
+		response.String(http.StatusOK, "list")
+	})
+	return router, handler
+}
+
+func serveIdempotencyTest(t *testing.T, router *goyave.Router, method, path, key, body string) (*http.Response, string) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	res := recorder.Result()
+	resBody, err := io.ReadAll(res.Body)
+	assert.NoError(t, res.Body.Close())
+	require.NoError(t, err)
+	return res, string(resBody)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("default_store", func(t *testing.T) {
+		middleware := &Middleware{}
+		prepareIdempotencyTest(t, middleware, false)
+		assert.IsType(t, &MemoryStore{}, middleware.Store)
+	})
+
+	for _, withParse := range []bool{false, true} {
+		t.Run(fmt.Sprintf("replay_parse_%t", withParse), func(t *testing.T) {
+			store := NewMemoryStore()
+			router, handler := prepareIdempotencyTest(t, &Middleware{Store: store}, withParse)
+
+			res, body := serveIdempotencyTest(t, router, http.MethodPost, "/orders", "key-1", `{"product":1}`)
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+			assert.Empty(t, res.Header.Get(HeaderReplayed))
+			assert.Equal(t, "order", res.Header.Get("X-Order"))
+			firstBody := body
+
+			res, body = serveIdempotencyTest(t, router, http.MethodPost, "/orders", `"key-1"`, `{"product":1}`)
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+			assert.Equal(t, "true", res.Header.Get(HeaderReplayed))
+			assert.Equal(t, "order", res.Header.Get("X-Order"))
+			assert.Equal(t, firstBody, body)
+			assert.Equal(t, int32(1), handler.calls.Load())
+
+			res, body = serveIdempotencyTest(t, router, http.MethodPost, "/orders", "key-1", `{"product":2}`)
+			assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+			assert.Equal(t, "{\"error\":\"The idempotency key has already been used for a different request.\"}\n", body)
+
+			res, _ = serveIdempotencyTest(t, router, http.MethodPost, "/orders", "key-2", `{"product":1}`)
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+			assert.Empty(t, res.Header.Get(HeaderReplayed))
+			assert.Equal(t, int32(2), handler.calls.Load())
+			assert.Equal(t, 2, store.Len())
+		})
+	}
+
+	t.Run("excluded_headers", func(t *testing.T) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		router := goyave.NewRouter(server.Server)
+		router.GlobalMiddleware(&compress.Middleware{Encoders: []compress.Encoder{&compress.Gzip{Level: gzip.BestSpeed}}})
+		router.Middleware(&Middleware{})
+		router.Post("/orders", func(response *goyave.Response, _ *goyave.Request) {
+			response.Cookie(&http.Cookie{Name: "session", Value: "secret"})
+			response.String(http.StatusCreated, "order")
+		})
+
+		serve := func(header http.Header) (*http.Response, []byte) {
+			req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+			req.Header = header
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			res := recorder.Result()
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, res.Body.Close())
+			require.NoError(t, err)
+			return res, body
+		}
+
+		res, _ := serve(http.Header{"Idempotency-Key": {"key"}, "Accept-Encoding": {"gzip"}})
+		assert.Equal(t, "gzip", res.Header.Get("Content-Encoding"))
+		assert.NotEmpty(t, res.Header.Values("Set-Cookie"))
+
+		res, body := serve(http.Header{"Idempotency-Key": {"key"}})
+		assert.Equal(t, "true", res.Header.Get(HeaderReplayed))
+		assert.Empty(t, res.Header.Get("Content-Encoding"))
+		assert.Empty(t, res.Header.Values("Set-Cookie"))
+		assert.Equal(t, "order", string(body))
+
+		res, body = serve(http.Header{"Idempotency-Key": {"key"}, "Accept-Encoding": {"gzip"}})
+		assert.Equal(t, "true", res.Header.Get(HeaderReplayed))
+		assert.Equal(t, "gzip", res.Header.Get("Content-Encoding"))
+		reader, err := gzip.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		decoded, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "order", string(decoded))
+	})
+
+	t.Run("scoped_by_user", func(t *testing.T) {
+		router, handler := prepareIdempotencyTest(t, &Middleware{
+			UserID: func(request *goyave.Request) string { return request.Header().Get("X-User") },
+		}, false)
+
+		for _, user := range []string{"1", "2", "1"} {
+			req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+			req.Header.Set("Idempotency-Key", "key")
+			req.Header.Set("X-User", user)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}
+		assert.Equal(t, int32(2), handler.calls.Load())
+	})
+
+	t.Run("in_flight", func(t *testing.T) {
+		router, handler := prepareIdempotencyTest(t, &Middleware{}, false)
+		handler.started = make(chan struct{})
+		handler.block = make(chan struct{})
+
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, _ := serveIdempotencyTest(t, router, http.MethodPost, "/orders", "key", "")
+			assert.Equal(t, http.StatusCreated, res.StatusCode)
+		}()
+		<-handler.started
+
+		res, body := serveIdempotencyTest(t, router, http.MethodPost, "/orders", "key", "")
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+		assert.Equal(t, "{\"error\":\"A request with the same idempotency key is still being processed.\"}\n", body)
+
+		close(handler.block)
+		wg.Wait()
+		assert.Equal(t, int32(1), handler.calls.Load())
+	})
+
+	t.Run("empty_response", func(t *testing.T) {
+		router, handler := prepareIdempotencyTest(t, &Middleware{}, false)
+		res, _ := serveIdempotencyTest(t, router, http.MethodPost, "/empty", "key", "")
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		res, _ = serveIdempotencyTest(t, router, http.MethodPost, "/empty", "key", "")
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		assert.Equal(t, "true", res.Header.Get(HeaderReplayed))
+		assert.Equal(t, int32(1), handler.calls.Load())
+	})
+
+	t.Run("failures_not_stored", func(t *testing.T) {
+		store := NewMemoryStore()
+		router, handler := prepareIdempotencyTest(t, &Middleware{Store: store}, false)
+		for i := 0; i < 2; i++ {
+			res, _ := serveIdempotencyTest(t, router, http.MethodPost, "/error", "key", "")
+			assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+			res, _ = serveIdempotencyTest(t, router, http.MethodPost, "/panic", "key", "")
+			assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		}
+		assert.Equal(t, int32(4), handler.calls.Load())
+		assert.Equal(t, 0, store.Len())
+	})
+
+	t.Run("key_validation", func(t *testing.T) {
+		router, handler := prepareIdempotencyTest(t, &Middleware{}, false)
+
+		res, _ := serveIdempotencyTest(t, router, http.MethodPost, "/orders", "", "")
+		assert.Equal(t, http.StatusCreated, res.StatusCode, "key not required by default")
+		res, _ = serveIdempotencyTest(t, router, http.MethodPost, "/orders", "", "")
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, int32(2), handler.calls.Load())
+
+		for _, key := range []string{strings.Repeat("a", 256), `""`, "keyé"} {
+			res, body := serveIdempotencyTest(t, router, http.MethodPost, "/orders", key, "")
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode, key)
+			assert.Equal(t, "{\"error\":\"The Idempotency-Key header is invalid.\"}\n", body)
+		}
+
+		res, _ = serveIdempotencyTest(t, router, http.MethodGet, "/orders", "key", "")
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		res, _ = serveIdempotencyTest(t, router, http.MethodGet, "/orders", "key", "")
+		assert.Empty(t, res.Header.Get(HeaderReplayed), "disabled for safe methods")
+	})
+
+	t.Run("required", func(t *testing.T) {
+		router, handler := prepareIdempotencyTest(t, &Middleware{Required: true, HeaderName: "X-Request-Key", Methods: []string{http.MethodPost}}, false)
+		res, body := serveIdempotencyTest(t, router, http.MethodPost, "/orders", "key", "")
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, "{\"error\":\"The Idempotency-Key header is required.\"}\n", body)
+		assert.Equal(t, int32(0), handler.calls.Load())
+
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set("X-Request-Key", "key")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+	})
+
+	t.Run("store_error", func(t *testing.T) {
+		router, handler := prepareIdempotencyTest(t, &Middleware{Store: &errorStore{}}, false)
+		res, _ := serveIdempotencyTest(t, router, http.MethodPost, "/orders", "key", "")
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		assert.Equal(t, int32(0), handler.calls.Load())
+	})
+
+	t.Run("sweeper", func(t *testing.T) {
+		store := NewMemoryStore()
+		require.NoError(t, store.Save(context.Background(), "expired", &Record{Status: http.StatusOK}, time.Now().Add(-time.Second)))
+		cfg := config.LoadDefault()
+		cfg.Set("server.port", 0)
+		cfg.Set("idempotency.sweepInterval", 1)
+		server, err := goyave.New(goyave.Options{Config: cfg})
+		require.NoError(t, err)
+		server.Router().GlobalMiddleware(&Middleware{Store: store})
+
+		wg := sync.WaitGroup{}
+		wg.Add(2)
+		server.RegisterStartupHook(func(s *goyave.Server) {
+			assert.Eventually(t, func() bool { return store.Len() == 0 }, 5*time.Second, 100*time.Millisecond)
+			s.Stop()
+			wg.Done()
+		})
+		go func() {
+			assert.NoError(t, server.Start())
+			wg.Done()
+		}()
+		wg.Wait()
+	})
+}
+
+type errorStore struct {
+	MemoryStore
+}
+
+func (s *errorStore) Lock(_ context.Context, _ string, _ string, _ time.Time) (*Record, error) {
+	return nil, io.ErrUnexpectedEOF
+}
@@ -0,0 +1,115 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Record the response stored for an idempotency key.
+type Record struct {
+	// Header the response headers.
+	Header http.Header `json:"header,omitempty"`
+
+	// Fingerprint identifies the request payload. A retry using the same key
+	// with a different fingerprint is rejected.
+	Fingerprint string `json:"fingerprint"`
+
+	// Body the response body.
+	Body []byte `json:"body,omitempty"`
+
+	// Status the response status code. Zero while the original request
+	// is still being processed.
+	Status int `json:"status"`
+}
+
+// InFlight returns true if the original request is still being processed.
+func (r *Record) InFlight() bool {
+	return r.Status == 0
+}
+
+// Store persists the responses associated with idempotency keys.
+type Store interface {
+	// Lock atomically reserves the given key until the given expiry time with an in-flight
+	// record if the key is not already used. Returns `nil` if the key was reserved, or the
+	// record currently associated with the key otherwise. Expired records are ignored.
+	Lock(ctx context.Context, key string, fingerprint string, expiresAt time.Time) (*Record, error)
+
+	// Save the completed record of the given key. The record expires at the given time.
+	Save(ctx context.Context, key string, record *Record, expiresAt time.Time) error
+
+	// Delete the record of the given key, allowing the request to be retried.
+	Delete(ctx context.Context, key string) error
+
+	// Sweep deletes all the records expired at the given time.
+	Sweep(ctx context.Context, now time.Time) error
+}
+
+type memoryEntry struct {
+	expiresAt time.Time
+	record    Record
+}
+
+// MemoryStore a `Store` keeping the records in memory. The records are lost
+// when the application stops and are not shared between multiple instances, so
+// this store is mostly suitable for development, tests and single-instance deployments.
+type MemoryStore struct {
+	records map[string]memoryEntry
+	mu      sync.Mutex
+}
+
+// NewMemoryStore create a new empty `MemoryStore`.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: map[string]memoryEntry{},
+	}
+}
+
+// Lock reserves the given key if it is not already used.
+func (s *MemoryStore) Lock(_ context.Context, key string, fingerprint string, expiresAt time.Time) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.records[key]; ok && time.Now().Before(entry.expiresAt) {
+		record := entry.record
+		return &record, nil
+	}
+	s.records[key] = memoryEntry{record: Record{Fingerprint: fingerprint}, expiresAt: expiresAt}
+	return nil, nil
+}
+
+// Save the completed record of the given key.
+func (s *MemoryStore) Save(_ context.Context, key string, record *Record, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = memoryEntry{record: *record, expiresAt: expiresAt}
+	return nil
+}
+
+// Delete the record of the given key.
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// Sweep deletes all the records expired at the given time.
+func (s *MemoryStore) Sweep(_ context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, entry := range s.records {
+		if !now.Before(entry.expiresAt) {
+			delete(s.records, key)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of records in the store, including expired ones
+// that were not swept yet.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records)
+}
@@ -0,0 +1,61 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	now := time.Now()
+
+	record, err := store.Lock(ctx, "a", "fingerprint-a", now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, record, "key reserved")
+
+	record, err = store.Lock(ctx, "a", "other", now.Add(time.Hour))
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.True(t, record.InFlight())
+	assert.Equal(t, "fingerprint-a", record.Fingerprint)
+
+	saved := &Record{
+		Fingerprint: "fingerprint-a",
+		Status:      http.StatusCreated,
+		Header:      http.Header{"Content-Type": {"application/json"}},
+		Body:        []byte(`{"id":1}`),
+	}
+	require.NoError(t, store.Save(ctx, "a", saved, now.Add(time.Hour)))
+	record, err = store.Lock(ctx, "a", "fingerprint-a", now.Add(time.Hour))
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.False(t, record.InFlight())
+	assert.Equal(t, saved, record)
+
+	require.NoError(t, store.Delete(ctx, "a"))
+	record, err = store.Lock(ctx, "a", "fingerprint-a", now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, record, "key can be reserved again after deletion")
+
+	require.NoError(t, store.Save(ctx, "expired", &Record{Fingerprint: "old", Status: http.StatusOK}, now.Add(-time.Second)))
+	record, err = store.Lock(ctx, "expired", "new", now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, record, "expired records are ignored")
+
+	require.NoError(t, store.Save(ctx, "b", &Record{Fingerprint: "b", Status: http.StatusOK}, now.Add(-time.Second)))
+	require.NoError(t, store.Sweep(ctx, now))
+	record, err = store.Lock(ctx, "a", "fingerprint-a", now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.NotNil(t, record, "unexpired records are not swept")
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	testStore(t, store)
+	assert.Equal(t, 2, store.Len())
+}
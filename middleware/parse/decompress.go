@@ -0,0 +1,134 @@
+package parse
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strings"
+
+	"github.com/samber/lo"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// DefaultDecoders the decoders used by the parse middleware if its `Decoders` field is `nil`.
+var DefaultDecoders = []Decoder{
+	&GzipDecoder{},
+	&DeflateDecoder{},
+}
+
+// Decoder is the counterpart of `compress.Encoder`, used by the parse middleware
+// to decompress request bodies.
+//
+// `Encoding` returns the name of the compression algorithm, matched against
+// the request's `Content-Encoding` header.
+//
+// `NewReader` returns a reader decompressing the given compressed body.
+type Decoder interface {
+	NewReader(io.Reader) (io.ReadCloser, error)
+	Encoding() string
+}
+
+// GzipDecoder decoder for the gzip format using Go's standard `compress/gzip` package.
+type GzipDecoder struct{}
+
+// Encoding returns "gzip".
+func (d *GzipDecoder) Encoding() string {
+	return "gzip"
+}
+
+// NewReader returns a `compress/gzip.Reader`.
+func (d *GzipDecoder) NewReader(r io.Reader) (io.ReadCloser, error) {
+	reader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return reader, nil
+}
+
+// DeflateDecoder decoder for the "deflate" format (zlib data format, RFC 1950)
+// using Go's standard `compress/zlib` package.
+type DeflateDecoder struct{}
+
+// Encoding returns "deflate".
+func (d *DeflateDecoder) Encoding() string {
+	return "deflate"
+}
+
+// NewReader returns a `compress/zlib` reader.
+func (d *DeflateDecoder) NewReader(r io.Reader) (io.ReadCloser, error) {
+	reader, err := zlib.NewReader(r)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return reader, nil
+}
+
+// decodedBody a request body wrapped in the readers of all the encodings
+// listed in the request's "Content-Encoding" header.
+type decodedBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (b *decodedBody) Close() error {
+	var errs []error
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		if err := b.closers[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(errs)
+	}
+	return nil
+}
+
+// getDecoders returns the decoders matching the given "Content-Encoding" header value,
+// in the order in which they must be applied. Returns `false` if one of the encodings
+// is not supported.
+func (m *Middleware) getDecoders(contentEncoding string) ([]Decoder, bool) {
+	available := m.Decoders
+	if available == nil {
+		available = DefaultDecoders
+	}
+
+	encodings := strings.Split(contentEncoding, ",")
+	decoders := make([]Decoder, 0, len(encodings))
+	// Encodings are listed in the order in which they were applied.
+	for i := len(encodings) - 1; i >= 0; i-- {
+		encoding := strings.ToLower(strings.TrimSpace(encodings[i]))
+		if encoding == "" || encoding == "identity" {
+			continue
+		}
+		decoder, ok := lo.Find(available, func(d Decoder) bool {
+			return d.Encoding() == encoding
+		})
+		if !ok {
+			return nil, false
+		}
+		decoders = append(decoders, decoder)
+	}
+	return decoders, true
+}
+
+func (m *Middleware) acceptedEncodings() string {
+	available := m.Decoders
+	if available == nil {
+		available = DefaultDecoders
+	}
+	return strings.Join(lo.Map(available, func(d Decoder, _ int) string { return d.Encoding() }), ", ")
+}
+
+func decodeBody(body io.Reader, decoders []Decoder) (*decodedBody, error) {
+	decoded := &decodedBody{Reader: body, closers: make([]io.Closer, 0, len(decoders))}
+	for _, d := range decoders {
+		reader, err := d.NewReader(decoded.Reader)
+		if err != nil {
+			_ = decoded.Close()
+			return nil, errors.New(err)
+		}
+		decoded.Reader = reader
+		decoded.closers = append(decoded.closers, reader)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,197 @@
+package parse
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func deflateBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	w := zlib.NewWriter(buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+type testDecoder struct{}
+
+func (d *testDecoder) Encoding() string {
+	return "reverse"
+}
+
+func (d *testDecoder) NewReader(r io.Reader) (io.ReadCloser, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func TestDecompress(t *testing.T) {
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+
+	jsonBody := []byte(`{"a":"b","c":["d","e"]}`)
+	expectedJSON := map[string]any{"a": "b", "c": []any{"d", "e"}}
+
+	cases := []struct {
+		middleware      *Middleware
+		expectedData    any
+		desc            string
+		contentEncoding string
+		contentType     string
+		acceptEncoding  string
+		body            []byte
+		expectedStatus  int
+	}{
+		{
+			desc:            "gzip",
+			middleware:      &Middleware{},
+			body:            gzipBytes(t, jsonBody),
+			contentType:     "application/json",
+			contentEncoding: "gzip",
+			expectedStatus:  http.StatusOK,
+			expectedData:    expectedJSON,
+		},
+		{
+			desc:            "deflate",
+			middleware:      &Middleware{},
+			body:            deflateBytes(t, jsonBody),
+			contentType:     "application/json",
+			contentEncoding: "Deflate",
+			expectedStatus:  http.StatusOK,
+			expectedData:    expectedJSON,
+		},
+		{
+			desc:            "identity",
+			middleware:      &Middleware{},
+			body:            jsonBody,
+			contentType:     "application/json",
+			contentEncoding: "identity",
+			expectedStatus:  http.StatusOK,
+			expectedData:    expectedJSON,
+		},
+		{
+			desc:            "multiple_encodings",
+			middleware:      &Middleware{},
+			body:            gzipBytes(t, deflateBytes(t, jsonBody)),
+			contentType:     "application/json",
+			contentEncoding: "deflate, gzip",
+			expectedStatus:  http.StatusOK,
+			expectedData:    expectedJSON,
+		},
+		{
+			desc:            "form",
+			middleware:      &Middleware{},
+			body:            gzipBytes(t, []byte(url.Values{"a": {"b"}}.Encode())),
+			contentType:     "application/x-www-form-urlencoded",
+			contentEncoding: "gzip",
+			expectedStatus:  http.StatusOK,
+			expectedData:    map[string]any{"a": "b"},
+		},
+		{
+			desc:            "custom_decoder",
+			middleware:      &Middleware{Decoders: []Decoder{&testDecoder{}}},
+			body:            []byte(`}"b":"a"{`),
+			contentType:     "application/json",
+			contentEncoding: "reverse",
+			expectedStatus:  http.StatusOK,
+			expectedData:    map[string]any{"a": "b"},
+		},
+		{
+			desc:            "unsupported_encoding",
+			middleware:      &Middleware{},
+			body:            jsonBody,
+			contentType:     "application/json",
+			contentEncoding: "br",
+			expectedStatus:  http.StatusUnsupportedMediaType,
+			acceptEncoding:  "gzip, deflate",
+		},
+		{
+			desc:            "unsupported_one_of_many",
+			middleware:      &Middleware{},
+			body:            gzipBytes(t, jsonBody),
+			contentType:     "application/json",
+			contentEncoding: "br, gzip",
+			expectedStatus:  http.StatusUnsupportedMediaType,
+			acceptEncoding:  "gzip, deflate",
+		},
+		{
+			desc:            "no_decoders",
+			middleware:      &Middleware{Decoders: []Decoder{}},
+			body:            gzipBytes(t, jsonBody),
+			contentType:     "application/json",
+			contentEncoding: "gzip",
+			expectedStatus:  http.StatusUnsupportedMediaType,
+			acceptEncoding:  "",
+		},
+		{
+			desc:            "invalid_header",
+			middleware:      &Middleware{},
+			body:            jsonBody,
+			contentType:     "application/json",
+			contentEncoding: "gzip",
+			expectedStatus:  http.StatusBadRequest,
+		},
+		{
+			desc:            "corrupted_body",
+			middleware:      &Middleware{},
+			body:            gzipBytes(t, jsonBody)[:20],
+			contentType:     "application/json",
+			contentEncoding: "gzip",
+			expectedStatus:  http.StatusBadRequest,
+		},
+		{
+			desc:            "decompressed_too_large",
+			middleware:      &Middleware{MaxUploadSize: 0.01},
+			body:            gzipBytes(t, []byte(strings.Repeat("a", 1024*1024))),
+			contentType:     "application/json",
+			contentEncoding: "gzip",
+			expectedStatus:  http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			request := testutil.NewTestRequest(http.MethodPost, "/parse", bytes.NewReader(c.body))
+			request.Header().Set("Content-Type", c.contentType)
+			request.Header().Set("Content-Encoding", c.contentEncoding)
+
+			result := server.TestMiddleware(c.middleware, request, func(response *goyave.Response, req *goyave.Request) {
+				assert.Equal(t, c.expectedData, req.Data)
+				assert.Empty(t, req.Header().Get("Content-Encoding"))
+				response.Status(http.StatusOK)
+			})
+			assert.NoError(t, result.Body.Close())
+			assert.Equal(t, c.expectedStatus, result.StatusCode)
+			assert.Equal(t, c.acceptEncoding, result.Header.Get("Accept-Encoding"))
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package parse
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil"
+)
+
+const (
+	// DefaultMaxDepth the default maximum nesting depth of a key using the
+	// bracket or dot notation.
+	DefaultMaxDepth = 10
+
+	// DefaultMaxIndex the default maximum array index of a key using the bracket notation.
+	DefaultMaxIndex = 1000
+)
+
+type segmentKind int
+
+const (
+	segmentKey segmentKind = iota
+	segmentIndex
+	segmentAppend
+)
+
+type segment struct {
+	key   string
+	index int
+	kind  segmentKind
+}
+
+// nestedArray an array being built. Indices can be sparse, the array
+// is compacted when the nesting is complete.
+type nestedArray struct {
+	items map[int]any
+	next  int
+}
+
+type keyNester struct {
+	maxDepth int
+	maxIndex int
+}
+
+func (m *Middleware) keyNester() *keyNester {
+	n := &keyNester{maxDepth: m.MaxDepth, maxIndex: m.MaxIndex}
+	if n.maxDepth <= 0 {
+		n.maxDepth = DefaultMaxDepth
+	}
+	if n.maxIndex <= 0 {
+		n.maxIndex = DefaultMaxIndex
+	}
+	return n
+}
+
+// nest converts the keys of the given flat map using the bracket or dot notation
+// into nested objects and arrays:
+//   - `user[name]` and `user.name` are the "name" field of the "user" object
+//   - `user[tags][]` appends to the "tags" array of the "user" object
+//   - `items[0][name]` is the "name" field of the first element of the "items" array
+//
+// Values are expected to be a `string`, a `[]string` or a `[]fsutil.File`.
+// File values are never split into multiple array elements: `files[]` is equivalent to `files`.
+// Returns an error if a key exceeds the maximum depth or index, or if two keys conflict.
+func (n *keyNester) nest(flat map[string]any) (map[string]any, error) {
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	root := make(map[string]any, len(flat))
+	for _, key := range keys {
+		segments, err := n.parseKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if err := n.insert(root, key, segments, flat[key]); err != nil {
+			return nil, err
+		}
+	}
+	compact(root)
+	return root, nil
+}
+
+func (n *keyNester) insert(root map[string]any, key string, segments []segment, value any) error {
+	if files, ok := value.([]fsutil.File); ok {
+		if len(segments) > 1 && segments[len(segments)-1].kind == segmentAppend {
+			segments = segments[:len(segments)-1]
+		}
+		return n.assign(root, key, segments, files)
+	}
+
+	hasAppend := false
+	for _, s := range segments {
+		if s.kind == segmentAppend {
+			hasAppend = true
+			break
+		}
+	}
+	if !hasAppend {
+		return n.assign(root, key, segments, value)
+	}
+
+	// Each value is a new array element.
+	var values []string
+	switch v := value.(type) {
+	case string:
+		values = []string{v}
+	case []string:
+		values = v
+	}
+	for _, v := range values {
+		if err := n.assign(root, key, segments, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *keyNester) assign(parent any, key string, segments []segment, value any) error {
+	s := segments[0]
+	last := len(segments) == 1
+
+	var child any
+	var exists bool
+	var set func(any)
+	switch p := parent.(type) {
+	case map[string]any:
+		child, exists = p[s.key]
+		set = func(v any) { p[s.key] = v }
+	case *nestedArray:
+		index := s.index
+		if s.kind == segmentAppend {
+			index = p.next
+		}
+		if index > n.maxIndex {
+			return errors.New(fmt.Errorf("parse: key %q exceeds the maximum array index (%d)", key, n.maxIndex))
+		}
+		child, exists = p.items[index]
+		set = func(v any) {
+			p.items[index] = v
+			if index >= p.next {
+				p.next = index + 1
+			}
+		}
+	}
+
+	if last {
+		if exists {
+			return errors.New(fmt.Errorf("parse: key %q conflicts with another key", key))
+		}
+		set(value)
+		return nil
+	}
+
+	next := segments[1]
+	if !exists {
+		if next.kind == segmentKey {
+			child = map[string]any{}
+		} else {
+			child = &nestedArray{items: map[int]any{}}
+		}
+		set(child)
+	} else {
+		_, isMap := child.(map[string]any)
+		_, isArray := child.(*nestedArray)
+		if (next.kind == segmentKey && !isMap) || (next.kind != segmentKey && !isArray) {
+			return errors.New(fmt.Errorf("parse: key %q conflicts with another key", key))
+		}
+	}
+	return n.assign(child, key, segments[1:], value)
+}
+
+// parseKey splits the given key into segments. Keys that don't use
+// the bracket or dot notation, or that are malformed, result in a single segment.
+func (n *keyNester) parseKey(key string) ([]segment, error) {
+	i := strings.IndexAny(key, "[.")
+	if i <= 0 {
+		return []segment{{key: key}}, nil
+	}
+
+	segments := []segment{{key: key[:i]}}
+	rest := key[i:]
+	for rest != "" {
+		switch rest[0] {
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return []segment{{key: key}}, nil
+			}
+			content := rest[1:end]
+			rest = rest[end+1:]
+			switch {
+			case content == "":
+				segments = append(segments, segment{kind: segmentAppend})
+			case isIndex(content):
+				index, err := strconv.Atoi(content)
+				if err != nil || index > n.maxIndex {
+					return nil, errors.New(fmt.Errorf("parse: key %q exceeds the maximum array index (%d)", key, n.maxIndex))
+				}
+				segments = append(segments, segment{kind: segmentIndex, index: index})
+			default:
+				segments = append(segments, segment{key: content})
+			}
+		case '.':
+			end := strings.IndexAny(rest[1:], "[.")
+			content := rest[1:]
+			if end != -1 {
+				content = rest[1 : end+1]
+			}
+			if content == "" {
+				return []segment{{key: key}}, nil
+			}
+			segments = append(segments, segment{key: content})
+			rest = rest[len(content)+1:]
+		default:
+			// Characters after a closing bracket
+			return []segment{{key: key}}, nil
+		}
+
+		if len(segments)-1 > n.maxDepth {
+			return nil, errors.New(fmt.Errorf("parse: key %q exceeds the maximum depth (%d)", key, n.maxDepth))
+		}
+	}
+	return segments, nil
+}
+
+func isIndex(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// compact replaces all the `*nestedArray` in the given map with `[]any`,
+// keeping the order of the indices.
+func compact(m map[string]any) {
+	for k, v := range m {
+		m[k] = compactValue(v)
+	}
+}
+
+func compactValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		compact(val)
+		return val
+	case *nestedArray:
+		indices := make([]int, 0, len(val.items))
+		for i := range val.items {
+			indices = append(indices, i)
+		}
+		sort.Ints(indices)
+		array := make([]any, 0, len(indices))
+		for _, i := range indices {
+			array = append(array, compactValue(val.items[i]))
+		}
+		return array
+	}
+	return v
+}
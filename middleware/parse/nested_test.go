@@ -0,0 +1,268 @@
+package parse
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/fsutil"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func TestNest(t *testing.T) {
+	files := []fsutil.File{{MIMEType: "image/png"}}
+
+	cases := []struct {
+		flat    map[string]any
+		want    map[string]any
+		desc    string
+		wantErr bool
+	}{
+		{
+			desc: "flat",
+			flat: map[string]any{"a": "b", "c": []string{"d", "e"}},
+			want: map[string]any{"a": "b", "c": []string{"d", "e"}},
+		},
+		{
+			desc: "brackets",
+			flat: map[string]any{"user[name]": "a", "user[address][city]": "b"},
+			want: map[string]any{"user": map[string]any{"name": "a", "address": map[string]any{"city": "b"}}},
+		},
+		{
+			desc: "dots",
+			flat: map[string]any{"user.name": "a", "user.address.city": "b"},
+			want: map[string]any{"user": map[string]any{"name": "a", "address": map[string]any{"city": "b"}}},
+		},
+		{
+			desc: "mixed",
+			flat: map[string]any{"user.tags[]": []string{"x", "y"}, "user[address].city": "b"},
+			want: map[string]any{"user": map[string]any{"tags": []any{"x", "y"}, "address": map[string]any{"city": "b"}}},
+		},
+		{
+			desc: "append_single",
+			flat: map[string]any{"tags[]": "x"},
+			want: map[string]any{"tags": []any{"x"}},
+		},
+		{
+			desc: "indices",
+			flat: map[string]any{"items[1][name]": "b", "items[0][name]": "a", "items[0][id]": "1"},
+			want: map[string]any{"items": []any{map[string]any{"name": "a", "id": "1"}, map[string]any{"name": "b"}}},
+		},
+		{
+			desc: "sparse_indices",
+			flat: map[string]any{"items[10]": "b", "items[3]": "a"},
+			want: map[string]any{"items": []any{"a", "b"}},
+		},
+		{
+			desc: "nested_arrays",
+			flat: map[string]any{"matrix[0][]": []string{"1", "2"}, "matrix[1][]": "3"},
+			want: map[string]any{"matrix": []any{[]any{"1", "2"}, []any{"3"}}},
+		},
+		{
+			desc: "append_objects",
+			flat: map[string]any{"items[][name]": []string{"a", "b"}},
+			want: map[string]any{"items": []any{map[string]any{"name": "a"}, map[string]any{"name": "b"}}},
+		},
+		{
+			desc: "multiple_values",
+			flat: map[string]any{"user[roles]": []string{"a", "b"}},
+			want: map[string]any{"user": map[string]any{"roles": []string{"a", "b"}}},
+		},
+		{
+			desc: "files",
+			flat: map[string]any{"user[avatar]": files, "attachments[]": files},
+			want: map[string]any{"user": map[string]any{"avatar": files}, "attachments": files},
+		},
+		{
+			desc: "malformed",
+			flat: map[string]any{"a[b": "1", "[c]": "2", ".d": "3", "e.": "4", "f[g]h": "5", "i..j": "6"},
+			want: map[string]any{"a[b": "1", "[c]": "2", ".d": "3", "e.": "4", "f[g]h": "5", "i..j": "6"},
+		},
+		{
+			desc: "bracket_content_with_dot",
+			flat: map[string]any{"a[b.c]": "1"},
+			want: map[string]any{"a": map[string]any{"b.c": "1"}},
+		},
+		{
+			desc:    "conflict_value_object",
+			flat:    map[string]any{"a": "1", "a[b]": "2"},
+			wantErr: true,
+		},
+		{
+			desc:    "conflict_object_array",
+			flat:    map[string]any{"a[0]": "1", "a.b": "2"},
+			wantErr: true,
+		},
+		{
+			desc:    "conflict_same_index",
+			flat:    map[string]any{"a[0]": "1", "a[00]": "2"},
+			wantErr: true,
+		},
+		{
+			desc:    "max_depth",
+			flat:    map[string]any{"a[b][c][d]": "1"},
+			wantErr: true,
+		},
+		{
+			desc:    "max_index",
+			flat:    map[string]any{"a[11]": "1"},
+			wantErr: true,
+		},
+		{
+			desc:    "max_index_overflow",
+			flat:    map[string]any{"a[99999999999999999999999]": "1"},
+			wantErr: true,
+		},
+		{
+			desc:    "max_index_append",
+			flat:    map[string]any{"a[]": strings.Split("0,1,2,3,4,5,6,7,8,9,10,11", ",")},
+			wantErr: true,
+		},
+	}
+
+	nester := &keyNester{maxDepth: 2, maxIndex: 10}
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			result, err := nester.nest(c.flat)
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.want, result)
+		})
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		n := (&Middleware{}).keyNester()
+		assert.Equal(t, DefaultMaxDepth, n.maxDepth)
+		assert.Equal(t, DefaultMaxIndex, n.maxIndex)
+
+		n = (&Middleware{MaxDepth: 3, MaxIndex: 4}).keyNester()
+		assert.Equal(t, 3, n.maxDepth)
+		assert.Equal(t, 4, n.maxIndex)
+	})
+}
+
+func TestNestedKeysMiddleware(t *testing.T) {
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+
+	t.Run("query", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodGet, "/parse?user[name]=a&user[tags][]=x&user[tags][]=y&filter.status=active", nil)
+		result := server.TestMiddleware(&Middleware{NestedKeys: true}, request, func(response *goyave.Response, req *goyave.Request) {
+			expected := map[string]any{
+				"user":   map[string]any{"name": "a", "tags": []any{"x", "y"}},
+				"filter": map[string]any{"status": "active"},
+			}
+			assert.Equal(t, expected, req.Query)
+			response.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+	})
+
+	t.Run("query_disabled", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodGet, "/parse?user[name]=a", nil)
+		result := server.TestMiddleware(&Middleware{}, request, func(response *goyave.Response, req *goyave.Request) {
+			assert.Equal(t, map[string]any{"user[name]": "a"}, req.Query)
+			response.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+	})
+
+	t.Run("query_error", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodGet, "/parse?a[2000]=b", nil)
+		result := server.TestMiddleware(&Middleware{NestedKeys: true}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+	})
+
+	t.Run("form", func(t *testing.T) {
+		form := url.Values{
+			"items[0][name]": {"a"},
+			"items[1][name]": {"b"},
+			"user.email":     {"johndoe@example.org"},
+		}
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader(form.Encode()))
+		request.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		result := server.TestMiddleware(&Middleware{NestedKeys: true}, request, func(response *goyave.Response, req *goyave.Request) {
+			expected := map[string]any{
+				"items": []any{map[string]any{"name": "a"}, map[string]any{"name": "b"}},
+				"user":  map[string]any{"email": "johndoe@example.org"},
+			}
+			assert.Equal(t, expected, req.Data)
+			response.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+	})
+
+	t.Run("form_error", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader("a=1&a[b]=2"))
+		request.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		result := server.TestMiddleware(&Middleware{NestedKeys: true}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+	})
+
+	t.Run("json_not_nested", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader(`{"a[b]":"c"}`))
+		request.Header().Set("Content-Type", "application/json")
+		result := server.TestMiddleware(&Middleware{NestedKeys: true}, request, func(response *goyave.Response, req *goyave.Request) {
+			assert.Equal(t, map[string]any{"a[b]": "c"}, req.Data)
+			response.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+	})
+
+	for _, stream := range []bool{false, true} {
+		stream := stream
+		name := "multipart"
+		if stream {
+			name = "multipart_stream"
+		}
+		t.Run(name, func(t *testing.T) {
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			require.NoError(t, testutil.WriteMultipartFile(writer, &osfs.FS{}, testLogo, "user[avatar]", "goyave_16.png"))
+			require.NoError(t, testutil.WriteMultipartFile(writer, &osfs.FS{}, testLogo, "attachments[]", "a.png"))
+			require.NoError(t, writer.WriteField("user[name]", "John"))
+			require.NoError(t, writer.Close())
+
+			request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+			request.Header().Set("Content-Type", writer.FormDataContentType())
+			middleware := &Middleware{NestedKeys: true, StreamMultipart: stream, UploadFS: osfs.New(t.TempDir())}
+			result := server.TestMiddleware(middleware, request, func(response *goyave.Response, req *goyave.Request) {
+				data := req.Data.(map[string]any)
+				user, ok := data["user"].(map[string]any)
+				require.True(t, ok)
+				assert.Equal(t, "John", user["name"])
+				avatar, ok := user["avatar"].([]fsutil.File)
+				require.True(t, ok)
+				require.Len(t, avatar, 1)
+				assert.Equal(t, "image/png", avatar[0].MIMEType)
+				attachments, ok := data["attachments"].([]fsutil.File)
+				require.True(t, ok)
+				assert.Len(t, attachments, 1)
+				response.Status(http.StatusOK)
+			})
+			assert.NoError(t, result.Body.Close())
+			assert.Equal(t, http.StatusOK, result.StatusCode)
+		})
+	}
+}
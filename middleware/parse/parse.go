@@ -2,11 +2,11 @@ package parse
 
 import (
 	"bytes"
-	"encoding/json"
+	"errors"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
-	"strings"
 
 	"goyave.dev/goyave/v5"
 	"goyave.dev/goyave/v5/util/fsutil"
@@ -18,25 +18,74 @@ import (
 // (single value arrays converted to non-array), the result is put in the request's `Query`.
 // If the parsing fails, returns "400 Bad request".
 //
-// The body is read only if the "Content-Type" header is set. If
-// the body exceeds the configured max upload size (in MiB), "413 Request Entity Too Large"
-// is returned.
-// If the content type is "application/json", the middleware will attempt
-// to unmarshal the body and put the result in the request's `Data`. If it fails, returns "400 Bad request".
-// If the content-type has another value, Go's standard `ParseMultipartForm` is called. The result
-// is put inside the request's `Data` after being flattened.
-// If the form is not a multipart form, attempts `ParseForm`. If `ParseMultipartForm` or `ParseForm` return
-// an error, returns "400 Bad request".
+// The body is read only if the "Content-Type" header is set. If no `BodyParser` matches
+// the media type, "415 Unsupported Media Type" is returned (see `RegisterParser()`).
+// If the request has a "Content-Encoding" header, the body is transparently decompressed
+// using the matching `Decoders` before being parsed. If one of the encodings is not supported,
+// "415 Unsupported Media Type" is returned along with an "Accept-Encoding" header listing
+// the supported encodings. If the body cannot be decompressed, returns "400 Bad request".
+// If the body (decompressed) exceeds the configured max upload size (in MiB),
+// "413 Request Entity Too Large" is returned.
+// The body is then parsed and the result is put in the request's `Data`. If parsing fails,
+// returns "400 Bad request".
+// If the content type is "application/json", the body is unmarshaled using `JSONParser`.
+// If the content type is "application/x-www-form-urlencoded" or "multipart/form-data",
+// the body is parsed using Go's standard `ParseMultipartForm` or `ParseForm` (see `FormParser`).
+// The result is put inside the request's `Data` after being flattened.
 //
 // In `multipart/form-data`, all file parts are automatically converted to `[]fsutil.File`.
 // Inside `request.Data`, a field of type "file" will therefore always be of type `[]fsutil.File`.
 // It is a slice so it support multi-file uploads in a single field.
+//
+// If `StreamMultipart` is enabled, `multipart/form-data` bodies are not buffered in memory
+// but parsed while being read: file parts are directly written to `UploadFS` and only form
+// values are kept in memory. The size and SHA-256 checksum of the files are computed on the fly
+// (see `fsutil.File`). The written files are removed once the request has been handled:
+// save them (`fsutil.File.Save()`) in the handler if you want to keep them.
 type Middleware struct {
 	goyave.Component
 
 	// MaxUpoadSize the maximum size of the request (in MiB).
 	// Defaults to the value provided in the config "server.maxUploadSize".
 	MaxUploadSize float64
+
+	// Decoders the decoders used to decompress request bodies having
+	// a "Content-Encoding" header. Defaults to `DefaultDecoders` (gzip and deflate).
+	// Set to an empty slice to reject all compressed request bodies.
+	Decoders []Decoder
+
+	// UploadFS the file system uploaded files are written to in streaming mode.
+	// Defaults to the OS temporary directory.
+	UploadFS UploadFS
+
+	// UploadDir the directory inside `UploadFS` uploaded files are written to in streaming mode.
+	// Created if it doesn't exist and `UploadFS` implements `fsutil.MkdirFS`.
+	UploadDir string
+
+	// StreamMultipart if true, `multipart/form-data` bodies are parsed while being read
+	// and file parts are written to `UploadFS` instead of being kept in memory.
+	StreamMultipart bool
+
+	// Parsers the parsers used by this middleware only, identified by media type. They take
+	// precedence over the parsers registered with `RegisterParser()`.
+	Parsers map[string]BodyParser
+
+	// NestedKeys if true, the bracket and dot notations are supported in the keys of
+	// the query and form bodies (parsed by `FormParser` or in streaming mode), producing
+	// nested objects and arrays. For example, `user[name]=a&user[tags][]=b&user.age=3&items[0][id]=4`
+	// results in `{"user": {"name": "a", "tags": ["b"], "age": "3"}, "items": [{"id": "4"}]}`.
+	// Array indices only determine the order of the elements: the resulting arrays are compact.
+	// If a key exceeds `MaxDepth` or `MaxIndex`, or if two keys conflict (e.g. `a=1&a[b]=2`),
+	// returns "400 Bad request".
+	NestedKeys bool
+
+	// MaxDepth the maximum nesting depth of a key if `NestedKeys` is enabled.
+	// Defaults to `DefaultMaxDepth`.
+	MaxDepth int
+
+	// MaxIndex the maximum array index of a key if `NestedKeys` is enabled.
+	// Defaults to `DefaultMaxIndex`.
+	MaxIndex int
 }
 
 // Handle reads the request query and body and parses it if necessary.
@@ -44,7 +93,7 @@ type Middleware struct {
 // middleware immediately passes after parsing the query.
 func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
 	return func(response *goyave.Response, r *goyave.Request) {
-		if err := parseQuery(r); err != nil {
+		if err := m.parseQuery(r); err != nil {
 			response.Status(http.StatusBadRequest)
 			return
 		}
@@ -57,10 +106,59 @@ func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
 		r.Data = nil
 		contentType := r.Header().Get("Content-Type")
 		if contentType != "" {
+			var parser BodyParser
+			if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+				parser = m.getParser(mediaType)
+			}
+			if parser == nil {
+				response.Status(http.StatusUnsupportedMediaType)
+				return
+			}
+
+			var body io.Reader = r.Body()
+			if contentEncoding := r.Header().Get("Content-Encoding"); contentEncoding != "" {
+				decoders, ok := m.getDecoders(contentEncoding)
+				if !ok {
+					response.Header().Set("Accept-Encoding", m.acceptedEncodings())
+					response.Status(http.StatusUnsupportedMediaType)
+					return
+				}
+				decoded, err := decodeBody(body, decoders)
+				if err != nil {
+					response.Status(http.StatusBadRequest)
+					return
+				}
+				defer func() {
+					_ = decoded.Close()
+				}()
+				body = decoded
+				r.Header().Del("Content-Encoding")
+				r.Header().Del("Content-Length")
+				r.Request().ContentLength = -1
+			}
+
 			maxSize := int64(m.getMaxUploadSize() * 1024 * 1024)
+			if boundary, ok := m.multipartBoundary(contentType); ok {
+				data, files, err := m.parseMultipartStream(response, body, boundary, maxSize)
+				if err != nil {
+					var maxBytesErr *http.MaxBytesError
+					if errors.As(err, &maxBytesErr) {
+						response.Status(http.StatusRequestEntityTooLarge)
+					} else {
+						response.Status(http.StatusBadRequest)
+					}
+					return
+				}
+				defer m.removeFiles(files)
+				r.Data = data
+				next(response, r)
+				return
+			}
+
 			maxValueBytes := maxSize
 			var bodyBuf bytes.Buffer
-			n, err := io.CopyN(&bodyBuf, r.Body(), maxValueBytes+1)
+			// The limit applies to the decompressed body to prevent decompression bombs.
+			n, err := io.CopyN(&bodyBuf, body, maxValueBytes+1)
 			if err == nil || err == io.EOF {
 				maxValueBytes -= n
 				if maxValueBytes < 0 {
@@ -68,21 +166,15 @@ func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
 					return
 				}
 
-				bodyBytes := bodyBuf.Bytes()
-				if strings.HasPrefix(contentType, "application/json") {
-					var body any
-					if err := json.Unmarshal(bodyBytes, &body); err != nil {
-						response.Status(http.StatusBadRequest)
-					}
-					r.Data = body
-				} else {
-					req := r.Request()
-					req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-					r.Data, err = generateFlatMap(req, maxSize)
-					if err != nil {
-						response.Status(http.StatusBadRequest)
+				r.Data, err = parser.Parse(r, bodyBuf.Bytes())
+				if _, isForm := parser.(*FormParser); err == nil && isForm && m.NestedKeys {
+					if flatMap, ok := r.Data.(map[string]any); ok {
+						r.Data, err = m.keyNester().nest(flatMap)
 					}
 				}
+				if err != nil {
+					response.Status(http.StatusBadRequest)
+				}
 			} else {
 				response.Status(http.StatusBadRequest)
 			}
@@ -102,11 +194,18 @@ func (m *Middleware) getMaxUploadSize() float64 {
 	return m.MaxUploadSize
 }
 
-func parseQuery(request *goyave.Request) error {
+func (m *Middleware) parseQuery(request *goyave.Request) error {
 	queryParams, err := url.ParseQuery(request.URL().RawQuery)
 	if err == nil {
 		request.Query = make(map[string]any, len(queryParams))
 		flatten(request.Query, queryParams)
+		if m.NestedKeys {
+			var nested map[string]any
+			nested, err = m.keyNester().nest(request.Query)
+			if err == nil {
+				request.Query = nested
+			}
+		}
 	}
 	return err
 }
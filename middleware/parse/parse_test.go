@@ -60,7 +60,7 @@ func TestParseMiddleware(t *testing.T) {
 
 	t.Run("Entity Too Large", func(t *testing.T) {
 		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader(strings.Repeat("a", 1024*1024)))
-		request.Header().Set("Content-Type", "application/octet-stream")
+		request.Header().Set("Content-Type", "application/json")
 
 		result := server.TestMiddleware(&Middleware{MaxUploadSize: 0.01}, request, func(_ *goyave.Response, _ *goyave.Request) {
 			assert.Fail(t, "Middleware should not pass")
@@ -0,0 +1,113 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// BodyParser parses request bodies of a given media type.
+// The returned value is put in the request's `Data`. If an error is returned,
+// the parse middleware responds with "400 Bad request".
+type BodyParser interface {
+	Parse(request *goyave.Request, body []byte) (any, error)
+}
+
+// BodyParserFunc an adapter allowing the use of an ordinary function as a `BodyParser`.
+type BodyParserFunc func(request *goyave.Request, body []byte) (any, error)
+
+// Parse calls f(request, body).
+func (f BodyParserFunc) Parse(request *goyave.Request, body []byte) (any, error) {
+	return f(request, body)
+}
+
+var (
+	parsers = map[string]BodyParser{
+		"application/json":                  &JSONParser{},
+		"application/x-www-form-urlencoded": &FormParser{},
+		"multipart/form-data":               &FormParser{},
+	}
+	parsersMu sync.RWMutex
+)
+
+// RegisterParser registers a `BodyParser` for all parse middleware. The media type
+// (e.g. "application/xml") is case-insensitive and must not contain parameters.
+// A media type ending with "/*" (e.g. "text/*") matches all the subtypes that
+// don't have a parser of their own. If a parser is already registered for the
+// given media type, it is replaced.
+//
+// By default, parsers are registered for "application/json", "application/x-www-form-urlencoded"
+// and "multipart/form-data".
+func RegisterParser(mediaType string, parser BodyParser) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	parsers[strings.ToLower(mediaType)] = parser
+}
+
+// getParser returns the parser for the given media type, or `nil` if there is none.
+// The middleware's `Parsers` take precedence over the registered ones.
+func (m *Middleware) getParser(mediaType string) BodyParser {
+	candidates := []string{mediaType}
+	if i := strings.Index(mediaType, "/"); i != -1 {
+		candidates = append(candidates, mediaType[:i]+"/*")
+	}
+
+	for _, c := range candidates {
+		if parser, ok := m.Parsers[c]; ok {
+			return parser
+		}
+		parsersMu.RLock()
+		parser, ok := parsers[c]
+		parsersMu.RUnlock()
+		if ok {
+			return parser
+		}
+	}
+	return nil
+}
+
+// JSONParser a `BodyParser` for JSON bodies.
+type JSONParser struct {
+	// UseNumber if true, numbers are decoded as `json.Number` instead of `float64`,
+	// avoiding precision loss for large integers (such as IDs). The `Int` and `Float`
+	// validation rules convert `json.Number` values.
+	UseNumber bool
+}
+
+// Parse the JSON body.
+func (p *JSONParser) Parse(_ *goyave.Request, body []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if p.UseNumber {
+		decoder.UseNumber()
+	}
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, errors.New(err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("invalid JSON: unexpected data after top-level value")
+	}
+	return data, nil
+}
+
+// FormParser a `BodyParser` for "application/x-www-form-urlencoded" and
+// "multipart/form-data" bodies. The result is a flattened `map[string]any`.
+// File parts are converted to `[]fsutil.File`.
+type FormParser struct{}
+
+// Parse the form body.
+func (p *FormParser) Parse(request *goyave.Request, body []byte) (any, error) {
+	req := request.Request()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	// The body is already in memory, so are its parts.
+	data, err := generateFlatMap(req, int64(len(body)))
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return data, nil
+}
@@ -0,0 +1,169 @@
+package parse
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func TestJSONParser(t *testing.T) {
+	cases := []struct {
+		want      any
+		body      string
+		useNumber bool
+		wantErr   bool
+	}{
+		{body: `{"id":12345678901234567890}`, want: map[string]any{"id": 1.2345678901234567e+19}},
+		{body: `{"id":12345678901234567890}`, useNumber: true, want: map[string]any{"id": json.Number("12345678901234567890")}},
+		{body: `[1, 2.5]`, useNumber: true, want: []any{json.Number("1"), json.Number("2.5")}},
+		{body: `"string"`, want: "string"},
+		{body: `null`, want: nil},
+		{body: ` {"a":"b"} `, want: map[string]any{"a": "b"}},
+		{body: `{"a":"b"} {"c":"d"}`, wantErr: true},
+		{body: `{"a":"b"}}`, wantErr: true},
+		{body: `{"unclosed"`, wantErr: true},
+		{body: ``, wantErr: true},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(fmt.Sprintf("%s_%t", c.body, c.useNumber), func(t *testing.T) {
+			parser := &JSONParser{UseNumber: c.useNumber}
+			data, err := parser.Parse(nil, []byte(c.body))
+			if c.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.want, data)
+		})
+	}
+}
+
+func TestBodyParsers(t *testing.T) {
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+
+	xmlParser := BodyParserFunc(func(_ *goyave.Request, body []byte) (any, error) {
+		var data struct {
+			Name string `xml:"name"`
+		}
+		if err := xml.Unmarshal(body, &data); err != nil {
+			return nil, err
+		}
+		return map[string]any{"name": data.Name}, nil
+	})
+	textParser := BodyParserFunc(func(_ *goyave.Request, body []byte) (any, error) {
+		return string(body), nil
+	})
+
+	RegisterParser("Application/XML", xmlParser)
+	RegisterParser("text/*", textParser)
+	t.Cleanup(func() {
+		parsersMu.Lock()
+		delete(parsers, "application/xml")
+		delete(parsers, "text/*")
+		parsersMu.Unlock()
+	})
+
+	cases := []struct {
+		middleware  *Middleware
+		want        any
+		desc        string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{
+			desc:        "registered",
+			middleware:  &Middleware{},
+			contentType: "application/xml; charset=utf-8",
+			body:        "<user><name>John</name></user>",
+			wantStatus:  http.StatusOK,
+			want:        map[string]any{"name": "John"},
+		},
+		{
+			desc:        "registered_error",
+			middleware:  &Middleware{},
+			contentType: "application/xml",
+			body:        "<user><name>John",
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			desc:        "wildcard",
+			middleware:  &Middleware{},
+			contentType: "text/csv",
+			body:        "a,b,c",
+			wantStatus:  http.StatusOK,
+			want:        "a,b,c",
+		},
+		{
+			desc: "middleware_parsers",
+			middleware: &Middleware{
+				Parsers: map[string]BodyParser{
+					"application/merge-patch+json": &JSONParser{UseNumber: true},
+				},
+			},
+			contentType: "application/merge-patch+json",
+			body:        `{"id":12345678901234567890,"name":null}`,
+			wantStatus:  http.StatusOK,
+			want:        map[string]any{"id": json.Number("12345678901234567890"), "name": nil},
+		},
+		{
+			desc: "middleware_parsers_override",
+			middleware: &Middleware{
+				Parsers: map[string]BodyParser{
+					"application/json": &JSONParser{UseNumber: true},
+				},
+			},
+			contentType: "application/json",
+			body:        `{"id":1}`,
+			wantStatus:  http.StatusOK,
+			want:        map[string]any{"id": json.Number("1")},
+		},
+		{
+			desc:        "unsupported",
+			middleware:  &Middleware{},
+			contentType: "application/msgpack",
+			body:        "abc",
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "merge_patch_not_registered",
+			middleware:  &Middleware{},
+			contentType: "application/merge-patch+json",
+			body:        `{"a":"b"}`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "invalid_content_type",
+			middleware:  &Middleware{},
+			contentType: "application/json; =invalid",
+			body:        `{"a":"b"}`,
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.desc, func(t *testing.T) {
+			request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader(c.body))
+			request.Header().Set("Content-Type", c.contentType)
+
+			result := server.TestMiddleware(c.middleware, request, func(response *goyave.Response, req *goyave.Request) {
+				assert.Equal(t, c.want, req.Data)
+				response.Status(http.StatusOK)
+			})
+			assert.NoError(t, result.Body.Close())
+			assert.Equal(t, c.wantStatus, result.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package parse
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+
+	"github.com/google/uuid"
+	"goyave.dev/goyave/v5/util/fsutil"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+
+	errorutil "goyave.dev/goyave/v5/util/errors"
+)
+
+// UploadFS a file system the parse middleware can write uploaded files to
+// in streaming mode, read them from and remove them once the request is handled.
+type UploadFS interface {
+	fs.FS
+	fsutil.WritableFS
+	fsutil.RemoveFS
+}
+
+// multipartBoundary returns the boundary of the given content type if streaming
+// is enabled and the content type is "multipart/form-data".
+func (m *Middleware) multipartBoundary(contentType string) (string, bool) {
+	if !m.StreamMultipart {
+		return "", false
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		return "", false
+	}
+	return params["boundary"], true
+}
+
+func (m *Middleware) getUploadFS() UploadFS {
+	if m.UploadFS == nil {
+		return osfs.New(os.TempDir())
+	}
+	return m.UploadFS
+}
+
+// parseMultipartStream reads the multipart body part by part. Form values are kept in
+// memory and file parts are written to the upload file system. The returned error is
+// a `*http.MaxBytesError` if the body exceeds the given max size. If an error is returned,
+// the files that were already written are removed.
+func (m *Middleware) parseMultipartStream(response http.ResponseWriter, body io.Reader, boundary string, maxSize int64) (map[string]any, []fsutil.File, error) {
+	uploadFS := m.getUploadFS()
+	if mkdirFS, ok := uploadFS.(fsutil.MkdirFS); ok && m.UploadDir != "" {
+		if err := mkdirFS.MkdirAll(m.UploadDir, os.ModePerm); err != nil {
+			return nil, nil, errorutil.New(err)
+		}
+	}
+
+	reader := multipart.NewReader(http.MaxBytesReader(response, io.NopCloser(body), maxSize), boundary)
+	values := url.Values{}
+	files := map[string][]fsutil.File{}
+	allFiles := []fsutil.File{}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			m.removeFiles(allFiles)
+			return nil, nil, errorutil.New(err)
+		}
+
+		name := part.FormName()
+		if name == "" {
+			_, err = io.Copy(io.Discard, part)
+		} else if part.FileName() == "" {
+			var value []byte
+			value, err = io.ReadAll(part)
+			values.Add(name, string(value))
+		} else {
+			var file fsutil.File
+			file, err = m.writeFilePart(uploadFS, part)
+			if err == nil {
+				files[name] = append(files[name], file)
+				allFiles = append(allFiles, file)
+			}
+		}
+		_ = part.Close()
+		if err != nil {
+			m.removeFiles(allFiles)
+			return nil, nil, errorutil.New(err)
+		}
+	}
+
+	data := make(map[string]any, len(values)+len(files))
+	flatten(data, values)
+	for field, f := range files {
+		data[field] = f
+	}
+	if m.NestedKeys {
+		nested, err := m.keyNester().nest(data)
+		if err != nil {
+			m.removeFiles(allFiles)
+			return nil, nil, errorutil.New(err)
+		}
+		data = nested
+	}
+	return data, allFiles, nil
+}
+
+// writeFilePart writes the content of the given part to a new file in the upload
+// file system. The MIME type, the size and the checksum of the content are computed
+// while it is being written.
+func (m *Middleware) writeFilePart(uploadFS UploadFS, part *multipart.Part) (file fsutil.File, err error) {
+	filePath := path.Join(m.UploadDir, "goyave-upload-"+uuid.NewString())
+	var writer io.ReadWriteCloser
+	writer, err = uploadFS.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return file, errorutil.New(err)
+	}
+	defer func() {
+		closeErr := writer.Close()
+		if err == nil && closeErr != nil {
+			err = errorutil.New(closeErr)
+		}
+		if err != nil {
+			_ = uploadFS.Remove(filePath)
+		}
+	}()
+
+	hash := sha256.New()
+	w := io.MultiWriter(writer, hash)
+
+	// Same MIME detection as `fsutil.ParseMultipartFiles()`: the first 512 bytes
+	// of the content, padded with zeros.
+	fileHeader := make([]byte, 512)
+	n, err := io.ReadFull(part, fileHeader)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return file, errorutil.New(err)
+	}
+	if _, err = w.Write(fileHeader[:n]); err != nil {
+		return file, errorutil.New(err)
+	}
+	size := int64(n)
+	if n == len(fileHeader) {
+		var copied int64
+		copied, err = io.Copy(w, part)
+		if err != nil {
+			return file, errorutil.New(err)
+		}
+		size += copied
+	}
+
+	file = fsutil.File{
+		Header: &multipart.FileHeader{
+			Filename: part.FileName(),
+			Header:   part.Header,
+			Size:     size,
+		},
+		FS:       uploadFS,
+		MIMEType: http.DetectContentType(fileHeader),
+		Path:     filePath,
+		Checksum: hex.EncodeToString(hash.Sum(nil)),
+	}
+	return file, nil
+}
+
+func (m *Middleware) removeFiles(files []fsutil.File) {
+	for _, f := range files {
+		removeFS, ok := f.FS.(fsutil.RemoveFS)
+		if !ok {
+			continue
+		}
+		if err := removeFS.Remove(f.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			m.Logger().Error(errorutil.New(err))
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package parse
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/fsutil"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+	"goyave.dev/goyave/v5/util/testutil"
+	"goyave.dev/goyave/v5/validation"
+)
+
+const testLogo = "../../resources/img/logo/goyave_16.png"
+
+func createStreamTestBody(t *testing.T) (*bytes.Buffer, string) {
+	t.Helper()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	require.NoError(t, testutil.WriteMultipartFile(writer, &osfs.FS{}, testLogo, "profile_picture", "goyave_16.png"))
+	require.NoError(t, testutil.WriteMultipartFile(writer, &osfs.FS{}, testLogo, "attachments", "a.png"))
+	require.NoError(t, testutil.WriteMultipartFile(writer, &osfs.FS{}, "../../resources/custom_config.json", "attachments", "b.json"))
+	require.NoError(t, writer.WriteField("email", "johndoe@example.org"))
+	require.NoError(t, writer.WriteField("tags", "a"))
+	require.NoError(t, writer.WriteField("tags", "b"))
+	require.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func checksum(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestStreamMultipart(t *testing.T) {
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+
+	t.Run("stream", func(t *testing.T) {
+		dir := t.TempDir()
+		body, contentType := createStreamTestBody(t)
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", contentType)
+
+		middleware := &Middleware{
+			StreamMultipart: true,
+			UploadFS:        osfs.New(dir),
+			UploadDir:       "uploads",
+		}
+
+		var paths []string
+		result := server.TestMiddleware(middleware, request, func(response *goyave.Response, req *goyave.Request) {
+			data, ok := req.Data.(map[string]any)
+			require.True(t, ok)
+			assert.Equal(t, "johndoe@example.org", data["email"])
+			assert.Equal(t, []string{"a", "b"}, data["tags"])
+
+			picture, ok := data["profile_picture"].([]fsutil.File)
+			require.True(t, ok)
+			require.Len(t, picture, 1)
+			assert.Equal(t, "goyave_16.png", picture[0].Header.Filename)
+			assert.Equal(t, int64(630), picture[0].Header.Size)
+			assert.Equal(t, "image/png", picture[0].MIMEType)
+			assert.Equal(t, checksum(t, testLogo), picture[0].Checksum)
+			assert.True(t, strings.HasPrefix(picture[0].Path, "uploads/"))
+
+			attachments, ok := data["attachments"].([]fsutil.File)
+			require.True(t, ok)
+			require.Len(t, attachments, 2)
+			assert.Equal(t, "a.png", attachments[0].Header.Filename)
+			assert.Equal(t, "b.json", attachments[1].Header.Filename)
+			assert.Equal(t, "application/octet-stream", attachments[1].MIMEType) // Same detection as non-streamed files
+			assert.Equal(t, checksum(t, "../../resources/custom_config.json"), attachments[1].Checksum)
+
+			f, err := attachments[1].Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(f)
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+			assert.Equal(t, "{\n    \"custom-entry\": \"value\"\n}", string(content))
+
+			for _, file := range append(picture, attachments...) {
+				assert.FileExists(t, dir+"/"+file.Path)
+				paths = append(paths, dir+"/"+file.Path)
+			}
+
+			// Compatible with file validators
+			ctx := &validation.Context{Value: picture}
+			assert.True(t, validation.File().Validate(ctx))
+			assert.True(t, validation.MIME("image/png").Validate(ctx))
+			assert.True(t, validation.Extension("png").Validate(ctx))
+			assert.True(t, validation.Max(1).Validate(ctx))
+			response.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+
+		// Files are removed once the request is handled
+		require.Len(t, paths, 3)
+		for _, p := range paths {
+			assert.NoFileExists(t, p)
+		}
+	})
+
+	t.Run("default_upload_fs", func(t *testing.T) {
+		body, contentType := createStreamTestBody(t)
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", contentType)
+
+		var path string
+		result := server.TestMiddleware(&Middleware{StreamMultipart: true}, request, func(response *goyave.Response, req *goyave.Request) {
+			files := req.Data.(map[string]any)["profile_picture"].([]fsutil.File)
+			path = os.TempDir() + "/" + files[0].Path
+			assert.FileExists(t, path)
+			response.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("compressed", func(t *testing.T) {
+		dir := t.TempDir()
+		body, contentType := createStreamTestBody(t)
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", bytes.NewReader(gzipBytes(t, body.Bytes())))
+		request.Header().Set("Content-Type", contentType)
+		request.Header().Set("Content-Encoding", "gzip")
+
+		result := server.TestMiddleware(&Middleware{StreamMultipart: true, UploadFS: osfs.New(dir)}, request, func(response *goyave.Response, req *goyave.Request) {
+			files := req.Data.(map[string]any)["profile_picture"].([]fsutil.File)
+			assert.Equal(t, checksum(t, testLogo), files[0].Checksum)
+			response.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+	})
+
+	t.Run("too_large", func(t *testing.T) {
+		dir := t.TempDir()
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		require.NoError(t, testutil.WriteMultipartFile(writer, &osfs.FS{}, testLogo, "small", "goyave_16.png"))
+		part, err := writer.CreateFormFile("large", "large.txt")
+		require.NoError(t, err)
+		_, err = part.Write([]byte(strings.Repeat("a", 1024*1024)))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", body)
+		request.Header().Set("Content-Type", writer.FormDataContentType())
+
+		result := server.TestMiddleware(&Middleware{StreamMultipart: true, UploadFS: osfs.New(dir), MaxUploadSize: 0.01}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusRequestEntityTooLarge, result.StatusCode)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		body, contentType := createStreamTestBody(t)
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", bytes.NewReader(body.Bytes()[:body.Len()-50]))
+		request.Header().Set("Content-Type", contentType)
+
+		result := server.TestMiddleware(&Middleware{StreamMultipart: true, UploadFS: osfs.New(dir)}, request, func(_ *goyave.Response, _ *goyave.Request) {
+			assert.Fail(t, "Middleware should not pass")
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, result.StatusCode)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("not_multipart", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodPost, "/parse", strings.NewReader("a=b"))
+		request.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+
+		result := server.TestMiddleware(&Middleware{StreamMultipart: true}, request, func(response *goyave.Response, req *goyave.Request) {
+			assert.Equal(t, map[string]any{"a": "b"}, req.Data)
+			response.Status(http.StatusOK)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+	})
+}
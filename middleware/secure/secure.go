@@ -0,0 +1,270 @@
+package secure
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/middleware/csrf"
+	"goyave.dev/goyave/v5/middleware/parse"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+func init() {
+	register := func(key string, value any, kind reflect.Kind) {
+		config.Register("security.headers."+key, config.Entry{
+			Value:            value,
+			Type:             kind,
+			IsSlice:          false,
+			AuthorizedValues: []any{},
+		})
+	}
+	register("hsts.maxAge", 31536000, reflect.Int)
+	register("hsts.includeSubdomains", true, reflect.Bool)
+	register("hsts.preload", false, reflect.Bool)
+	register("contentSecurityPolicy", "default-src 'self'", reflect.String)
+	register("cspReportOnly", false, reflect.Bool)
+	register("cspReportURI", "", reflect.String)
+	register("contentTypeOptions", "nosniff", reflect.String)
+	register("frameOptions", "DENY", reflect.String)
+	register("referrerPolicy", "strict-origin-when-cross-origin", reflect.String)
+	register("permissionsPolicy", "", reflect.String)
+}
+
+// MetaHeaders the security headers middleware uses the `*Headers` stored in this route
+// meta (or in any of the route's parent routers) instead of the headers defined in the config.
+const MetaHeaders = "goyave.security-headers"
+
+// NoncePlaceholder occurrences of this placeholder in the Content Security Policy are
+// replaced with a nonce generated for each request (e.g. "script-src 'nonce-{nonce}'").
+const NoncePlaceholder = "{nonce}"
+
+// ExtraNonce the key used in `Context.Extra` to store the CSP nonce (`string`)
+// generated for the request. Use `Nonce()` to retrieve it.
+type ExtraNonce struct{}
+
+// Headers the security headers added to responses. Empty values (or zero for `HSTSMaxAge`)
+// disable the corresponding header.
+type Headers struct {
+	// ContentSecurityPolicy the value of the `Content-Security-Policy` header.
+	// Can contain `NoncePlaceholder`.
+	ContentSecurityPolicy string
+
+	// CSPReportURI if not empty, added to the CSP with the "report-uri" directive.
+	CSPReportURI string
+
+	// ContentTypeOptions the value of the `X-Content-Type-Options` header.
+	ContentTypeOptions string
+
+	// FrameOptions the value of the `X-Frame-Options` header.
+	FrameOptions string
+
+	// ReferrerPolicy the value of the `Referrer-Policy` header.
+	ReferrerPolicy string
+
+	// PermissionsPolicy the value of the `Permissions-Policy` header.
+	PermissionsPolicy string
+
+	// HSTSMaxAge the max age (in seconds) of the `Strict-Transport-Security` header.
+	HSTSMaxAge int
+
+	// HSTSIncludeSubdomains adds the "includeSubDomains" directive to the HSTS header.
+	HSTSIncludeSubdomains bool
+
+	// HSTSPreload adds the "preload" directive to the HSTS header.
+	HSTSPreload bool
+
+	// CSPReportOnly if true, the CSP is sent in the `Content-Security-Policy-Report-Only`
+	// header so violations are reported but not enforced.
+	CSPReportOnly bool
+}
+
+// HeadersFromConfig returns the headers defined in the "security.headers" config entries.
+// The result can be used as a base for per-route overrides.
+func HeadersFromConfig(cfg *config.Config) *Headers {
+	return &Headers{
+		ContentSecurityPolicy: cfg.GetString("security.headers.contentSecurityPolicy"),
+		CSPReportURI:          cfg.GetString("security.headers.cspReportURI"),
+		ContentTypeOptions:    cfg.GetString("security.headers.contentTypeOptions"),
+		FrameOptions:          cfg.GetString("security.headers.frameOptions"),
+		ReferrerPolicy:        cfg.GetString("security.headers.referrerPolicy"),
+		PermissionsPolicy:     cfg.GetString("security.headers.permissionsPolicy"),
+		HSTSMaxAge:            cfg.GetInt("security.headers.hsts.maxAge"),
+		HSTSIncludeSubdomains: cfg.GetBool("security.headers.hsts.includeSubdomains"),
+		HSTSPreload:           cfg.GetBool("security.headers.hsts.preload"),
+		CSPReportOnly:         cfg.GetBool("security.headers.cspReportOnly"),
+	}
+}
+
+func (h *Headers) write(header http.Header, nonce string) {
+	if h.HSTSMaxAge > 0 {
+		hsts := "max-age=" + strconv.Itoa(h.HSTSMaxAge)
+		if h.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if h.HSTSPreload {
+			hsts += "; preload"
+		}
+		header.Set("Strict-Transport-Security", hsts)
+	}
+	if h.ContentSecurityPolicy != "" {
+		csp := strings.ReplaceAll(h.ContentSecurityPolicy, NoncePlaceholder, nonce)
+		if h.CSPReportURI != "" {
+			csp += "; report-uri " + h.CSPReportURI
+		}
+		if h.CSPReportOnly {
+			header.Set("Content-Security-Policy-Report-Only", csp)
+		} else {
+			header.Set("Content-Security-Policy", csp)
+		}
+	}
+	setHeader(header, "X-Content-Type-Options", h.ContentTypeOptions)
+	setHeader(header, "X-Frame-Options", h.FrameOptions)
+	setHeader(header, "Referrer-Policy", h.ReferrerPolicy)
+	setHeader(header, "Permissions-Policy", h.PermissionsPolicy)
+}
+
+func setHeader(header http.Header, name, value string) {
+	if value != "" {
+		header.Set(name, value)
+	}
+}
+
+// Middleware adding security headers (HSTS, CSP, X-Content-Type-Options, X-Frame-Options,
+// Referrer-Policy and Permissions-Policy) to all responses.
+//
+// The headers are defined by the "security.headers" config entries, and can be overridden
+// for a route or router by setting the `MetaHeaders` meta to a `*Headers`.
+//
+// If the Content Security Policy contains `NoncePlaceholder`, a nonce is generated for each request,
+// put in the request's extra with the key `ExtraNonce` so it can be used in templates, and
+// replaces the placeholder in the header.
+type Middleware struct {
+	goyave.Component
+	headers *Headers
+}
+
+// Init the middleware and load the headers from the config.
+func (m *Middleware) Init(server *goyave.Server) {
+	m.Component.Init(server)
+	m.headers = HeadersFromConfig(server.Config())
+}
+
+// Handle adds the security headers to the response.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		headers := m.headers
+		if request.Route != nil {
+			if override, ok := request.Route.LookupMeta(MetaHeaders); ok {
+				if h, ok := override.(*Headers); ok && h != nil {
+					headers = h
+				}
+			}
+		}
+
+		nonce := ""
+		if strings.Contains(headers.ContentSecurityPolicy, NoncePlaceholder) {
+			nonce = generateNonce()
+			request.Extra[ExtraNonce{}] = nonce
+		}
+		headers.write(response.Header(), nonce)
+		next(response, request)
+	}
+}
+
+// Nonce returns the CSP nonce generated for the request by the security headers
+// middleware, or an empty string if there is none.
+func Nonce(request *goyave.Request) string {
+	nonce, _ := request.Extra[ExtraNonce{}].(string)
+	return nonce
+}
+
+func generateNonce() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(errors.New(err))
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// MaxReportSize the maximum size in bytes of a CSP violation report accepted by the
+// report route. Larger reports are rejected with "413 Request Entity Too Large".
+const MaxReportSize = 64 << 10
+
+// maxLoggedReportSize the maximum size in bytes of the JSON-encoded report written to the logs.
+const maxLoggedReportSize = 4 << 10
+
+// RegisterReportRoute registers a "POST /csp-report" route receiving the CSP violation reports
+// sent by browsers and logging them as warnings. Reports using both the legacy "report-uri"
+// format and the Reporting API format are supported. The full URI of this route can be used
+// as the value of the "security.headers.cspReportURI" config entry.
+//
+// Calling this function registers the JSON body parser for the "application/csp-report"
+// and "application/reports+json" media types (see `parse.RegisterParser()`) so reports
+// are accepted by the parse middleware.
+//
+// The route is exempted from CSRF protection because reports are cross-site requests.
+// Because it is not authenticated, reports larger than `MaxReportSize` are rejected and
+// the logged reports are truncated.
+// Returns the registered route, named "goyave.csp-report".
+func RegisterReportRoute(server *goyave.Server, router *goyave.Router) *goyave.Route {
+	parse.RegisterParser("application/csp-report", &parse.JSONParser{})
+	parse.RegisterParser("application/reports+json", &parse.JSONParser{})
+	return router.Post("/csp-report", func(response *goyave.Response, request *goyave.Request) {
+		if request.Request().ContentLength > MaxReportSize {
+			response.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(response, request.Body(), MaxReportSize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if stderrors.As(err, &maxBytesErr) {
+				response.Status(http.StatusRequestEntityTooLarge)
+				return
+			}
+			response.Error(errors.New(err))
+			return
+		}
+		var report any
+		if len(bytes.TrimSpace(body)) > 0 {
+			if err := json.Unmarshal(body, &report); err != nil {
+				response.Status(http.StatusBadRequest)
+				return
+			}
+		} else {
+			report = request.Data
+		}
+		if report == nil {
+			response.Status(http.StatusBadRequest)
+			return
+		}
+		server.Logger.Warn("Content Security Policy violation", "report", truncateReport(report), "userAgent", truncate(request.UserAgent(), 256))
+		response.Status(http.StatusNoContent)
+	}).Name("goyave.csp-report").SetMeta(csrf.MetaExempt, true)
+}
+
+// truncateReport returns the JSON representation of the given report,
+// truncated to `maxLoggedReportSize`.
+func truncateReport(report any) string {
+	b, err := json.Marshal(report)
+	if err != nil {
+		return ""
+	}
+	return truncate(string(b), maxLoggedReportSize)
+}
+
+func truncate(str string, maxLength int) string {
+	if len(str) <= maxLength {
+		return str
+	}
+	return strings.ToValidUTF8(str[:maxLength], "") + "...(truncated)"
+}
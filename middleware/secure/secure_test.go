@@ -0,0 +1,163 @@
+package secure
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/middleware/parse"
+	"goyave.dev/goyave/v5/slog"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func serveSecureTest(t *testing.T, router *goyave.Router, req *http.Request) (*http.Response, string) {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	res := recorder.Result()
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, res.Body.Close())
+	require.NoError(t, err)
+	return res, string(body)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		request := testutil.NewTestRequest(http.MethodGet, "/secure", nil)
+		result := server.TestMiddleware(&Middleware{}, request, func(response *goyave.Response, request *goyave.Request) {
+			assert.Empty(t, Nonce(request))
+			response.Status(http.StatusNoContent)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Equal(t, "max-age=31536000; includeSubDomains", result.Header.Get("Strict-Transport-Security"))
+		assert.Equal(t, "default-src 'self'", result.Header.Get("Content-Security-Policy"))
+		assert.Equal(t, "nosniff", result.Header.Get("X-Content-Type-Options"))
+		assert.Equal(t, "DENY", result.Header.Get("X-Frame-Options"))
+		assert.Equal(t, "strict-origin-when-cross-origin", result.Header.Get("Referrer-Policy"))
+		assert.NotContains(t, result.Header, "Permissions-Policy")
+	})
+
+	t.Run("config", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		cfg.Set("security.headers.hsts.maxAge", 0)
+		cfg.Set("security.headers.contentSecurityPolicy", "script-src 'nonce-{nonce}'")
+		cfg.Set("security.headers.cspReportOnly", true)
+		cfg.Set("security.headers.cspReportURI", "/csp-report")
+		cfg.Set("security.headers.frameOptions", "")
+		cfg.Set("security.headers.permissionsPolicy", "camera=()")
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+		request := testutil.NewTestRequest(http.MethodGet, "/secure", nil)
+		nonce := ""
+		result := server.TestMiddleware(&Middleware{}, request, func(response *goyave.Response, request *goyave.Request) {
+			nonce = Nonce(request)
+			response.Status(http.StatusNoContent)
+		})
+		assert.NoError(t, result.Body.Close())
+		assert.Len(t, nonce, 24)
+		assert.NotContains(t, result.Header, "Strict-Transport-Security")
+		assert.NotContains(t, result.Header, "Content-Security-Policy")
+		assert.Equal(t, "script-src 'nonce-"+nonce+"'; report-uri /csp-report", result.Header.Get("Content-Security-Policy-Report-Only"))
+		assert.NotContains(t, result.Header, "X-Frame-Options")
+		assert.Equal(t, "camera=()", result.Header.Get("Permissions-Policy"))
+	})
+
+	t.Run("meta_override", func(t *testing.T) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		router := goyave.NewRouter(server.Server)
+		router.GlobalMiddleware(&Middleware{})
+		handler := func(response *goyave.Response, _ *goyave.Request) {
+			response.Status(http.StatusNoContent)
+		}
+		router.Get("/default", handler)
+		headers := HeadersFromConfig(server.Config())
+		headers.FrameOptions = "SAMEORIGIN"
+		embed := router.Subrouter("/embed")
+		embed.SetMeta(MetaHeaders, headers)
+		embed.Get("/widget", handler)
+
+		res, _ := serveSecureTest(t, router, httptest.NewRequest(http.MethodGet, "/embed/widget", nil))
+		assert.Equal(t, "SAMEORIGIN", res.Header.Get("X-Frame-Options"))
+		assert.Equal(t, "nosniff", res.Header.Get("X-Content-Type-Options"))
+
+		res, _ = serveSecureTest(t, router, httptest.NewRequest(http.MethodGet, "/default", nil))
+		assert.Equal(t, "DENY", res.Header.Get("X-Frame-Options"))
+	})
+}
+
+func TestRegisterReportRoute(t *testing.T) {
+	logs := &bytes.Buffer{}
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault(), Logger: slog.New(slog.NewHandler(false, logs))})
+	router := goyave.NewRouter(server.Server)
+	router.GlobalMiddleware(&parse.Middleware{})
+	route := RegisterReportRoute(server.Server, router)
+	assert.Equal(t, "goyave.csp-report", route.GetName())
+
+	cases := []struct {
+		desc           string
+		contentType    string
+		body           string
+		expectedStatus int
+	}{
+		{desc: "csp-report", contentType: "application/csp-report", body: `{"csp-report":{"violated-directive":"script-src"}}`, expectedStatus: http.StatusNoContent},
+		{desc: "reports+json", contentType: "application/reports+json", body: `[{"type":"csp-violation","body":{"effectiveDirective":"script-src"}}]`, expectedStatus: http.StatusNoContent},
+		{desc: "json", contentType: "application/json", body: `{"csp-report":{"violated-directive":"script-src"}}`, expectedStatus: http.StatusNoContent},
+		{desc: "invalid", contentType: "application/csp-report", body: `{`, expectedStatus: http.StatusBadRequest},
+		{desc: "empty", contentType: "", body: ``, expectedStatus: http.StatusBadRequest},
+		{desc: "too_large", contentType: "application/csp-report", body: `{"csp-report":{"script-sample":"` + strings.Repeat("a", MaxReportSize) + `"}}`, expectedStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			logs.Reset()
+			req := httptest.NewRequest(http.MethodPost, "/csp-report", strings.NewReader(c.body))
+			if c.contentType != "" {
+				req.Header.Set("Content-Type", c.contentType)
+			}
+			res, _ := serveSecureTest(t, router, req)
+			assert.Equal(t, c.expectedStatus, res.StatusCode)
+			if c.expectedStatus == http.StatusNoContent {
+				assert.Contains(t, logs.String(), "Content Security Policy violation")
+				assert.Contains(t, logs.String(), "script-src")
+			} else {
+				assert.Empty(t, logs.String())
+			}
+		})
+	}
+}
+
+func TestRegisterReportRouteLimits(t *testing.T) {
+	logs := &bytes.Buffer{}
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault(), Logger: slog.New(slog.NewHandler(false, logs))})
+	router := goyave.NewRouter(server.Server)
+	RegisterReportRoute(server.Server, router)
+
+	t.Run("chunked_too_large", func(t *testing.T) {
+		logs.Reset()
+		body := `{"csp-report":{"script-sample":"` + strings.Repeat("a", MaxReportSize) + `"}}`
+		req := httptest.NewRequest(http.MethodPost, "/csp-report", strings.NewReader(body))
+		req.ContentLength = -1
+		req.Header.Set("Content-Type", "application/csp-report")
+		res, _ := serveSecureTest(t, router, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+		assert.Empty(t, logs.String())
+	})
+
+	t.Run("logs_truncated", func(t *testing.T) {
+		logs.Reset()
+		body := `{"csp-report":{"violated-directive":"script-src","script-sample":"` + strings.Repeat("a", MaxReportSize/2) + `"}}`
+		req := httptest.NewRequest(http.MethodPost, "/csp-report", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/csp-report")
+		res, _ := serveSecureTest(t, router, req)
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+		assert.Contains(t, logs.String(), "csp-report")
+		assert.Contains(t, logs.String(), "...(truncated)")
+		assert.Less(t, logs.Len(), maxLoggedReportSize*2)
+	})
+}
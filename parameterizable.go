@@ -17,6 +17,21 @@ type parameterizable struct {
 // If "ends" is set to true, the generated regex ends with "$", thus set "ends" to true
 // if you're compiling route parameters, set to false if you're compiling router parameters.
 func (p *parameterizable) compileParameters(uri string, ends bool, regexCache map[string]*regexp.Regexp) {
+	suffix := `/?$`
+	if ends {
+		suffix = "$"
+	}
+	p.compile(uri, "[^/]+", suffix, func(s string) string { return s }, regexCache)
+}
+
+// compileHostParameters parse the host parameters and compiles their regexes if needed.
+// Contrary to URIs, the literal parts of the host pattern are escaped, the default parameter
+// pattern doesn't match dots and the match is case-insensitive.
+func (p *parameterizable) compileHostParameters(host string, regexCache map[string]*regexp.Regexp) {
+	p.compile(strings.ToLower(host), "[^.]+", "$", regexp.QuoteMeta, regexCache)
+}
+
+func (p *parameterizable) compile(uri, defaultPattern, suffix string, escape func(string) string, regexCache map[string]*regexp.Regexp) {
 	idxs, err := p.braceIndices(uri)
 	if err != nil {
 		panic(err)
@@ -40,7 +55,7 @@ func (p *parameterizable) compileParameters(uri string, ends bool, regexCache ma
 			if parts[0] == "" {
 				panic(fmt.Errorf("invalid route parameter, missing name in %q", sub))
 			}
-			pattern := "[^/]+" // default pattern
+			pattern := defaultPattern
 			if len(parts) == 2 {
 				pattern = parts[1]
 				if pattern == "" {
@@ -48,23 +63,19 @@ func (p *parameterizable) compileParameters(uri string, ends bool, regexCache ma
 				}
 			}
 
-			builder.WriteString(raw)
+			builder.WriteString(escape(raw))
 			builder.WriteString("(")
 			builder.WriteString(pattern)
 			builder.WriteString(")")
 			end++ // Skip closing braces
 			p.parameters = append(p.parameters, parts[0])
 		}
-		builder.WriteString(uri[end:])
+		builder.WriteString(escape(uri[end:]))
 	} else {
-		builder.WriteString(uri)
+		builder.WriteString(escape(uri))
 	}
 
-	if ends {
-		builder.WriteString("$")
-	} else {
-		builder.WriteString(`/?$`)
-	}
+	builder.WriteString(suffix)
 
 	pattern := builder.String()
 	cachedRegex, ok := regexCache[pattern]
@@ -0,0 +1,169 @@
+package goyave
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// ResourceAction identifies one of the conventional actions of a resource controller.
+type ResourceAction string
+
+// Resource controller actions.
+const (
+	ActionIndex   ResourceAction = "index"
+	ActionShow    ResourceAction = "show"
+	ActionStore   ResourceAction = "store"
+	ActionUpdate  ResourceAction = "update"
+	ActionDestroy ResourceAction = "destroy"
+)
+
+// IndexController a resource controller implementing the "index" action (list the resources).
+type IndexController interface {
+	Index(response *Response, request *Request)
+}
+
+// ShowController a resource controller implementing the "show" action (get a single resource).
+type ShowController interface {
+	Show(response *Response, request *Request)
+}
+
+// StoreController a resource controller implementing the "store" action (create a resource).
+type StoreController interface {
+	Store(response *Response, request *Request)
+}
+
+// UpdateController a resource controller implementing the "update" action (update a resource).
+type UpdateController interface {
+	Update(response *Response, request *Request)
+}
+
+// DestroyController a resource controller implementing the "destroy" action (delete a resource).
+type DestroyController interface {
+	Destroy(response *Response, request *Request)
+}
+
+// ResourceOptions options for the registration of a resource controller.
+type ResourceOptions struct {
+	// IDParameter the name of the route parameter identifying a single resource.
+	// Defaults to the singular form of the resource name followed by "ID" (e.g. "userID" for "users").
+	IDParameter string
+
+	// IDPattern the pattern of the route parameter identifying a single resource.
+	// Defaults to "[0-9]+".
+	IDPattern string
+}
+
+// Resource a resource registered using `Router.Resource()`.
+type Resource struct {
+	// Router the subrouter in which the routes of the resource are registered.
+	Router *Router
+
+	routes      map[ResourceAction]*Route
+	name        string
+	idParameter string
+	idPattern   string
+}
+
+// Resource registers conventional REST routes for the actions implemented by the given controller
+// in a new subrouter having the resource name as prefix. For a resource named "users":
+//
+//	| Action  | Method     | URI                    | Route name    | Interface         |
+//	|---------|------------|------------------------|---------------|-------------------|
+//	| index   | GET        | /users                 | users.index   | IndexController   |
+//	| store   | POST       | /users                 | users.store   | StoreController   |
+//	| show    | GET        | /users/{userID:[0-9]+} | users.show    | ShowController    |
+//	| update  | PUT, PATCH | /users/{userID:[0-9]+} | users.update  | UpdateController  |
+//	| destroy | DELETE     | /users/{userID:[0-9]+} | users.destroy | DestroyController |
+//
+// Actions not implemented by the controller are not registered. Panics if the controller
+// doesn't implement any action. `Init()` is automatically called on the controller.
+//
+// The returned `*Resource` gives access to the registered routes so validation rules and
+// middleware can be added per action, and can be used to register nested resources.
+//
+//	users := router.Resource("users", userCtrl)
+//	users.Route(goyave.ActionStore).ValidateBody(userCtrl.StoreRequest)
+//	users.Resource("posts", postCtrl) // "/users/{userID:[0-9]+}/posts", "users.posts.index", ...
+//
+// Only the first options are used. If no options are given, the default options are used.
+func (r *Router) Resource(name string, controller Composable, options ...*ResourceOptions) *Resource {
+	return r.resource(r.Subrouter("/"+name), name, name, controller, options)
+}
+
+// Resource registers a nested resource controller. The nested resource's prefix contains the
+// ID parameter of this resource (e.g. "/users/{userID:[0-9]+}/posts") and its route names are
+// prefixed with the name of this resource (e.g. "users.posts.index").
+// See `Router.Resource()` for more details.
+func (r *Resource) Resource(name string, controller Composable, options ...*ResourceOptions) *Resource {
+	router := r.Router.Subrouter(fmt.Sprintf("/{%s:%s}/%s", r.idParameter, r.idPattern, name))
+	return r.Router.resource(router, name, r.name+"."+name, controller, options)
+}
+
+// Route returns the route registered for the given action, or `nil` if
+// the controller doesn't implement this action.
+func (r *Resource) Route(action ResourceAction) *Route {
+	return r.routes[action]
+}
+
+func (r *Router) resource(router *Router, name, routeName string, controller Composable, options []*ResourceOptions) *Resource {
+	opts := &ResourceOptions{}
+	if len(options) > 0 && options[0] != nil {
+		opts = options[0]
+	}
+	resource := &Resource{
+		Router:      router,
+		name:        routeName,
+		idParameter: opts.IDParameter,
+		idPattern:   opts.IDPattern,
+		routes:      make(map[ResourceAction]*Route, 5),
+	}
+	if resource.idParameter == "" {
+		resource.idParameter = singular(name) + "ID"
+	}
+	if resource.idPattern == "" {
+		resource.idPattern = "[0-9]+"
+	}
+
+	controller.Init(r.server)
+	idURI := fmt.Sprintf("/{%s:%s}", resource.idParameter, resource.idPattern)
+	if c, ok := controller.(IndexController); ok {
+		resource.register(ActionIndex, []string{http.MethodGet}, "/", c.Index)
+	}
+	if c, ok := controller.(StoreController); ok {
+		resource.register(ActionStore, []string{http.MethodPost}, "/", c.Store)
+	}
+	if c, ok := controller.(ShowController); ok {
+		resource.register(ActionShow, []string{http.MethodGet}, idURI, c.Show)
+	}
+	if c, ok := controller.(UpdateController); ok {
+		resource.register(ActionUpdate, []string{http.MethodPut, http.MethodPatch}, idURI, c.Update)
+	}
+	if c, ok := controller.(DestroyController); ok {
+		resource.register(ActionDestroy, []string{http.MethodDelete}, idURI, c.Destroy)
+	}
+
+	if len(resource.routes) == 0 {
+		panic(errors.NewSkip(fmt.Errorf("resource controller %T doesn't implement any action", controller), 4))
+	}
+	return resource
+}
+
+func (r *Resource) register(action ResourceAction, methods []string, uri string, handler Handler) {
+	r.routes[action] = r.Router.Route(methods, uri, handler).Name(r.name + "." + string(action))
+}
+
+// singular returns a naive singular form of the given english plural noun.
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "ss"):
+		return name
+	case strings.HasSuffix(name, "s"):
+		return name[:len(name)-1]
+	}
+	return name
+}
@@ -0,0 +1,155 @@
+package goyave
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/validation"
+)
+
+type testResourceController struct {
+	Component
+	initialized bool
+}
+
+func (c *testResourceController) Init(server *Server) {
+	c.initialized = true
+	c.Component.Init(server)
+}
+
+func (c *testResourceController) Index(response *Response, _ *Request) {
+	response.String(http.StatusOK, "index")
+}
+
+func (c *testResourceController) Show(response *Response, request *Request) {
+	response.String(http.StatusOK, "show "+request.RouteParams["userID"])
+}
+
+func (c *testResourceController) Store(response *Response, _ *Request) {
+	response.String(http.StatusCreated, "store")
+}
+
+func (c *testResourceController) Update(response *Response, request *Request) {
+	response.String(http.StatusOK, "update "+request.RouteParams["userID"])
+}
+
+func (c *testResourceController) Destroy(response *Response, request *Request) {
+	response.String(http.StatusOK, "destroy "+request.RouteParams["userID"])
+}
+
+type testNestedResourceController struct {
+	Component
+}
+
+func (c *testNestedResourceController) Index(response *Response, request *Request) {
+	response.String(http.StatusOK, "posts of "+request.RouteParams["userID"])
+}
+
+func (c *testNestedResourceController) Show(response *Response, request *Request) {
+	response.String(http.StatusOK, "post "+request.RouteParams["slug"]+" of "+request.RouteParams["userID"])
+}
+
+func TestResource(t *testing.T) {
+	t.Run("routes", func(t *testing.T) {
+		router, logs := prepareRouteTableTest(t, true)
+		ctrl := &testResourceController{}
+		users := router.Resource("users", ctrl)
+		assert.True(t, ctrl.initialized)
+		assert.Equal(t, router.server, ctrl.Server())
+		assert.Equal(t, "/users", users.Router.prefix)
+
+		cases := []struct {
+			action  ResourceAction
+			uri     string
+			methods []string
+		}{
+			{action: ActionIndex, uri: "/users", methods: []string{http.MethodGet, http.MethodHead}},
+			{action: ActionStore, uri: "/users", methods: []string{http.MethodPost}},
+			{action: ActionShow, uri: "/users/{userID:[0-9]+}", methods: []string{http.MethodGet, http.MethodHead}},
+			{action: ActionUpdate, uri: "/users/{userID:[0-9]+}", methods: []string{http.MethodPut, http.MethodPatch}},
+			{action: ActionDestroy, uri: "/users/{userID:[0-9]+}", methods: []string{http.MethodDelete}},
+		}
+		for _, c := range cases {
+			route := users.Route(c.action)
+			require.NotNil(t, route, c.action)
+			assert.Equal(t, "users."+string(c.action), route.GetName())
+			assert.Equal(t, route, router.GetRoute("users."+string(c.action)))
+			assert.Equal(t, c.uri, route.GetFullURI())
+			assert.Equal(t, c.methods, route.GetMethods())
+		}
+
+		requests := []struct {
+			method         string
+			path           string
+			expectedBody   string
+			expectedStatus int
+		}{
+			{method: http.MethodGet, path: "/users", expectedStatus: http.StatusOK, expectedBody: "index"},
+			{method: http.MethodPost, path: "/users", expectedStatus: http.StatusCreated, expectedBody: "store"},
+			{method: http.MethodGet, path: "/users/12", expectedStatus: http.StatusOK, expectedBody: "show 12"},
+			{method: http.MethodPut, path: "/users/12", expectedStatus: http.StatusOK, expectedBody: "update 12"},
+			{method: http.MethodPatch, path: "/users/12", expectedStatus: http.StatusOK, expectedBody: "update 12"},
+			{method: http.MethodDelete, path: "/users/12", expectedStatus: http.StatusOK, expectedBody: "destroy 12"},
+			{method: http.MethodGet, path: "/users/abc", expectedStatus: http.StatusNotFound, expectedBody: "{\"error\":\"Not Found\"}\n"},
+		}
+		for _, c := range requests {
+			t.Run(c.method+"_"+c.path, func(t *testing.T) {
+				res, body := serveVersionTest(t, router, c.method, c.path, nil)
+				assert.Equal(t, c.expectedStatus, res.StatusCode)
+				assert.Equal(t, c.expectedBody, body)
+			})
+		}
+		assert.Empty(t, logs.String())
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		router, logs := prepareRouteTableTest(t, true)
+		users := router.Resource("users", &testResourceController{})
+		posts := users.Resource("posts", &testNestedResourceController{}, &ResourceOptions{IDParameter: "slug", IDPattern: "[a-z-]+"})
+
+		assert.Nil(t, posts.Route(ActionStore))
+		assert.Nil(t, posts.Route(ActionUpdate))
+		assert.Nil(t, posts.Route(ActionDestroy))
+		assert.Equal(t, "/users/{userID:[0-9]+}/posts", posts.Route(ActionIndex).GetFullURI())
+		assert.Equal(t, "/users/{userID:[0-9]+}/posts/{slug:[a-z-]+}", posts.Route(ActionShow).GetFullURI())
+		assert.Equal(t, "users.posts.index", posts.Route(ActionIndex).GetName())
+		assert.Equal(t, "/users/3/posts/hello-world", router.GetRoute("users.posts.show").BuildURI("3", "hello-world"))
+
+		_, body := serveVersionTest(t, router, http.MethodGet, "/users/3/posts", nil)
+		assert.Equal(t, "posts of 3", body)
+		_, body = serveVersionTest(t, router, http.MethodGet, "/users/3/posts/hello-world", nil)
+		assert.Equal(t, "post hello-world of 3", body)
+		_, body = serveVersionTest(t, router, http.MethodGet, "/users/3", nil)
+		assert.Equal(t, "show 3", body)
+		assert.Empty(t, logs.String())
+	})
+
+	t.Run("per_action_validation_and_middleware", func(t *testing.T) {
+		router := prepareRouterTest()
+		users := router.Resource("users", &testResourceController{})
+		middleware := &testMiddleware{key: "store"}
+		users.Route(ActionStore).
+			Middleware(middleware).
+			ValidateBody(func(_ *Request) validation.RuleSet { return validation.RuleSet{} })
+
+		assert.True(t, hasMiddleware[*testMiddleware](users.Route(ActionStore).middleware))
+		assert.True(t, hasMiddleware[*validateRequestMiddleware](users.Route(ActionStore).middleware))
+		assert.False(t, hasMiddleware[*testMiddleware](users.Route(ActionIndex).middleware))
+	})
+
+	t.Run("no_action", func(t *testing.T) {
+		router := prepareRouterTest()
+		assert.Panics(t, func() {
+			router.Resource("users", &testController{})
+		})
+	})
+}
+
+func TestSingular(t *testing.T) {
+	assert.Equal(t, "user", singular("users"))
+	assert.Equal(t, "category", singular("categories"))
+	assert.Equal(t, "address", singular("address"))
+	assert.Equal(t, "sheep", singular("sheep"))
+}
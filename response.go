@@ -44,9 +44,12 @@ type Response struct {
 	// Used to check if controller didn't write anything so
 	// core can write default 204 No Content.
 	// See RFC 7231, 6.3.5
-	empty       bool
-	wroteHeader bool
-	hijacked    bool
+	empty          bool
+	wroteHeader    bool
+	hijacked       bool
+	headerDeferred bool
+
+	headerHooks []func()
 }
 
 // NewResponse create a new Response using the given `http.ResponseWriter` and request.
@@ -69,6 +72,7 @@ func NewResponse(server *Server, request *Request, writer http.ResponseWriter) *
 // child writer if it implements PreWriter.
 func (r *Response) PreWrite(b []byte) {
 	r.empty = false
+	r.headerDeferred = false
 	if pr, ok := r.writer.(PreWriter); ok {
 		pr.PreWrite(b)
 	}
@@ -76,10 +80,21 @@ func (r *Response) PreWrite(b []byte) {
 		if r.status == 0 {
 			r.status = http.StatusOK
 		}
-		r.WriteHeader(r.status)
+		if !r.headerDeferred {
+			r.WriteHeader(r.status)
+		}
 	}
 }
 
+// DeferHeader prevents the current call of `PreWrite()` from writing the response header.
+// Chained writers (see `SetWriter()`) can call it from their own `PreWrite()` implementation
+// if they need to buffer the beginning of the body before deciding which headers to set.
+// These writers are then responsible for calling `WriteHeader()` before writing to their
+// child writer.
+func (r *Response) DeferHeader() {
+	r.headerDeferred = true
+}
+
 // --------------------------------------
 // http.ResponseWriter implementation
 
@@ -92,17 +107,32 @@ func (r *Response) Write(data []byte) (int, error) {
 }
 
 // WriteHeader sends an HTTP response header with the provided
-// status code.
+// status code. The hooks registered with `BeforeWriteHeader()` are executed first.
 // Prefer using "Status()" method instead.
 // Calling this method a second time will have no effect.
 func (r *Response) WriteHeader(status int) {
 	if !r.wroteHeader {
 		r.status = status
+		hooks := r.headerHooks
+		r.headerHooks = nil
+		for _, hook := range hooks {
+			hook()
+		}
 		r.wroteHeader = true
 		r.responseWriter.WriteHeader(status)
 	}
 }
 
+// BeforeWriteHeader registers a function executed right before the response header is written,
+// whether it is written explicitly with `WriteHeader()` (e.g. by `http.Redirect()`) or implicitly
+// when the body is written. Hooks can alter the response header and set cookies.
+// They are executed in registration order. Has no effect if the header is already written.
+func (r *Response) BeforeWriteHeader(hook func()) {
+	if !r.wroteHeader {
+		r.headerHooks = append(r.headerHooks, hook)
+	}
+}
+
 // Header returns the header map that will be sent.
 func (r *Response) Header() http.Header {
 	return r.responseWriter.Header()
@@ -53,6 +53,16 @@ func (r *testChainedWriter) PreWrite(b []byte) {
 	r.prewritten = b
 }
 
+type testDeferringWriter struct {
+	testChainedWriter
+	response *Response
+}
+
+func (r *testDeferringWriter) PreWrite(b []byte) {
+	r.testChainedWriter.PreWrite(b)
+	r.response.DeferHeader()
+}
+
 func (r *testChainedWriter) Close() error {
 	r.closed = true
 	return nil
@@ -304,6 +314,46 @@ func TestResponse(t *testing.T) {
 		assert.True(t, newWriter.closed)
 	})
 
+	t.Run("DeferHeader", func(t *testing.T) {
+		resp, _ := newTestReponse()
+		newWriter := &testDeferringWriter{response: resp}
+		resp.SetWriter(newWriter)
+
+		resp.PreWrite([]byte{1, 2, 3})
+		assert.Equal(t, []byte{1, 2, 3}, newWriter.prewritten)
+		assert.False(t, resp.IsHeaderWritten())
+		assert.False(t, resp.IsEmpty())
+		assert.Equal(t, http.StatusOK, resp.GetStatus())
+
+		resp.SetWriter(&testChainedWriter{})
+		resp.PreWrite([]byte{4})
+		assert.True(t, resp.IsHeaderWritten())
+	})
+
+	t.Run("BeforeWriteHeader", func(t *testing.T) {
+		resp, recorder := newTestReponse()
+		calls := []string{}
+		resp.BeforeWriteHeader(func() {
+			calls = append(calls, "first")
+			resp.Header().Set("X-Hook", "value")
+		})
+		resp.BeforeWriteHeader(func() {
+			calls = append(calls, "second")
+		})
+
+		resp.WriteHeader(http.StatusFound)
+		resp.WriteHeader(http.StatusOK)
+		assert.Equal(t, []string{"first", "second"}, calls)
+		assert.Equal(t, http.StatusFound, recorder.Code)
+		assert.Equal(t, "value", recorder.Header().Get("X-Hook"))
+
+		resp.BeforeWriteHeader(func() {
+			assert.Fail(t, "hook registered after the header is written should not be executed")
+		})
+		resp.String(http.StatusOK, "body")
+		assert.Len(t, calls, 2)
+	})
+
 	t.Run("Error_no_debug", func(t *testing.T) {
 		resp, _ := newTestReponse()
 		logBuffer := &bytes.Buffer{}
@@ -3,6 +3,7 @@ package goyave
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/samber/lo"
@@ -16,12 +17,13 @@ import (
 // hold Meta information that can be used by generic middleware to
 // alter their behavior depending on the route being served.
 type Route struct {
-	name    string
-	uri     string
-	methods []string
-	parent  *Router
-	Meta    map[string]any
-	handler Handler
+	name     string
+	uri      string
+	methods  []string
+	parent   *Router
+	Meta     map[string]any
+	handler  Handler
+	versions *VersionRange
 	middlewareHolder
 	parameterizable
 }
@@ -194,15 +196,47 @@ func (r *Route) Middleware(middleware ...Middleware) *Route {
 // BuildURL build a full URL pointing to this route.
 // Panics if the amount of parameters doesn't match the amount of
 // actual parameters for this route.
+//
+// If the route belongs to a host router, the host parameters come first and
+// the host of the base URL is replaced with the host built from the pattern.
+// The scheme and port of the base URL are kept. Relative host patterns are resolved
+// against the "server.domain" config entry (see `Router.Host()`): with the "example.com"
+// domain, a route of the "{tenant}" host router builds URLs such as "http://acme.example.com:8080/".
 func (r *Route) BuildURL(parameters ...string) string {
-	return r.parent.server.BaseURL() + r.BuildURI(parameters...)
+	return r.buildURL(r.parent.server.BaseURL(), parameters)
 }
 
 // BuildProxyURL build a full URL pointing to this route using the proxy base URL.
 // Panics if the amount of parameters doesn't match the amount of
 // actual parameters for this route.
+//
+// If the route belongs to a host router, the host parameters come first and
+// the host of the proxy base URL is replaced with the host built from the pattern,
+// as described in `Route.BuildURL()`.
 func (r *Route) BuildProxyURL(parameters ...string) string {
-	return r.parent.server.ProxyBaseURL() + r.BuildURI(parameters...)
+	return r.buildURL(r.parent.server.ProxyBaseURL(), parameters)
+}
+
+func (r *Route) buildURL(baseURL string, parameters []string) string {
+	hostRouter := r.parent.hostRouter()
+	if hostRouter == nil {
+		return baseURL + r.BuildURI(parameters...)
+	}
+
+	hostParams := len(hostRouter.host.parameters)
+	if len(parameters) < hostParams {
+		panic(errors.Errorf("BuildURL: route host has %d parameters, %d given", hostParams, len(parameters)))
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		panic(errors.New(err))
+	}
+	host := replaceParameters(hostRouter.hostPattern, parameters[:hostParams])
+	if port := u.Port(); port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+	return u.String() + r.BuildURI(parameters[hostParams:]...)
 }
 
 // BuildURI build a full URI pointing to this route. The returned
@@ -216,22 +250,28 @@ func (r *Route) BuildURI(parameters ...string) string {
 		panic(errors.Errorf("BuildURI: route has %d parameters, %d given", len(fullParameters), len(parameters)))
 	}
 
+	return replaceParameters(fullURI, parameters)
+}
+
+// replaceParameters replaces the parameter definitions in the given pattern
+// with the given parameters, in order.
+func replaceParameters(pattern string, parameters []string) string {
 	var builder strings.Builder
-	builder.Grow(len(fullURI))
+	builder.Grow(len(pattern))
 
-	idxs, _ := r.braceIndices(fullURI)
+	idxs, _ := (&parameterizable{}).braceIndices(pattern)
 	length := len(idxs)
 	end := 0
 	currentParam := 0
 	for i := 0; i < length; i += 2 {
-		raw := fullURI[end:idxs[i]]
+		raw := pattern[end:idxs[i]]
 		end = idxs[i+1]
 		builder.WriteString(raw)
 		builder.WriteString(parameters[currentParam])
 		currentParam++
 		end++ // Skip closing braces
 	}
-	builder.WriteString(fullURI[end:])
+	builder.WriteString(pattern[end:])
 
 	return builder.String()
 }
@@ -0,0 +1,296 @@
+package goyave
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/samber/lo"
+	"goyave.dev/goyave/v5/cors"
+	errorutil "goyave.dev/goyave/v5/util/errors"
+)
+
+// RouteEntry flattened representation of a route and all the settings
+// it inherits from its parent routers.
+type RouteEntry struct {
+	// Route the described route.
+	Route *Route
+
+	// CORS the CORS options applied to this route, or `nil`.
+	CORS *cors.Options
+
+	// Meta all the meta values applying to this route, including the ones inherited
+	// from its parent routers. The `MetaCORS` meta is not included.
+	Meta map[string]any
+
+	Name string
+
+	// Host the host pattern of the closest host router, if any.
+	Host    string
+	URI     string
+	Methods []string
+
+	// Middleware the middleware executed for this route, in execution order.
+	// Global middleware come first.
+	Middleware []Middleware
+
+	// ValidateBody is true if the route has body validation rules.
+	ValidateBody bool
+
+	// Versions the range of API versions the route is available in, or `nil`
+	// if the route is available in all versions.
+	Versions *VersionRange
+
+	// ValidateQuery is true if the route has query validation rules.
+	ValidateQuery bool
+}
+
+type routeEntryJSON struct {
+	CORS          *cors.Options  `json:"cors,omitempty"`
+	Meta          map[string]any `json:"meta,omitempty"`
+	Name          string         `json:"name,omitempty"`
+	Host          string         `json:"host,omitempty"`
+	URI           string         `json:"uri"`
+	Methods       []string       `json:"methods"`
+	Versions      *VersionRange  `json:"versions,omitempty"`
+	Middleware    []string       `json:"middleware"`
+	ValidateBody  bool           `json:"validateBody"`
+	ValidateQuery bool           `json:"validateQuery"`
+}
+
+// MarshalJSON the middleware are represented by their type name. Meta values that
+// cannot be marshaled to JSON are represented using their default format.
+func (e *RouteEntry) MarshalJSON() ([]byte, error) {
+	meta := make(map[string]any, len(e.Meta))
+	for k, v := range e.Meta {
+		if _, err := json.Marshal(v); err != nil {
+			v = fmt.Sprintf("%v", v)
+		}
+		meta[k] = v
+	}
+	return json.Marshal(routeEntryJSON{
+		CORS:          e.CORS,
+		Meta:          meta,
+		Name:          e.Name,
+		Host:          e.Host,
+		URI:           e.URI,
+		Methods:       e.Methods,
+		Versions:      e.Versions,
+		Middleware:    e.MiddlewareNames(),
+		ValidateBody:  e.ValidateBody,
+		ValidateQuery: e.ValidateQuery,
+	})
+}
+
+// MiddlewareNames returns the type names of the middleware executed for this route,
+// in execution order.
+func (e *RouteEntry) MiddlewareNames() []string {
+	return lo.Map(e.Middleware, func(m Middleware, _ int) string {
+		return fmt.Sprintf("%T", m)
+	})
+}
+
+// RouteTable flattens this router and all its subrouters into a list of
+// route entries. The entries are sorted in matching order: the routes of
+// subrouters come before the routes of their parent.
+func (r *Router) RouteTable() []*RouteEntry {
+	return r.appendRouteEntries(make([]*RouteEntry, 0, len(r.routes)))
+}
+
+func (r *Router) appendRouteEntries(entries []*RouteEntry) []*RouteEntry {
+	for _, subrouter := range r.subrouters {
+		entries = subrouter.appendRouteEntries(entries)
+	}
+	for _, route := range r.routes {
+		entries = append(entries, route.entry())
+	}
+	return entries
+}
+
+func (r *Route) entry() *RouteEntry {
+	routers := make([]*Router, 0, 3)
+	for router := r.parent; router != nil; router = router.parent {
+		routers = append(routers, router)
+	}
+
+	middleware := r.parent.globalMiddleware.GetMiddleware()
+	meta := make(map[string]any, len(r.Meta))
+	for i := len(routers) - 1; i >= 0; i-- {
+		middleware = append(middleware, routers[i].middleware...)
+		maps.Copy(meta, routers[i].Meta)
+	}
+	middleware = append(middleware, r.middleware...)
+	maps.Copy(meta, r.Meta)
+	delete(meta, MetaCORS)
+
+	corsMeta, _ := r.LookupMeta(MetaCORS)
+	corsOptions, _ := corsMeta.(*cors.Options)
+	validation := findMiddleware[*validateRequestMiddleware](r.middleware)
+	host := ""
+	if hostRouter := r.parent.hostRouter(); hostRouter != nil {
+		host = hostRouter.hostPattern
+	}
+	return &RouteEntry{
+		Route:         r,
+		CORS:          corsOptions,
+		Meta:          meta,
+		Name:          r.name,
+		Host:          host,
+		URI:           r.GetFullURI(),
+		Methods:       r.GetMethods(),
+		Middleware:    middleware,
+		Versions:      r.GetVersions(),
+		ValidateBody:  validation != nil && validation.BodyRules != nil,
+		ValidateQuery: validation != nil && validation.QueryRules != nil,
+	}
+}
+
+// WriteRouteTable writes the route table of the given router as
+// a human-readable text table. This is suitable for CLI output.
+// The URI of routes belonging to a host router is prefixed with the host pattern.
+func WriteRouteTable(w io.Writer, router *Router) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "METHODS\tURI\tNAME\tVALIDATION\tMIDDLEWARE"); err != nil {
+		return errorutil.New(err)
+	}
+	for _, entry := range router.RouteTable() {
+		validation := make([]string, 0, 2)
+		if entry.ValidateBody {
+			validation = append(validation, "body")
+		}
+		if entry.ValidateQuery {
+			validation = append(validation, "query")
+		}
+		_, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			strings.Join(entry.Methods, ","),
+			entry.Host+entry.URI,
+			lo.Ternary(entry.Name == "", "-", entry.Name),
+			lo.Ternary(len(validation) == 0, "-", strings.Join(validation, ",")),
+			strings.Join(entry.MiddlewareNames(), " > "),
+		)
+		if err != nil {
+			return errorutil.New(err)
+		}
+	}
+	return errorutil.New(tw.Flush())
+}
+
+// checkRouteConflicts reports the conflicts caused by the registration of the given route.
+// A route is unreachable if a route previously registered in the same router matches its URI
+// with at least one common method, or if a subrouter of the same router matches its URI.
+// This check is best-effort and doesn't take parameter patterns overlap into account.
+// Routes of versioned routers are not checked against each other because the same route
+// can be registered for different versions.
+func (r *Router) checkRouteConflicts(route *Route) {
+	for _, subrouter := range r.subrouters {
+		if subrouter.matchesPrefix(route.uri) {
+			r.reportRouteConflict(route.describe(), "router "+subrouter.describe())
+			return
+		}
+	}
+	if r.versioning != nil {
+		return
+	}
+	for _, other := range r.routes {
+		if other == route || !other.shadows(route) || len(lo.Intersect(other.methods, route.methods)) == 0 {
+			continue
+		}
+		r.reportRouteConflict(route.describe(), other.describe())
+		return
+	}
+}
+
+// checkSubrouterConflicts reports the conflicts caused by the registration of the given subrouter.
+// A subrouter shadows the routes of its parent matching its prefix because subrouters
+// are matched first. A subrouter is unreachable if a subrouter previously registered in the
+// same parent matches its prefix.
+func (r *Router) checkSubrouterConflicts(subrouter *Router) {
+	if subrouter.prefix == "" {
+		return
+	}
+	for _, other := range r.subrouters {
+		if other == subrouter {
+			break
+		}
+		if other.matchesPrefix(subrouter.prefix) || (other.regex != nil && other.regex.String() == subrouter.regex.String()) {
+			r.reportRouteConflict("router "+subrouter.describe(), "router "+other.describe())
+			return
+		}
+	}
+	for _, route := range r.routes {
+		if subrouter.matchesPrefix(route.uri) {
+			r.reportRouteConflict(route.describe(), "router "+subrouter.describe())
+		}
+	}
+}
+
+// checkHostConflicts reports the conflicts caused by the registration of the given host router.
+// A host router is unreachable if a host router previously registered in the same parent
+// matches its host pattern.
+func (r *Router) checkHostConflicts(hostRouter *Router) {
+	for _, other := range r.subrouters {
+		if other == hostRouter {
+			break
+		}
+		if other.host == nil || other.prefix != "" {
+			continue
+		}
+		if other.host.regex.MatchString(strings.ToLower(hostRouter.hostPattern)) || other.host.regex.String() == hostRouter.host.regex.String() {
+			r.reportRouteConflict("host router "+strconv.Quote(hostRouter.hostPattern), "host router "+strconv.Quote(other.hostPattern))
+			return
+		}
+	}
+}
+
+func (r *Router) reportRouteConflict(shadowed, shadowedBy string) {
+	if r.server == nil {
+		return
+	}
+	err := fmt.Errorf("%s is unreachable: shadowed by %s", shadowed, shadowedBy)
+	if r.server.config.GetBool("server.strictRouting") {
+		panic(errorutil.New(err))
+	}
+	r.server.Logger.Warn(err.Error())
+}
+
+// matchesPrefix returns true if the prefix of this router matches the
+// beginning of the given URI definition.
+func (r *Router) matchesPrefix(uri string) bool {
+	if r.regex == nil {
+		return false
+	}
+	i := -1
+	if len(uri) > 0 {
+		i = nthIndex(uri[1:], "/", r.slashCount) + 1
+	}
+	if i <= 0 {
+		i = len(uri)
+	}
+	return r.regex.MatchString(uri[:i])
+}
+
+// shadows returns true if this route matches the URI definition of the
+// other route or if both URI definitions result in the same pattern.
+func (r *Route) shadows(other *Route) bool {
+	return r.regex.MatchString(other.uri) || r.regex.String() == other.regex.String()
+}
+
+func (r *Router) describe() string {
+	uri := ""
+	for router := r; router != nil; router = router.parent {
+		uri = router.prefix + uri
+	}
+	return fmt.Sprintf("%q", uri)
+}
+
+func (r *Route) describe() string {
+	s := fmt.Sprintf("route \"%s %s\"", strings.Join(r.methods, ","), r.GetFullURI())
+	if r.name != "" {
+		s += fmt.Sprintf(" (%s)", r.name)
+	}
+	return s
+}
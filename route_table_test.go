@@ -0,0 +1,190 @@
+package goyave
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/cors"
+	"goyave.dev/goyave/v5/slog"
+	"goyave.dev/goyave/v5/validation"
+)
+
+func prepareRouteTableTest(t *testing.T, strict bool) (*Router, *bytes.Buffer) {
+	cfg := config.LoadDefault()
+	cfg.Set("server.strictRouting", strict)
+	buffer := &bytes.Buffer{}
+	server, err := New(Options{Config: cfg, Logger: slog.New(slog.NewHandler(false, buffer))})
+	require.NoError(t, err)
+	return NewRouter(server), buffer
+}
+
+func TestRouteTable(t *testing.T) {
+	router, _ := prepareRouteTableTest(t, false)
+	global := &testMiddleware{key: "global"}
+	routerMiddleware := &testMiddleware{key: "router"}
+	subrouterMiddleware := &testMiddleware{key: "subrouter"}
+	routeMiddleware := &testMiddleware{key: "route"}
+	router.GlobalMiddleware(global)
+	router.Middleware(routerMiddleware)
+	router.SetMeta("meta", "root")
+	router.SetMeta("func", func() {})
+
+	router.Get("/hello", nil).Name("hello")
+	router.Host("{tenant}.example.com").Get("/tenant", nil).Name("tenant")
+	users := router.Subrouter("/users")
+	users.Middleware(subrouterMiddleware)
+	users.SetMeta("meta", "users")
+	corsOptions := cors.Default()
+	users.CORS(corsOptions)
+	users.Post("/", nil).
+		Name("users.store").
+		Middleware(routeMiddleware).
+		ValidateBody(func(_ *Request) validation.RuleSet { return validation.RuleSet{} }).
+		SetMeta("route-meta", 1)
+	users.Get("/{id}", nil).ValidateQuery(func(_ *Request) validation.RuleSet { return validation.RuleSet{} })
+
+	table := router.RouteTable()
+	require.Len(t, table, 4)
+
+	tenant := table[0]
+	assert.Equal(t, "{tenant}.example.com", tenant.Host)
+	assert.Equal(t, "/tenant", tenant.URI)
+	table = table[1:]
+
+	store := table[0]
+	assert.Equal(t, router.GetRoute("users.store"), store.Route)
+	assert.Equal(t, "users.store", store.Name)
+	assert.Equal(t, "/users", store.URI)
+	assert.Equal(t, []string{http.MethodPost, http.MethodOptions}, store.Methods)
+	assert.Equal(t, corsOptions, store.CORS)
+	assert.True(t, store.ValidateBody)
+	assert.False(t, store.ValidateQuery)
+	assert.Equal(t, "users", store.Meta["meta"])
+	assert.Equal(t, 1, store.Meta["route-meta"])
+	assert.NotContains(t, store.Meta, MetaCORS)
+
+	middleware := store.Middleware
+	require.Len(t, middleware, 8)
+	assert.IsType(t, &recoveryMiddleware{}, middleware[0])
+	assert.IsType(t, &languageMiddleware{}, middleware[1])
+	assert.Equal(t, global, middleware[2])
+	assert.IsType(t, &corsMiddleware{}, middleware[3])
+	assert.Equal(t, routerMiddleware, middleware[4])
+	assert.Equal(t, subrouterMiddleware, middleware[5])
+	assert.Equal(t, routeMiddleware, middleware[6])
+	assert.IsType(t, &validateRequestMiddleware{}, middleware[7])
+	assert.Equal(t, "*goyave.testMiddleware", store.MiddlewareNames()[2])
+
+	show := table[1]
+	assert.Equal(t, "/users/{id}", show.URI)
+	assert.Empty(t, show.Name)
+	assert.False(t, show.ValidateBody)
+	assert.True(t, show.ValidateQuery)
+
+	hello := table[2]
+	assert.Equal(t, "hello", hello.Name)
+	assert.Nil(t, hello.CORS)
+	assert.Equal(t, "root", hello.Meta["meta"])
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(router.RouteTable())
+		require.NoError(t, err)
+		var result []map[string]any
+		require.NoError(t, json.Unmarshal(b, &result))
+		require.Len(t, result, 4)
+		assert.Equal(t, "{tenant}.example.com", result[0]["host"])
+		assert.NotContains(t, result[1], "host")
+		result = result[1:]
+		assert.Equal(t, "users.store", result[0]["name"])
+		assert.Equal(t, "/users", result[0]["uri"])
+		assert.Equal(t, true, result[0]["validateBody"])
+		assert.Contains(t, result[0], "cors")
+		assert.NotContains(t, result[2], "cors")
+		assert.Contains(t, result[0]["middleware"], "*goyave.testMiddleware")
+		assert.IsType(t, "", result[0]["meta"].(map[string]any)["func"])
+	})
+
+	t.Run("text", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, WriteRouteTable(buf, router))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 5)
+		assert.Equal(t, []string{"METHODS", "URI", "NAME", "VALIDATION", "MIDDLEWARE"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{"GET,HEAD", "{tenant}.example.com/tenant", "tenant", "-"}, strings.Fields(lines[1])[:4])
+		lines = lines[1:]
+		assert.Equal(t, []string{"POST,OPTIONS", "/users", "users.store", "body"}, strings.Fields(lines[1])[:4])
+		assert.Equal(t, []string{"GET,OPTIONS,HEAD", "/users/{id}", "-", "query"}, strings.Fields(lines[2])[:4])
+		assert.Equal(t, []string{"GET,HEAD", "/hello", "hello", "-"}, strings.Fields(lines[3])[:4])
+		assert.Contains(t, lines[1], "*goyave.recoveryMiddleware > *goyave.languageMiddleware")
+	})
+}
+
+func TestRouteConflicts(t *testing.T) {
+	t.Run("no_conflict", func(t *testing.T) {
+		router, logs := prepareRouteTableTest(t, false)
+		router.Get("/users/new", nil)
+		router.Get("/users/{id}", nil)
+		router.Post("/users/{id}", nil)
+		router.Subrouter("/conflict")
+		router.Subrouter("/conflict-2")
+		router.Group().Get("/group", nil)
+		router.Group().Get("/group-2", nil)
+		assert.Empty(t, logs.String())
+	})
+
+	t.Run("route_shadowed_by_route", func(t *testing.T) {
+		router, logs := prepareRouteTableTest(t, false)
+		router.Get("/users/{id}", nil).Name("users.show")
+		router.Get("/users/new", nil)
+		assert.Contains(t, logs.String(), `route \"GET,HEAD /users/new\" is unreachable: shadowed by route \"GET,HEAD /users/{id}\" (users.show)`)
+
+		logs.Reset()
+		router.Post("/users/new", nil)
+		assert.Empty(t, logs.String())
+
+		router.Get("/users/{userId}", nil)
+		assert.Contains(t, logs.String(), `GET,HEAD /users/{userId}\" is unreachable`)
+	})
+
+	t.Run("route_shadowed_by_subrouter", func(t *testing.T) {
+		router, logs := prepareRouteTableTest(t, false)
+		router.Get("/categories/test", nil)
+		assert.Empty(t, logs.String())
+		router.Subrouter("/categories")
+		assert.Contains(t, logs.String(), `route \"GET,HEAD /categories/test\" is unreachable: shadowed by router \"/categories\"`)
+
+		logs.Reset()
+		router.Get("/categories/{id}", nil)
+		assert.Contains(t, logs.String(), `route \"GET,HEAD /categories/{id}\" is unreachable: shadowed by router \"/categories\"`)
+	})
+
+	t.Run("subrouter_shadowed_by_subrouter", func(t *testing.T) {
+		router, logs := prepareRouteTableTest(t, false)
+		router.Subrouter("/{name}")
+		router.Subrouter("/users")
+		assert.Contains(t, logs.String(), `router \"/users\" is unreachable: shadowed by router \"/{name}\"`)
+	})
+
+	t.Run("host_shadowed_by_host", func(t *testing.T) {
+		router, logs := prepareRouteTableTest(t, false)
+		router.Host("admin.example.com")
+		router.Host("{tenant}.example.com")
+		assert.Empty(t, logs.String())
+		router.Host("api.example.com")
+		assert.Contains(t, logs.String(), `host router \"api.example.com\" is unreachable: shadowed by host router \"{tenant}.example.com\"`)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		router, _ := prepareRouteTableTest(t, true)
+		router.Get("/users/{id}", nil)
+		assert.Panics(t, func() {
+			router.Get("/users/new", nil)
+		})
+	})
+}
@@ -236,6 +236,44 @@ func TestRoute(t *testing.T) {
 		assert.Equal(t, "http://127.0.0.1:8080/product/123/keyboard/accessories", uri)
 	})
 
+	t.Run("BuildURL_host", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		cfg.Set("server.domain", "example.com")
+		cfg.Set("server.proxy.host", "proxy.example.com")
+		cfg.Set("server.proxy.protocol", "https")
+		cfg.Set("server.proxy.port", 443)
+		cfg.Set("server.proxy.base", "/base")
+		server, err := New(Options{Config: cfg})
+		if err != nil {
+			panic(err)
+		}
+		router := NewRouter(server)
+		route := router.Host("{tenant}.example.com").Subrouter("/product").Get("/{id}", nil)
+
+		assert.Equal(t, "http://acme.example.com:8080/product/123", route.BuildURL("acme", "123"))
+		assert.Equal(t, "https://acme.example.com/base/product/123", route.BuildProxyURL("acme", "123"))
+		assert.Equal(t, "/product/123", route.BuildURI("123"))
+
+		assert.Panics(t, func() {
+			route.BuildURL()
+		})
+		assert.Panics(t, func() {
+			route.BuildURL("acme")
+		})
+
+		static := router.Host("admin.example.com").Get("/", nil)
+		assert.Equal(t, "http://admin.example.com:8080/", static.BuildURL())
+
+		relativeRouter := NewRouter(server)
+		relativeStatic := relativeRouter.Host("admin").Get("/", nil)
+		relative := relativeRouter.Host("{tenant}").Get("/product/{id}", nil)
+		assert.Equal(t, "admin.example.com", relativeStatic.GetParent().GetHost())
+		assert.Equal(t, "{tenant}.example.com", relative.GetParent().GetHost())
+		assert.Equal(t, "http://admin.example.com:8080/", relativeStatic.BuildURL())
+		assert.Equal(t, "http://acme.example.com:8080/product/123", relative.BuildURL("acme", "123"))
+		assert.Equal(t, "https://acme.example.com/base/product/123", relative.BuildProxyURL("acme", "123"))
+	})
+
 	t.Run("GetFullURI", func(t *testing.T) {
 		router := prepareRouteTest()
 		subrouter := router.Subrouter("/product").Subrouter("/{id:[0-9+]}")
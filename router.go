@@ -3,6 +3,7 @@ package goyave
 import (
 	"errors"
 	"io/fs"
+	"net"
 	"net/http"
 	"regexp"
 	"strings"
@@ -18,6 +19,9 @@ import (
 // Common route meta keys.
 const (
 	MetaCORS = "goyave.cors"
+
+	// MetaDeprecation marks a route as deprecated. The value is expected to be a `*Deprecation`.
+	MetaDeprecation = "goyave.deprecation"
 )
 
 // Special route names.
@@ -53,7 +57,11 @@ type routeMatch struct {
 	route       *Route
 	parameters  map[string]string
 	err         error
+	header      http.Header
+	versioning  *VersionOptions
 	currentPath string
+	host        string
+	version     int
 }
 
 func (rm *routeMatch) mergeParams(params map[string]string) {
@@ -80,6 +88,13 @@ type Router struct {
 	regexCache     map[string]*regexp.Regexp
 	Meta           map[string]any
 
+	// host matcher, only set for host routers
+	host        *parameterizable
+	hostPattern string
+
+	// versioning options, only set for versioned routers and their subrouters
+	versioning *VersionOptions
+
 	parameterizable
 	middlewareHolder
 	globalMiddleware *middlewareHolder
@@ -275,7 +290,7 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	match := routeMatch{currentPath: req.URL.Path}
+	match := routeMatch{currentPath: req.URL.Path, host: requestHost(req.Host), header: req.Header}
 	r.match(req.Method, &match)
 	r.requestHandler(&match, w, req)
 }
@@ -283,6 +298,16 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 // TODO export RouteMatch and add Match with string param function
 
 func (r *Router) match(method string, match *routeMatch) bool {
+	if r.host != nil {
+		hostParams := r.host.regex.FindStringSubmatch(match.host)
+		if hostParams == nil {
+			return false
+		}
+		if len(hostParams) > 1 {
+			match.mergeParams(r.host.makeParameters(hostParams, r.host.parameters))
+		}
+	}
+
 	// Check if router itself matches
 	var params []string
 	if r.parameterizable.regex != nil {
@@ -309,7 +334,7 @@ func (r *Router) match(method string, match *routeMatch) bool {
 		// Check in subrouters first
 		for _, router := range r.subrouters {
 			if router.match(method, match) {
-				if router.prefix == "" && match.route == methodNotAllowedRoute {
+				if router.prefix == "" && router.host == nil && match.route == methodNotAllowedRoute {
 					// This allows route groups with subrouters having empty prefix.
 					continue
 				}
@@ -318,10 +343,16 @@ func (r *Router) match(method string, match *routeMatch) bool {
 		}
 
 		// Check if any route matches
-		for _, route := range r.routes {
-			if route.match(method, match) {
+		if r.versioning != nil {
+			if r.matchVersion(method, match) {
 				return true
 			}
+		} else {
+			for _, route := range r.routes {
+				if route.match(method, match) {
+					return true
+				}
+			}
 		}
 	}
 
@@ -331,8 +362,17 @@ func (r *Router) match(method string, match *routeMatch) bool {
 	}
 
 	match.route = notFoundRoute
-	// Return true if the subrouter matched so we don't turn back and check other subrouters
-	return params != nil && len(params[0]) > 0
+	// Return true if the subrouter matched so we don't turn back and check other subrouters.
+	// Host routers always return true because their host matched.
+	return (params != nil && len(params[0]) > 0) || r.host != nil
+}
+
+// requestHost returns the lowercase host of a request, without port.
+func requestHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
 }
 
 func nthIndex(str, substr string, n int) int {
@@ -358,6 +398,8 @@ func (r *Router) makeParameters(match []string) map[string]string {
 //
 // Subrouters are matched before routes. For example, if you have a subrouter with a
 // prefix "/{name}" and a route "/route", the "/route" will never match.
+// Such conflicts are detected at registration time and reported as a warning,
+// or cause a panic if the "server.strictRouting" config entry is `true`.
 func (r *Router) Subrouter(prefix string) *Router {
 	if prefix == "/" {
 		prefix = ""
@@ -376,15 +418,85 @@ func (r *Router) Subrouter(prefix string) *Router {
 		},
 		globalMiddleware: r.globalMiddleware,
 		regexCache:       r.regexCache,
+		versioning:       r.versioning,
 	}
 	if prefix != "" {
 		router.compileParameters(router.prefix, false, r.regexCache)
 		router.slashCount = strings.Count(prefix, "/")
 	}
 	r.subrouters = append(r.subrouters, router)
+	r.checkSubrouterConflicts(router)
+	return router
+}
+
+// Host create a new sub-router only matching requests whose host matches the given pattern.
+// The pattern can contain parameters (e.g. "{tenant}.example.com"), which are added
+// to the request's `RouteParams`. By default, a host parameter matches a single label:
+// it doesn't match dots. The port of the request is ignored and the match is case-insensitive.
+//
+// Host routers have an empty prefix and inherit the middleware, meta and CORS options
+// of their parent just like other subrouters. If the host of a request matches,
+// the request is handled by this router only: if none of its routes match,
+// the response is "404 Not Found" even if a route of the parent router would match.
+//
+// A relative pattern, which doesn't contain any dot outside of its parameters (e.g. "{tenant}"
+// or "admin"), is resolved against the "server.domain" config entry: if the domain is
+// "example.com", the pattern "{tenant}" is equivalent to "{tenant}.example.com".
+// If "server.domain" is not set, relative patterns are used as-is.
+//
+// Host routers are matched in registration order. Register the most specific hosts first.
+// For example, "admin.example.com" should be registered before "{tenant}.example.com".
+func (r *Router) Host(pattern string) *Router {
+	pattern = r.resolveHostPattern(pattern)
+	router := r.Subrouter("")
+	router.hostPattern = pattern
+	router.host = &parameterizable{}
+	router.host.compileHostParameters(pattern, r.regexCache)
+	r.checkHostConflicts(router)
 	return router
 }
 
+// resolveHostPattern appends the "server.domain" config entry to the given host pattern
+// if it is relative.
+func (r *Router) resolveHostPattern(pattern string) string {
+	domain := r.server.config.GetString("server.domain")
+	if domain == "" {
+		return pattern
+	}
+	idxs, err := r.braceIndices(pattern)
+	if err != nil {
+		return pattern // The error is reported when compiling the pattern
+	}
+	end := 0
+	for i := 0; i < len(idxs); i += 2 {
+		if strings.Contains(pattern[end:idxs[i]], ".") {
+			return pattern
+		}
+		end = idxs[i+1]
+	}
+	if strings.Contains(pattern[end:], ".") {
+		return pattern
+	}
+	return pattern + "." + domain
+}
+
+// GetHost returns the host pattern of this router, resolved against the "server.domain"
+// config entry if it is relative. Returns an empty string if this router is not a host router.
+func (r *Router) GetHost() string {
+	return r.hostPattern
+}
+
+// hostRouter returns the closest host router in the hierarchy of this router,
+// starting with itself. Returns `nil` if there is no host router.
+func (r *Router) hostRouter() *Router {
+	for router := r; router != nil; router = router.parent {
+		if router.host != nil {
+			return router
+		}
+	}
+	return nil
+}
+
 // Group create a new sub-router with an empty prefix.
 func (r *Router) Group() *Router {
 	return r.Subrouter("")
@@ -471,6 +583,7 @@ func (r *Router) registerRoute(methods []string, uri string, handler Handler) *R
 	}
 	route.compileParameters(route.uri, true, r.regexCache)
 	r.routes = append(r.routes, route)
+	r.checkRouteConflicts(route)
 	return route
 }
 
@@ -489,6 +602,18 @@ func (r *Router) requestHandler(match *routeMatch, w http.ResponseWriter, rawReq
 	response := NewResponse(r.server, request, w)
 	handler := match.route.handler
 
+	if match.versioning != nil {
+		if match.version > 0 {
+			request.Extra[ExtraAPIVersion{}] = match.version
+		}
+		writeVersionHeaders(response.Header(), match.versioning)
+	}
+	if deprecation, ok := match.route.LookupMeta(MetaDeprecation); ok {
+		if d, ok := deprecation.(*Deprecation); ok && d != nil {
+			d.writeHeaders(response.Header())
+		}
+	}
+
 	// Route-specific middleware is executed after router middleware
 	handler = match.route.applyMiddleware(handler)
 
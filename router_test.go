@@ -227,6 +227,97 @@ func TestRouter(t *testing.T) {
 		assert.Equal(t, slash, group)
 	})
 
+	t.Run("Host", func(t *testing.T) {
+		router := prepareRouterTest()
+		router.CORS(cors.Default())
+		router.Middleware(&testMiddleware{key: "root"})
+		router.Get("/hello", func(response *Response, _ *Request) {
+			response.String(http.StatusOK, "root hello")
+		})
+
+		admin := router.Host("admin.example.com")
+		admin.Subrouter("/api").Post("/hello", func(response *Response, _ *Request) {
+			response.String(http.StatusOK, "admin hello")
+		})
+
+		tenant := router.Host("{tenant}.example.com")
+		assert.Equal(t, "{tenant}.example.com", tenant.GetHost())
+		assert.Empty(t, router.GetHost())
+		assert.Equal(t, "^([^.]+)\\.example\\.com$", tenant.host.regex.String())
+		tenant.Middleware(&testMiddleware{key: "tenant"})
+		tenant.Get("/users/{id}", func(response *Response, request *Request) {
+			response.String(http.StatusOK, fmt.Sprintf("%s %s %v", request.RouteParams["tenant"], request.RouteParams["id"], request.Extra[extraMiddlewareOrder{}]))
+		})
+
+		serve := func(method, host, path string) (int, string, http.Header) {
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(method, path, nil)
+			req.Host = host
+			router.ServeHTTP(recorder, req)
+			res := recorder.Result()
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, res.Body.Close())
+			require.NoError(t, err)
+			return res.StatusCode, string(body), res.Header
+		}
+
+		cases := []struct {
+			method         string
+			host           string
+			path           string
+			expectedBody   string
+			expectedStatus int
+		}{
+			{method: http.MethodGet, host: "acme.example.com", path: "/users/1", expectedStatus: http.StatusOK, expectedBody: "acme 1 [root tenant]"},
+			{method: http.MethodGet, host: "ACME.Example.com:8080", path: "/users/1", expectedStatus: http.StatusOK, expectedBody: "acme 1 [root tenant]"},
+			{method: http.MethodGet, host: "acme.example.com", path: "/hello", expectedStatus: http.StatusNotFound, expectedBody: "{\"error\":\"Not Found\"}\n"},
+			{method: http.MethodPost, host: "acme.example.com", path: "/users/1", expectedStatus: http.StatusMethodNotAllowed, expectedBody: "{\"error\":\"Method Not Allowed\"}\n"},
+			{method: http.MethodGet, host: "a.b.example.com", path: "/users/1", expectedStatus: http.StatusNotFound, expectedBody: "{\"error\":\"Not Found\"}\n"},
+			{method: http.MethodGet, host: "acmeXexample.com", path: "/hello", expectedStatus: http.StatusOK, expectedBody: "root hello"},
+			{method: http.MethodPost, host: "admin.example.com", path: "/api/hello", expectedStatus: http.StatusOK, expectedBody: "admin hello"},
+			{method: http.MethodGet, host: "admin.example.com", path: "/hello", expectedStatus: http.StatusNotFound, expectedBody: "{\"error\":\"Not Found\"}\n"},
+			{method: http.MethodGet, host: "example.com", path: "/hello", expectedStatus: http.StatusOK, expectedBody: "root hello"},
+		}
+
+		for _, c := range cases {
+			t.Run(fmt.Sprintf("%s_%s%s", c.method, c.host, c.path), func(t *testing.T) {
+				status, body, _ := serve(c.method, c.host, c.path)
+				assert.Equal(t, c.expectedStatus, status)
+				assert.Equal(t, c.expectedBody, body)
+			})
+		}
+
+		t.Run("CORS_inherited", func(t *testing.T) {
+			_, _, header := serve(http.MethodGet, "acme.example.com", "/users/1")
+			assert.Equal(t, "*", header.Get("Access-Control-Allow-Origin"))
+		})
+	})
+
+	t.Run("Host_relative", func(t *testing.T) {
+		router := prepareRouterTest()
+		assert.Equal(t, "{tenant}", router.Host("{tenant}").GetHost(), "no domain")
+
+		cfg := config.LoadDefault()
+		cfg.Set("server.domain", "example.com")
+		server, err := New(Options{Config: cfg})
+		require.NoError(t, err)
+		assert.Equal(t, "admin.example.com", NewRouter(server).Host("admin").GetHost())
+		assert.Equal(t, "{tenant}.example.com", NewRouter(server).Host("{tenant}").GetHost())
+		assert.Equal(t, "{tenant:[a-z.]+}.example.com", NewRouter(server).Host("{tenant:[a-z.]+}").GetHost())
+		assert.Equal(t, "{tenant}.example.org", NewRouter(server).Host("{tenant}.example.org").GetHost())
+
+		router = NewRouter(server)
+		router.Host("{shop}").Get("/", func(response *Response, request *Request) {
+			response.String(http.StatusOK, request.RouteParams["shop"])
+		})
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = "acme.example.com"
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "acme", recorder.Body.String())
+	})
+
 	t.Run("Route", func(t *testing.T) {
 		router := prepareRouterTest()
 
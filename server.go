@@ -9,13 +9,16 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"syscall"
 	"time"
 
 	stderrors "errors"
 
+	"github.com/samber/lo"
 	"gorm.io/gorm"
 	"goyave.dev/goyave/v5/config"
 	"goyave.dev/goyave/v5/database"
@@ -24,6 +27,7 @@ import (
 	"goyave.dev/goyave/v5/util/errors"
 	"goyave.dev/goyave/v5/util/fsutil"
 	"goyave.dev/goyave/v5/util/fsutil/osfs"
+	"goyave.dev/goyave/v5/view"
 )
 
 // serverKey is a context key used to store the server instance into its base context.
@@ -47,6 +51,12 @@ type Options struct {
 	// If not provided, uses `osfs.FS` as a default.
 	LangFS fsutil.FS
 
+	// ViewsFS the file system from which the view templates
+	// will be loaded. This file system is expected to contain
+	// a `resources/views` directory if it implements `fsutil.WorkingDirFS`.
+	// If not provided, no views are loaded. See `Response.View()`.
+	ViewsFS fsutil.FS
+
 	// ConnState specifies an optional callback function that is
 	// called when a client connection changes state. See the
 	// `http.ConnState` type and associated constants for details.
@@ -90,6 +100,7 @@ type Server struct {
 
 	router *Router
 	db     *gorm.DB
+	views  *view.Views
 
 	services map[string]Service
 
@@ -183,6 +194,12 @@ func New(opts Options) (*Server, error) {
 
 	server.router = NewRouter(server)
 	server.server.Handler = server.router
+
+	if opts.ViewsFS != nil {
+		if err := server.LoadViews(opts.ViewsFS); err != nil {
+			return nil, err
+		}
+	}
 	return server, nil
 }
 
@@ -250,6 +267,15 @@ func (s *Server) LookupService(name string) (Service, bool) {
 	return service, ok
 }
 
+// Services returns all the services registered on this server, sorted by name.
+func (s *Server) Services() []Service {
+	services := lo.Values(s.services)
+	slices.SortFunc(services, func(a, b Service) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return services
+}
+
 // RegisterService on thise server using its name (returned by `Service.Name()`).
 // A service's name should be unique.
 // `Service.Init(server)` is called on the given service upon registration.
@@ -214,6 +214,8 @@ func TestServer(t *testing.T) {
 		assert.Equal(t, map[string]Service{"dummy": service}, server.services)
 		assert.Equal(t, service, server.Service("dummy"))
 
+		assert.Equal(t, []Service{service}, server.Services())
+
 		s, ok := server.LookupService("dummy")
 		assert.Equal(t, service, s)
 		assert.True(t, ok)
@@ -0,0 +1,37 @@
+package session
+
+import (
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/middleware/csrf"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// CSRFTokenKey the session key under which `CSRFStore` keeps the CSRF token.
+const CSRFTokenKey = "_csrf_token"
+
+// CSRFStore a `csrf.Store` keeping the CSRF token in the session, implementing the
+// synchronizer token strategy. The session middleware must be executed before the
+// CSRF middleware.
+type CSRFStore struct{}
+
+var _ csrf.Store = CSRFStore{}
+
+// Get returns the CSRF token stored in the session of the request.
+func (CSRFStore) Get(request *goyave.Request) string {
+	s := FromRequest(request)
+	if s == nil {
+		return ""
+	}
+	token, _ := Value[string](s, CSRFTokenKey)
+	return token
+}
+
+// Save stores the CSRF token in the session of the request.
+// Panics if the request has no session.
+func (CSRFStore) Save(_ *goyave.Response, request *goyave.Request, token string) {
+	s := FromRequest(request)
+	if s == nil {
+		panic(errors.New("CSRFStore: the request has no session, the session middleware must be executed before the CSRF middleware"))
+	}
+	s.Set(CSRFTokenKey, token)
+}
@@ -0,0 +1,27 @@
+package session
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func TestCSRFStore(t *testing.T) {
+	store := CSRFStore{}
+	request := testutil.NewTestRequest(http.MethodGet, "/", nil)
+	assert.Empty(t, store.Get(request))
+	assert.Panics(t, func() {
+		store.Save(nil, request, "token")
+	})
+
+	s := newSession("id", time.Now())
+	request.Extra[ExtraSession{}] = s
+	assert.Empty(t, store.Get(request))
+	store.Save(nil, request, "token")
+	assert.Equal(t, "token", store.Get(request))
+	v, _ := s.Get(CSRFTokenKey)
+	assert.Equal(t, "token", v)
+}
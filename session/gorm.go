@@ -0,0 +1,63 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	errorutil "goyave.dev/goyave/v5/util/errors"
+)
+
+// Model the database model used by `GORMStore`. Use it with `AutoMigrate`
+// or create the "sessions" table using your migration tool.
+type Model struct {
+	ExpiresAt time.Time `gorm:"index;not null"`
+	ID        string    `gorm:"primaryKey;size:64"`
+	Data      []byte    `gorm:"not null"`
+}
+
+// TableName returns "sessions".
+func (Model) TableName() string {
+	return "sessions"
+}
+
+// GORMStore a `Store` keeping the sessions in a database table using GORM. See `Model`.
+type GORMStore struct {
+	DB *gorm.DB
+}
+
+// NewGORMStore create a new `GORMStore` using the given database.
+func NewGORMStore(db *gorm.DB) *GORMStore {
+	return &GORMStore{DB: db}
+}
+
+// Load returns the data of the session identified by the given ID.
+func (s *GORMStore) Load(ctx context.Context, id string) ([]byte, error) {
+	model := &Model{}
+	err := s.DB.WithContext(ctx).Where("id = ? AND expires_at > ?", id, time.Now()).Take(model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errorutil.New(err)
+	}
+	return model.Data, nil
+}
+
+// Save the data of the session identified by the given ID.
+func (s *GORMStore) Save(ctx context.Context, id string, data []byte, expiresAt time.Time) error {
+	model := &Model{ID: id, Data: data, ExpiresAt: expiresAt}
+	return errorutil.New(s.DB.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(model).Error)
+}
+
+// Delete the session identified by the given ID.
+func (s *GORMStore) Delete(ctx context.Context, id string) error {
+	return errorutil.New(s.DB.WithContext(ctx).Where("id = ?", id).Delete(&Model{}).Error)
+}
+
+// Sweep deletes all the sessions expired at the given time.
+func (s *GORMStore) Sweep(ctx context.Context, now time.Time) error {
+	return errorutil.New(s.DB.WithContext(ctx).Where("expires_at <= ?", now).Delete(&Model{}).Error)
+}
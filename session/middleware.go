@@ -0,0 +1,296 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+func init() {
+	register := func(key string, value any, kind reflect.Kind, authorizedValues ...any) {
+		config.Register("session."+key, config.Entry{
+			Value:            value,
+			Type:             kind,
+			IsSlice:          false,
+			AuthorizedValues: append([]any{}, authorizedValues...),
+		})
+	}
+	register("cookie.name", "goyave_session", reflect.String)
+	register("cookie.path", "/", reflect.String)
+	register("cookie.domain", "", reflect.String)
+	register("cookie.secure", false, reflect.Bool)
+	register("cookie.sameSite", "Lax", reflect.String, "Lax", "Strict", "None")
+	register("idleTimeout", 7200, reflect.Int)
+	register("absoluteTimeout", 86400, reflect.Int)
+	register("encryptID", false, reflect.Bool)
+	register("sweepInterval", 600, reflect.Int)
+}
+
+// cookiePayload the content of the session cookie when using `CookieStore`.
+type cookiePayload struct {
+	sessionData
+	ID string `json:"id"`
+}
+
+// Middleware loading the session of the client from the session cookie and the store,
+// and putting it in the request's extra with the key `ExtraSession`. If the client doesn't
+// have a valid session, a new one is created. The session is saved and the session cookie is
+// set right before the response header is written (see `goyave.Response.BeforeWriteHeader()`),
+// even if the handler writes the header without body (e.g. with `http.Redirect()`).
+// If the session cannot be loaded from the store, the request fails with "500 Internal Server Error".
+//
+// The session ID stored in the cookie is signed (or encrypted if the "session.encryptID" config
+// entry is `true`) using the server's `goyave.CookieCodec`. Cookies encoded with one of the
+// "app.previousKeys" are accepted and encoded again with the current key. A session expires
+// if it hasn't been used for "session.idleTimeout" seconds, or "session.absoluteTimeout" seconds
+// after its creation. A zero timeout disables the corresponding expiry.
+//
+// When the server starts, a background sweeper deleting the expired sessions from the store
+// is started every "session.sweepInterval" seconds (if not zero). It is stopped when
+// the server shuts down.
+//
+// Panics on initialization if the application key is not set.
+type Middleware struct {
+	goyave.Component
+
+	// Store the session storage. Defaults to a new `MemoryStore`.
+	Store Store
+
+	codec   *goyave.CookieCodec
+	sweeper sync.Once
+}
+
+// Init the middleware and register the sweeper lifecycle hooks.
+func (m *Middleware) Init(server *goyave.Server) {
+	m.Component.Init(server)
+	key := server.Config().GetString("app.key")
+	if key == "" {
+		panic(errors.New("session middleware: the \"app.key\" config entry is not set"))
+	}
+	m.codec = server.CookieCodec()
+	if m.Store == nil {
+		m.Store = NewMemoryStore()
+	}
+	m.sweeper.Do(func() {
+		m.registerSweeper(server)
+	})
+}
+
+// Handle loads the session and saves it once the request has been handled.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		sess, err := m.load(request)
+		if err != nil {
+			response.Error(err)
+			return
+		}
+		request.Extra[ExtraSession{}] = sess
+
+		once := sync.Once{}
+		commit := func() {
+			once.Do(func() {
+				if err := m.commit(response, request, sess); err != nil {
+					m.Logger().Error(err)
+				}
+			})
+		}
+		response.BeforeWriteHeader(commit)
+		next(response, request)
+		if !response.IsHeaderWritten() {
+			commit()
+		}
+	}
+}
+
+func (m *Middleware) isCookieStore() bool {
+	switch m.Store.(type) {
+	case CookieStore, *CookieStore:
+		return true
+	}
+	return false
+}
+
+func (m *Middleware) load(request *goyave.Request) (*Session, error) {
+	now := time.Now()
+	cookie, err := request.Request().Cookie(m.Config().GetString("session.cookie.name"))
+	if err != nil {
+		return newSession(generateID(), now), nil
+	}
+
+	var id string
+	rotated := false
+	data := &sessionData{}
+	if m.isCookieStore() {
+		payload := &cookiePayload{}
+		plaintext, _, err := m.codec.Decrypt(cookie.Name, cookie.Value)
+		if err != nil || json.Unmarshal([]byte(plaintext), payload) != nil {
+			return newSession(generateID(), now), nil
+		}
+		id = payload.ID
+		data = &payload.sessionData
+	} else {
+		var err error
+		if id, rotated, err = m.decodeID(cookie.Name, cookie.Value); err != nil {
+			return newSession(generateID(), now), nil
+		}
+		raw, err := m.Store.Load(request.Context(), id)
+		if err != nil {
+			return nil, errors.New(err)
+		}
+		if raw == nil || json.Unmarshal(raw, data) != nil {
+			return newSession(generateID(), now), nil
+		}
+	}
+
+	if !m.expiresAt(data).After(now) {
+		if err := m.Store.Delete(request.Context(), id); err != nil {
+			return nil, errors.New(err)
+		}
+		return newSession(generateID(), now), nil
+	}
+	sess := sessionFromData(id, data)
+	sess.rotated = rotated
+	return sess, nil
+}
+
+func (m *Middleware) commit(response *goyave.Response, request *goyave.Request, sess *Session) error {
+	cfg := m.Config()
+	ctx := request.Context()
+	cookie := &http.Cookie{
+		Name:     cfg.GetString("session.cookie.name"),
+		Path:     cfg.GetString("session.cookie.path"),
+		Domain:   cfg.GetString("session.cookie.domain"),
+		Secure:   cfg.GetBool("session.cookie.secure"),
+		HttpOnly: true,
+		SameSite: sameSite(cfg.GetString("session.cookie.sameSite")),
+	}
+
+	sess.mu.RLock()
+	id, previousID, destroyed, isNew, rotated := sess.id, sess.previousID, sess.destroyed, sess.isNew, sess.rotated
+	sess.mu.RUnlock()
+
+	if previousID != "" {
+		if err := m.Store.Delete(ctx, previousID); err != nil {
+			return errors.New(err)
+		}
+	}
+	if destroyed {
+		if err := m.Store.Delete(ctx, id); err != nil {
+			return errors.New(err)
+		}
+		if !isNew || previousID != "" {
+			cookie.MaxAge = -1
+			response.Cookie(cookie)
+		}
+		return nil
+	}
+
+	data := sess.data()
+	data.LastActivity = time.Now()
+	if m.isCookieStore() {
+		plaintext, err := json.Marshal(&cookiePayload{ID: id, sessionData: *data})
+		if err != nil {
+			return errors.New(err)
+		}
+		cookie.Value = string(plaintext)
+		return errors.New(m.codec.SetEncrypted(response, cookie))
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return errors.New(err)
+	}
+	if err := m.Store.Save(ctx, id, raw, m.expiresAt(data)); err != nil {
+		return errors.New(err)
+	}
+	if isNew || previousID != "" || rotated {
+		cookie.Value = id
+		if m.Config().GetBool("session.encryptID") {
+			return errors.New(m.codec.SetEncrypted(response, cookie))
+		}
+		return errors.New(m.codec.SetSigned(response, cookie))
+	}
+	return nil
+}
+
+// expiresAt returns the time at which the session expires. Sessions without
+// idle nor absolute timeout expire after one year.
+func (m *Middleware) expiresAt(data *sessionData) time.Time {
+	cfg := m.Config()
+	idle := time.Duration(cfg.GetInt("session.idleTimeout")) * time.Second
+	absolute := time.Duration(cfg.GetInt("session.absoluteTimeout")) * time.Second
+	switch {
+	case idle <= 0 && absolute <= 0:
+		return data.LastActivity.AddDate(1, 0, 0)
+	case idle <= 0:
+		return data.CreatedAt.Add(absolute)
+	case absolute <= 0:
+		return data.LastActivity.Add(idle)
+	}
+	expiresAt := data.LastActivity.Add(idle)
+	if abs := data.CreatedAt.Add(absolute); abs.Before(expiresAt) {
+		return abs
+	}
+	return expiresAt
+}
+
+func (m *Middleware) decodeID(name, value string) (string, bool, error) {
+	if m.Config().GetBool("session.encryptID") {
+		return m.codec.Decrypt(name, value)
+	}
+	return m.codec.Verify(name, value)
+}
+
+func (m *Middleware) registerSweeper(server *goyave.Server) {
+	interval := time.Duration(server.Config().GetInt("session.sweepInterval")) * time.Second
+	if interval <= 0 || m.isCookieStore() {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	server.RegisterStartupHook(func(s *goyave.Server) {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case now := <-ticker.C:
+					if err := m.Store.Sweep(ctx, now); err != nil && ctx.Err() == nil {
+						s.Logger.Error(errors.New(err))
+					}
+				}
+			}
+		}()
+	})
+	server.RegisterShutdownHook(func(_ *goyave.Server) {
+		cancel()
+	})
+}
+
+func sameSite(value string) http.SameSite {
+	switch value {
+	case "Strict":
+		return http.SameSiteStrictMode
+	case "None":
+		return http.SameSiteNoneMode
+	}
+	return http.SameSiteLaxMode
+}
+
+func generateID() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(errors.New(err))
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
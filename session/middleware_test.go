@@ -0,0 +1,296 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func prepareMiddlewareTest(t *testing.T, store Store, configure func(cfg *config.Config)) (*goyave.Router, *Middleware) {
+	cfg := config.LoadDefault()
+	cfg.Set("app.key", "test-key")
+	if configure != nil {
+		configure(cfg)
+	}
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+	router := goyave.NewRouter(server.Server)
+	middleware := &Middleware{Store: store}
+	router.GlobalMiddleware(middleware)
+
+	router.Get("/get", func(response *goyave.Response, request *goyave.Request) {
+		s := FromRequest(request)
+		value, _ := s.Get("value")
+		flash, _ := s.GetFlash("flash")
+		response.String(http.StatusOK, fmt.Sprintf("%v %v", value, flash))
+	})
+	router.Post("/set", func(_ *goyave.Response, request *goyave.Request) {
+		s := FromRequest(request)
+		s.Set("value", request.URL().Query().Get("value"))
+		s.Flash("flash", "flashed")
+	})
+	router.Post("/login", func(response *goyave.Response, request *goyave.Request) {
+		FromRequest(request).Regenerate()
+		response.String(http.StatusOK, "logged in")
+	})
+	router.Post("/logout", func(_ *goyave.Response, request *goyave.Request) {
+		FromRequest(request).Destroy()
+	})
+	router.Post("/redirect", func(response *goyave.Response, request *goyave.Request) {
+		FromRequest(request).Set("value", "redirected")
+		http.Redirect(response, request.Request(), "/get", http.StatusSeeOther)
+	})
+	return router, middleware
+}
+
+type failingStore struct {
+	*MemoryStore
+}
+
+func (s failingStore) Load(_ context.Context, _ string) ([]byte, error) {
+	return nil, fmt.Errorf("store error")
+}
+
+type sessionClient struct {
+	t      *testing.T
+	router *goyave.Router
+	cookie *http.Cookie
+}
+
+func (c *sessionClient) do(method, path string) (*http.Response, string) {
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	if c.cookie != nil {
+		req.AddCookie(c.cookie)
+	}
+	c.router.ServeHTTP(recorder, req)
+	res := recorder.Result()
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(c.t, res.Body.Close())
+	require.NoError(c.t, err)
+	for _, cookie := range res.Cookies() {
+		if cookie.Name == "goyave_session" {
+			if cookie.MaxAge < 0 {
+				c.cookie = nil
+			} else {
+				c.cookie = cookie
+			}
+		}
+	}
+	return res, string(body)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("no_key", func(t *testing.T) {
+		server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: config.LoadDefault()})
+		assert.Panics(t, func() {
+			(&Middleware{}).Init(server.Server)
+		})
+	})
+
+	t.Run("default_store", func(t *testing.T) {
+		_, middleware := prepareMiddlewareTest(t, nil, nil)
+		assert.IsType(t, &MemoryStore{}, middleware.Store)
+	})
+
+	for _, encryptID := range []bool{false, true} {
+		t.Run(fmt.Sprintf("lifecycle_encryptID_%t", encryptID), func(t *testing.T) {
+			store := NewMemoryStore()
+			router, _ := prepareMiddlewareTest(t, store, func(cfg *config.Config) {
+				cfg.Set("session.encryptID", encryptID)
+			})
+			client := &sessionClient{t: t, router: router}
+
+			res, body := client.do(http.MethodGet, "/get")
+			assert.Equal(t, "<nil> <nil>", body)
+			require.NotNil(t, client.cookie)
+			assert.True(t, client.cookie.HttpOnly)
+			assert.Equal(t, "/", client.cookie.Path)
+			assert.Equal(t, http.SameSiteLaxMode, client.cookie.SameSite)
+			assert.Len(t, res.Cookies(), 1)
+			assert.Equal(t, 1, store.Len())
+			firstCookie := client.cookie.Value
+			if !encryptID {
+				assert.Contains(t, firstCookie, ".")
+			}
+
+			res, _ = client.do(http.MethodPost, "/set?value=hello")
+			assert.Empty(t, res.Cookies(), "cookie not set again for existing session")
+
+			_, body = client.do(http.MethodGet, "/get")
+			assert.Equal(t, "hello flashed", body)
+			_, body = client.do(http.MethodGet, "/get")
+			assert.Equal(t, "hello <nil>", body, "flash data only available for one request")
+
+			res, body = client.do(http.MethodPost, "/login")
+			assert.Equal(t, "logged in", body)
+			require.Len(t, res.Cookies(), 1)
+			assert.NotEqual(t, firstCookie, client.cookie.Value)
+			assert.Equal(t, 1, store.Len(), "previous ID deleted")
+			_, body = client.do(http.MethodGet, "/get")
+			assert.Equal(t, "hello <nil>", body, "data kept after regeneration")
+
+			oldClient := &sessionClient{t: t, router: router, cookie: &http.Cookie{Name: "goyave_session", Value: firstCookie}}
+			_, body = oldClient.do(http.MethodGet, "/get")
+			assert.Equal(t, "<nil> <nil>", body, "previous ID invalidated")
+
+			res, _ = client.do(http.MethodPost, "/logout")
+			require.Len(t, res.Cookies(), 1)
+			assert.Equal(t, -1, res.Cookies()[0].MaxAge)
+			assert.Nil(t, client.cookie)
+			assert.Equal(t, 1, store.Len(), "only the session of the old client remains")
+		})
+	}
+
+	t.Run("tampered_cookie", func(t *testing.T) {
+		store := NewMemoryStore()
+		router, _ := prepareMiddlewareTest(t, store, nil)
+		client := &sessionClient{t: t, router: router}
+		client.do(http.MethodPost, "/set?value=hello")
+		id, _, _ := strings.Cut(client.cookie.Value, ".")
+
+		client.cookie = &http.Cookie{Name: "goyave_session", Value: id + ".invalid"}
+		_, body := client.do(http.MethodGet, "/get")
+		assert.Equal(t, "<nil> <nil>", body)
+		assert.NotContains(t, client.cookie.Value, id)
+
+		client.cookie = &http.Cookie{Name: "goyave_session", Value: id}
+		_, body = client.do(http.MethodGet, "/get")
+		assert.Equal(t, "<nil> <nil>", body)
+	})
+
+	t.Run("key_rotation", func(t *testing.T) {
+		store := NewMemoryStore()
+		router, middleware := prepareMiddlewareTest(t, store, func(cfg *config.Config) {
+			cfg.Set("app.key", "new-key")
+			cfg.Set("app.previousKeys", []string{"test-key"})
+		})
+		raw, err := json.Marshal(&sessionData{CreatedAt: time.Now(), LastActivity: time.Now(), Values: map[string]any{"value": "old"}})
+		require.NoError(t, err)
+		require.NoError(t, store.Save(context.Background(), "rotated", raw, time.Now().Add(time.Hour)))
+		oldCookie := goyave.NewCookieCodec("test-key").Sign("goyave_session", "rotated")
+		client := &sessionClient{t: t, router: router, cookie: &http.Cookie{Name: "goyave_session", Value: oldCookie}}
+
+		res, body := client.do(http.MethodGet, "/get")
+		assert.Equal(t, "old <nil>", body)
+		require.Len(t, res.Cookies(), 1)
+		assert.Equal(t, middleware.codec.Sign("goyave_session", "rotated"), client.cookie.Value)
+
+		res, _ = client.do(http.MethodGet, "/get")
+		assert.Empty(t, res.Cookies())
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		store := NewMemoryStore()
+		router, middleware := prepareMiddlewareTest(t, store, func(cfg *config.Config) {
+			cfg.Set("session.idleTimeout", 60)
+			cfg.Set("session.absoluteTimeout", 3600)
+		})
+
+		save := func(id string, createdAt, lastActivity time.Time) *http.Cookie {
+			raw, err := json.Marshal(&sessionData{CreatedAt: createdAt, LastActivity: lastActivity, Values: map[string]any{"value": "old"}})
+			require.NoError(t, err)
+			require.NoError(t, store.Save(context.Background(), id, raw, time.Now().Add(time.Hour)))
+			return &http.Cookie{Name: "goyave_session", Value: middleware.codec.Sign("goyave_session", id)}
+		}
+
+		now := time.Now()
+		client := &sessionClient{t: t, router: router, cookie: save("valid", now.Add(-time.Minute), now.Add(-30*time.Second))}
+		_, body := client.do(http.MethodGet, "/get")
+		assert.Equal(t, "old <nil>", body)
+
+		client = &sessionClient{t: t, router: router, cookie: save("idle", now.Add(-time.Minute*5), now.Add(-2*time.Minute))}
+		_, body = client.do(http.MethodGet, "/get")
+		assert.Equal(t, "<nil> <nil>", body)
+
+		client = &sessionClient{t: t, router: router, cookie: save("absolute", now.Add(-2*time.Hour), now.Add(-time.Second))}
+		_, body = client.do(http.MethodGet, "/get")
+		assert.Equal(t, "<nil> <nil>", body)
+
+		data, err := store.Load(context.Background(), "idle")
+		require.NoError(t, err)
+		assert.Nil(t, data, "expired session deleted")
+
+		assert.Equal(t, now.Add(time.Minute), middleware.expiresAt(&sessionData{CreatedAt: now, LastActivity: now}))
+		assert.Equal(t, now.Add(time.Hour), middleware.expiresAt(&sessionData{CreatedAt: now, LastActivity: now.Add(time.Hour)}))
+	})
+
+	t.Run("cookie_store", func(t *testing.T) {
+		router, _ := prepareMiddlewareTest(t, CookieStore{}, nil)
+		client := &sessionClient{t: t, router: router}
+
+		client.do(http.MethodPost, "/set?value=hello")
+		require.NotNil(t, client.cookie)
+		assert.NotContains(t, client.cookie.Value, "hello")
+
+		_, body := client.do(http.MethodGet, "/get")
+		assert.Equal(t, "hello flashed", body)
+		_, body = client.do(http.MethodGet, "/get")
+		assert.Equal(t, "hello <nil>", body)
+
+		client.cookie.Value = "invalid"
+		_, body = client.do(http.MethodGet, "/get")
+		assert.Equal(t, "<nil> <nil>", body)
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		router, _ := prepareMiddlewareTest(t, NewMemoryStore(), nil)
+		client := &sessionClient{t: t, router: router}
+
+		res, _ := client.do(http.MethodPost, "/redirect")
+		assert.Equal(t, http.StatusSeeOther, res.StatusCode)
+		require.NotNil(t, client.cookie)
+
+		_, body := client.do(http.MethodGet, "/get")
+		assert.Equal(t, "redirected <nil>", body)
+	})
+
+	t.Run("store_error", func(t *testing.T) {
+		router, _ := prepareMiddlewareTest(t, failingStore{MemoryStore: NewMemoryStore()}, func(cfg *config.Config) {
+			cfg.Set("app.debug", false)
+		})
+		client := &sessionClient{t: t, router: router}
+		client.do(http.MethodPost, "/set?value=hello")
+		require.NotNil(t, client.cookie)
+
+		res, _ := client.do(http.MethodGet, "/get")
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+
+	t.Run("sweeper", func(t *testing.T) {
+		store := NewMemoryStore()
+		require.NoError(t, store.Save(context.Background(), "expired", []byte("{}"), time.Now().Add(-time.Second)))
+		cfg := config.LoadDefault()
+		cfg.Set("app.key", "test-key")
+		cfg.Set("server.port", 0)
+		cfg.Set("session.sweepInterval", 1)
+		server, err := goyave.New(goyave.Options{Config: cfg})
+		require.NoError(t, err)
+		server.Router().GlobalMiddleware(&Middleware{Store: store})
+
+		wg := sync.WaitGroup{}
+		wg.Add(2)
+		server.RegisterStartupHook(func(s *goyave.Server) {
+			assert.Eventually(t, func() bool { return store.Len() == 0 }, 5*time.Second, 100*time.Millisecond)
+			s.Stop()
+			wg.Done()
+		})
+		go func() {
+			assert.NoError(t, server.Start())
+			wg.Done()
+		}()
+		wg.Wait()
+	})
+}
@@ -0,0 +1,204 @@
+package session
+
+import (
+	"maps"
+	"sync"
+	"time"
+
+	"goyave.dev/goyave/v5"
+)
+
+// ExtraSession the key used in `Context.Extra` to store the `*Session`
+// of the request. Use `FromRequest()` to retrieve it.
+type ExtraSession struct{}
+
+// Session the server-side data associated with a client across multiple requests.
+// Values must be JSON-serializable: they are serialized to JSON by the session middleware
+// at the end of each request, so their type may change once loaded again (for example
+// numbers are loaded as `float64`).
+//
+// A session is safe for concurrent use.
+type Session struct {
+	createdAt    time.Time
+	lastActivity time.Time
+	values       map[string]any
+	flash        map[string]any
+	oldFlash     map[string]any
+	id           string
+	previousID   string
+	mu           sync.RWMutex
+	isNew        bool
+	destroyed    bool
+	rotated      bool
+}
+
+// sessionData the serialized representation of a session.
+type sessionData struct {
+	CreatedAt    time.Time      `json:"createdAt"`
+	LastActivity time.Time      `json:"lastActivity"`
+	Values       map[string]any `json:"values,omitempty"`
+	Flash        map[string]any `json:"flash,omitempty"`
+}
+
+func newSession(id string, now time.Time) *Session {
+	return &Session{
+		id:           id,
+		createdAt:    now,
+		lastActivity: now,
+		values:       map[string]any{},
+		flash:        map[string]any{},
+		oldFlash:     map[string]any{},
+		isNew:        true,
+	}
+}
+
+func sessionFromData(id string, data *sessionData) *Session {
+	s := &Session{
+		id:           id,
+		createdAt:    data.CreatedAt,
+		lastActivity: data.LastActivity,
+		values:       data.Values,
+		flash:        map[string]any{},
+		oldFlash:     data.Flash,
+	}
+	if s.values == nil {
+		s.values = map[string]any{}
+	}
+	if s.oldFlash == nil {
+		s.oldFlash = map[string]any{}
+	}
+	return s
+}
+
+func (s *Session) data() *sessionData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &sessionData{
+		CreatedAt:    s.createdAt,
+		LastActivity: s.lastActivity,
+		Values:       maps.Clone(s.values),
+		Flash:        maps.Clone(s.flash),
+	}
+}
+
+// ID returns the identifier of the session.
+func (s *Session) ID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.id
+}
+
+// IsNew returns true if the session was created during the current request.
+func (s *Session) IsNew() bool {
+	return s.isNew
+}
+
+// CreatedAt returns the time at which the session was created.
+func (s *Session) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+// LastActivity returns the time of the previous request using this session.
+func (s *Session) LastActivity() time.Time {
+	return s.lastActivity
+}
+
+// Get returns the value identified by the given key and `true` if it exists.
+func (s *Session) Get(key string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Set the value identified by the given key.
+func (s *Session) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+// Delete the value identified by the given key.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// Clear removes all the values and flash data of the session.
+func (s *Session) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = map[string]any{}
+	s.flash = map[string]any{}
+	s.oldFlash = map[string]any{}
+}
+
+// Flash set a value that will only be available during the next request
+// using this session (for example a success message displayed after a redirect).
+func (s *Session) Flash(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flash[key] = value
+}
+
+// GetFlash returns the flash value identified by the given key set during the previous
+// request and `true` if it exists.
+func (s *Session) GetFlash(key string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.oldFlash[key]
+	return v, ok
+}
+
+// Reflash keeps the flash data set during the previous request for one more request.
+func (s *Session) Reflash() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range s.oldFlash {
+		if _, ok := s.flash[k]; !ok {
+			s.flash[k] = v
+		}
+	}
+}
+
+// Regenerate replaces the session ID with a new one while keeping the session data.
+// The previous ID is invalidated. This should be called when the privilege level of the
+// client changes (for example on login) to prevent session fixation attacks.
+func (s *Session) Regenerate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.previousID == "" && !s.isNew {
+		s.previousID = s.id
+	}
+	s.id = generateID()
+}
+
+// Destroy removes the session from the store and expires the session cookie.
+// A new session is created on the next request.
+func (s *Session) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.destroyed = true
+	s.values = map[string]any{}
+	s.flash = map[string]any{}
+}
+
+// FromRequest returns the session of the given request, or `nil` if
+// the session middleware was not executed.
+func FromRequest(request *goyave.Request) *Session {
+	s, _ := request.Extra[ExtraSession{}].(*Session)
+	return s
+}
+
+// Value returns the session value identified by the given key converted to the type `T`,
+// and `true` if it exists and has the expected type.
+func Value[T any](s *Session, key string) (T, bool) {
+	v, ok := s.Get(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	t, ok := v.(T)
+	return t, ok
+}
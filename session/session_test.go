@@ -0,0 +1,91 @@
+package session
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+func TestSession(t *testing.T) {
+	t.Run("values", func(t *testing.T) {
+		now := time.Now()
+		s := newSession("id", now)
+		assert.Equal(t, "id", s.ID())
+		assert.True(t, s.IsNew())
+		assert.Equal(t, now, s.CreatedAt())
+		assert.Equal(t, now, s.LastActivity())
+
+		_, ok := s.Get("key")
+		assert.False(t, ok)
+		s.Set("key", "value")
+		v, ok := s.Get("key")
+		assert.True(t, ok)
+		assert.Equal(t, "value", v)
+
+		str, ok := Value[string](s, "key")
+		assert.True(t, ok)
+		assert.Equal(t, "value", str)
+		_, ok = Value[int](s, "key")
+		assert.False(t, ok)
+		_, ok = Value[int](s, "missing")
+		assert.False(t, ok)
+
+		s.Delete("key")
+		_, ok = s.Get("key")
+		assert.False(t, ok)
+
+		s.Set("a", 1)
+		s.Flash("b", 2)
+		s.Clear()
+		assert.Empty(t, s.values)
+		assert.Empty(t, s.flash)
+	})
+
+	t.Run("flash", func(t *testing.T) {
+		s := newSession("id", time.Now())
+		s.Flash("message", "saved")
+		_, ok := s.GetFlash("message")
+		assert.False(t, ok)
+
+		next := sessionFromData("id", s.data())
+		assert.False(t, next.IsNew())
+		v, ok := next.GetFlash("message")
+		assert.True(t, ok)
+		assert.Equal(t, "saved", v)
+		assert.Empty(t, next.data().Flash)
+
+		next.Reflash()
+		assert.Equal(t, map[string]any{"message": "saved"}, next.data().Flash)
+	})
+
+	t.Run("Regenerate", func(t *testing.T) {
+		s := sessionFromData("id", &sessionData{})
+		s.Regenerate()
+		assert.NotEqual(t, "id", s.ID())
+		assert.Equal(t, "id", s.previousID)
+		s.Regenerate()
+		assert.Equal(t, "id", s.previousID)
+
+		s = newSession("new", time.Now())
+		s.Regenerate()
+		assert.Empty(t, s.previousID)
+	})
+
+	t.Run("Destroy", func(t *testing.T) {
+		s := sessionFromData("id", &sessionData{Values: map[string]any{"a": 1}})
+		s.Destroy()
+		assert.True(t, s.destroyed)
+		assert.Empty(t, s.values)
+	})
+
+	t.Run("FromRequest", func(t *testing.T) {
+		request := testutil.NewTestRequest(http.MethodGet, "/", nil)
+		assert.Nil(t, FromRequest(request))
+		s := newSession("id", time.Now())
+		request.Extra[ExtraSession{}] = s
+		assert.Equal(t, s, FromRequest(request))
+	})
+}
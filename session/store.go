@@ -0,0 +1,110 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store persists the serialized session data.
+type Store interface {
+	// Load returns the data of the session identified by the given ID, or `nil` if
+	// the session doesn't exist or is expired.
+	Load(ctx context.Context, id string) ([]byte, error)
+
+	// Save the data of the session identified by the given ID. The session expires at the given time.
+	Save(ctx context.Context, id string, data []byte, expiresAt time.Time) error
+
+	// Delete the session identified by the given ID.
+	Delete(ctx context.Context, id string) error
+
+	// Sweep deletes all the sessions expired at the given time.
+	Sweep(ctx context.Context, now time.Time) error
+}
+
+type memoryEntry struct {
+	expiresAt time.Time
+	data      []byte
+}
+
+// MemoryStore a `Store` keeping the sessions in memory. The sessions are lost
+// when the application stops and are not shared between multiple instances, so
+// this store is mostly suitable for development and tests.
+type MemoryStore struct {
+	sessions map[string]memoryEntry
+	mu       sync.RWMutex
+}
+
+// NewMemoryStore create a new empty `MemoryStore`.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions: map[string]memoryEntry{},
+	}
+}
+
+// Load returns the data of the session identified by the given ID.
+func (s *MemoryStore) Load(_ context.Context, id string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.sessions[id]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, nil
+	}
+	return entry.data, nil
+}
+
+// Save the data of the session identified by the given ID.
+func (s *MemoryStore) Save(_ context.Context, id string, data []byte, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[id] = memoryEntry{data: data, expiresAt: expiresAt}
+	return nil
+}
+
+// Delete the session identified by the given ID.
+func (s *MemoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+// Sweep deletes all the sessions expired at the given time.
+func (s *MemoryStore) Sweep(_ context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, entry := range s.sessions {
+		if now.After(entry.expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of sessions in the store, including expired sessions
+// that have not been swept yet.
+func (s *MemoryStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.sessions)
+}
+
+// CookieStore a `Store` keeping the session data on the client side in the session
+// cookie, encrypted with the application key. No server-side storage is needed, but the
+// session cannot be invalidated server-side before it expires and the serialized data
+// must fit in a cookie (4KB).
+//
+// The methods of this store are no-ops: the session middleware handles the cookie directly.
+type CookieStore struct{}
+
+// Load always returns `nil`.
+func (CookieStore) Load(_ context.Context, _ string) ([]byte, error) { return nil, nil }
+
+// Save does nothing.
+func (CookieStore) Save(_ context.Context, _ string, _ []byte, _ time.Time) error { return nil }
+
+// Delete does nothing.
+func (CookieStore) Delete(_ context.Context, _ string) error { return nil }
+
+// Sweep does nothing.
+func (CookieStore) Sweep(_ context.Context, _ time.Time) error { return nil }
@@ -0,0 +1,63 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	now := time.Now()
+
+	data, err := store.Load(ctx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	require.NoError(t, store.Save(ctx, "a", []byte("data-a"), now.Add(time.Hour)))
+	require.NoError(t, store.Save(ctx, "b", []byte("data-b"), now.Add(-time.Second)))
+	require.NoError(t, store.Save(ctx, "c", []byte("data-c"), now.Add(2*time.Hour)))
+
+	data, err = store.Load(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data-a"), data)
+
+	data, err = store.Load(ctx, "b")
+	require.NoError(t, err)
+	assert.Nil(t, data, "expired sessions are not loaded")
+
+	require.NoError(t, store.Save(ctx, "a", []byte("updated"), now.Add(time.Hour)))
+	data, err = store.Load(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("updated"), data)
+
+	require.NoError(t, store.Delete(ctx, "c"))
+	data, err = store.Load(ctx, "c")
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	require.NoError(t, store.Sweep(ctx, now))
+	data, err = store.Load(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("updated"), data)
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	testStore(t, store)
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestCookieStore(t *testing.T) {
+	store := CookieStore{}
+	ctx := context.Background()
+	assert.NoError(t, store.Save(ctx, "a", []byte("data"), time.Now().Add(time.Hour)))
+	data, err := store.Load(ctx, "a")
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	assert.NoError(t, store.Delete(ctx, "a"))
+	assert.NoError(t, store.Sweep(ctx, time.Now()))
+}
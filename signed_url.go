@@ -0,0 +1,197 @@
+package goyave
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+
+	errorutil "goyave.dev/goyave/v5/util/errors"
+)
+
+// Query parameters added to signed URLs.
+const (
+	SignedURLExpiresParameter   = "expires"
+	SignedURLSignatureParameter = "signature"
+)
+
+var (
+	// ErrInvalidSignature returned by `VerifySignedURL` if the URL signature is missing or
+	// doesn't match any of the application keys.
+	ErrInvalidSignature = errors.New("invalid URL signature")
+
+	// ErrExpiredSignature returned by `VerifySignedURL` if the signed URL is expired.
+	ErrExpiredSignature = errors.New("expired URL signature")
+)
+
+// BuildSignedURL build a full URL pointing to this route, signed with the application
+// key (`app.key` config entry) and valid until the given expiration date.
+// The signature and expiration date are added to the URL's query. The signed URL
+// can be verified using `VerifySignedURL` or `SignedURLMiddleware`.
+//
+// Panics if the amount of parameters doesn't match the amount of actual
+// parameters for this route or if the application key is not set.
+func (r *Route) BuildSignedURL(expiresAt time.Time, parameters ...string) string {
+	return r.buildSignedURL(r.parent.server.BaseURL(), expiresAt, "", parameters)
+}
+
+// BuildUserSignedURL like `BuildSignedURL` but the signature is bound to the given
+// user ID. The link will only be accepted by a `SignedURLMiddleware` with a `UserID`
+// function returning the same user ID.
+func (r *Route) BuildUserSignedURL(userID string, expiresAt time.Time, parameters ...string) string {
+	return r.buildSignedURL(r.parent.server.BaseURL(), expiresAt, userID, parameters)
+}
+
+// BuildSignedProxyURL like `BuildSignedURL` but using the proxy base URL (see `Route.BuildProxyURL()`).
+// Use this function to generate links followed by users when the application is served
+// behind a reverse proxy.
+func (r *Route) BuildSignedProxyURL(expiresAt time.Time, parameters ...string) string {
+	return r.buildSignedURL(r.parent.server.ProxyBaseURL(), expiresAt, "", parameters)
+}
+
+// BuildUserSignedProxyURL like `BuildUserSignedURL` but using the proxy base URL (see `Route.BuildProxyURL()`).
+func (r *Route) BuildUserSignedProxyURL(userID string, expiresAt time.Time, parameters ...string) string {
+	return r.buildSignedURL(r.parent.server.ProxyBaseURL(), expiresAt, userID, parameters)
+}
+
+func (r *Route) buildSignedURL(baseURL string, expiresAt time.Time, userID string, parameters []string) string {
+	key := r.parent.server.config.GetString("app.key")
+	if key == "" {
+		panic(errorutil.NewSkip("cannot sign URL: the \"app.key\" config entry is not set", 4))
+	}
+	u, err := url.Parse(r.buildURL(baseURL, parameters))
+	if err != nil {
+		panic(errorutil.New(err))
+	}
+	query := url.Values{SignedURLExpiresParameter: {strconv.FormatInt(expiresAt.Unix(), 10)}}
+	query.Set(SignedURLSignatureParameter, signURL(key, u, query, userID))
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// VerifySignedURL checks the signature and the expiration date of the given URL.
+// The signature is checked against the application key (`app.key` config entry) first,
+// then against each of the previous keys (`app.previousKeys` config entry) to support
+// key rotation. If the URL was signed for a user, the same user ID must be given.
+// The scheme, host, path and query of the URL are signed, so a link signed for a host
+// (e.g. a tenant subdomain) cannot be used on another host. The given URL must therefore
+// be absolute. Default ports are ignored.
+//
+// Returns `ErrInvalidSignature` or `ErrExpiredSignature` if the URL is not valid.
+func (s *Server) VerifySignedURL(u *url.URL, userID string) error {
+	query := u.Query()
+	signature := query.Get(SignedURLSignatureParameter)
+	query.Del(SignedURLSignatureParameter)
+
+	keys := append([]string{s.config.GetString("app.key")}, s.config.GetStringSlice("app.previousKeys")...)
+	valid := false
+	for _, key := range keys {
+		if key != "" && hmac.Equal([]byte(signature), []byte(signURL(key, u, query, userID))) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	expires, err := strconv.ParseInt(query.Get(SignedURLExpiresParameter), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if time.Now().After(time.Unix(expires, 0)) {
+		return ErrExpiredSignature
+	}
+	return nil
+}
+
+func signURL(key string, u *url.URL, query url.Values, userID string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(signedURLOrigin(u) + u.EscapedPath() + "?" + query.Encode() + "\n" + userID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signedURLOrigin returns the normalized scheme and host of the given URL.
+// The default port of the scheme is omitted.
+func signedURLOrigin(u *url.URL) string {
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		host = net.JoinHostPort(host, port)
+	}
+	return scheme + "://" + host
+}
+
+// SignedURLMiddleware rejects requests whose URL is not correctly signed or is expired
+// with a `403 Forbidden` error and a localized message. Use this middleware on routes
+// accessed through links generated with `Route.BuildSignedURL()` or `Route.BuildUserSignedURL()`.
+//
+// If the "server.proxy.host" config entry is set, the URL is verified against the proxy
+// base URL, so only links generated with `Route.BuildSignedProxyURL()` or
+// `Route.BuildUserSignedProxyURL()` are accepted. The host of the request is used instead
+// of the proxy host for routes belonging to a host router.
+// Otherwise, the URL is verified using the host of the request and the "https" scheme if the
+// request uses TLS, "http" otherwise.
+type SignedURLMiddleware struct {
+	Component
+
+	// UserID returns the ID of the user the link must be bound to (e.g. the ID of
+	// the authenticated user). If not nil, only links generated with
+	// `Route.BuildUserSignedURL()` for this user are accepted.
+	UserID func(request *Request) string
+}
+
+// Handle checks the request URL signature.
+func (m *SignedURLMiddleware) Handle(next Handler) Handler {
+	return func(response *Response, request *Request) {
+		userID := ""
+		if m.UserID != nil {
+			userID = m.UserID(request)
+		}
+		if err := m.Server().VerifySignedURL(m.publicURL(request), userID); err != nil {
+			message := request.Lang.Get("signed-url.invalid")
+			if err == ErrExpiredSignature {
+				message = request.Lang.Get("signed-url.expired")
+			}
+			response.JSON(http.StatusForbidden, map[string]string{"error": message})
+			return
+		}
+		next(response, request)
+	}
+}
+
+// publicURL returns the URL of the request as followed by the user.
+func (m *SignedURLMiddleware) publicURL(request *Request) *url.URL {
+	u := *request.URL()
+	if !m.Config().Has("server.proxy.host") {
+		u.Host = request.Request().Host
+		u.Scheme = lo.Ternary(request.Request().TLS != nil, "https", "http")
+		return &u
+	}
+
+	base, err := url.Parse(m.Server().ProxyBaseURL())
+	if err != nil {
+		panic(errorutil.New(err))
+	}
+	u.Scheme = base.Scheme
+	u.Host = base.Host
+	if request.Route != nil && request.Route.parent.hostRouter() != nil {
+		u.Host = (&url.URL{Host: request.Request().Host}).Hostname()
+		if port := base.Port(); port != "" {
+			u.Host = net.JoinHostPort(u.Host, port)
+		}
+	}
+	if u.RawPath != "" {
+		u.RawPath = base.EscapedPath() + u.RawPath
+	}
+	u.Path = base.Path + u.Path
+	return &u
+}
@@ -0,0 +1,193 @@
+package goyave
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/config"
+)
+
+func prepareSignedURLTest(t *testing.T) *Router {
+	cfg := config.LoadDefault()
+	cfg.Set("app.key", "current-key")
+	cfg.Set("app.previousKeys", []string{"old-key"})
+	server, err := New(Options{Config: cfg})
+	require.NoError(t, err)
+	return NewRouter(server)
+}
+
+func serveSignedURLTest(t *testing.T, router *Router, path string, header http.Header) (*http.Response, string) {
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	router.ServeHTTP(recorder, req)
+	res := recorder.Result()
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, res.Body.Close())
+	require.NoError(t, err)
+	return res, string(body)
+}
+
+func TestSignedURL(t *testing.T) {
+	t.Run("BuildSignedURL", func(t *testing.T) {
+		router := prepareSignedURLTest(t)
+		route := router.Get("/download/{file}", nil)
+		expires := time.Unix(1893456000, 0)
+		signed := route.BuildSignedURL(expires, "report.pdf")
+
+		u, err := url.Parse(signed)
+		require.NoError(t, err)
+		assert.Equal(t, "http://127.0.0.1:8080/download/report.pdf", u.Scheme+"://"+u.Host+u.Path)
+		assert.Equal(t, "1893456000", u.Query().Get(SignedURLExpiresParameter))
+		assert.Len(t, u.Query().Get(SignedURLSignatureParameter), 64)
+
+		assert.NotEqual(t, signed, route.BuildUserSignedURL("1", expires, "report.pdf"))
+	})
+
+	t.Run("BuildSignedURL_no_key", func(t *testing.T) {
+		router := prepareRouterTest()
+		route := router.Get("/download/{file}", nil)
+		assert.Panics(t, func() {
+			route.BuildSignedURL(time.Now().Add(time.Hour), "report.pdf")
+		})
+	})
+
+	t.Run("VerifySignedURL", func(t *testing.T) {
+		router := prepareSignedURLTest(t)
+		server := router.server
+		route := router.Get("/download/{file}", nil)
+
+		verify := func(signedURL, userID string) error {
+			u, err := url.Parse(signedURL)
+			require.NoError(t, err)
+			return server.VerifySignedURL(u, userID)
+		}
+
+		valid := route.BuildSignedURL(time.Now().Add(time.Hour), "report.pdf")
+		assert.NoError(t, verify(valid, ""))
+		assert.ErrorIs(t, verify(valid, "1"), ErrInvalidSignature)
+		assert.ErrorIs(t, verify(strings.Replace(valid, "report.pdf", "secret.pdf", 1), ""), ErrInvalidSignature)
+		assert.ErrorIs(t, verify(strings.Replace(valid, "expires=", "expires=9", 1), ""), ErrInvalidSignature)
+		assert.ErrorIs(t, verify("http://127.0.0.1:8080/download/report.pdf", ""), ErrInvalidSignature)
+		assert.ErrorIs(t, verify(strings.Replace(valid, "127.0.0.1:8080", "127.0.0.2:8080", 1), ""), ErrInvalidSignature)
+		assert.ErrorIs(t, verify(strings.Replace(valid, "http://", "https://", 1), ""), ErrInvalidSignature)
+
+		expired := route.BuildSignedURL(time.Now().Add(-time.Minute), "report.pdf")
+		assert.ErrorIs(t, verify(expired, ""), ErrExpiredSignature)
+
+		bound := route.BuildUserSignedURL("1", time.Now().Add(time.Hour), "report.pdf")
+		assert.NoError(t, verify(bound, "1"))
+		assert.ErrorIs(t, verify(bound, "2"), ErrInvalidSignature)
+		assert.ErrorIs(t, verify(bound, ""), ErrInvalidSignature)
+
+		t.Run("host_router", func(t *testing.T) {
+			tenantRoute := router.Host("{tenant}.example.com").Get("/download/{file}", nil)
+			signed := tenantRoute.BuildSignedURL(time.Now().Add(time.Hour), "acme", "report.pdf")
+			assert.True(t, strings.HasPrefix(signed, "http://acme.example.com:8080/download/report.pdf?"))
+			assert.NoError(t, verify(signed, ""))
+			assert.NoError(t, verify(strings.Replace(signed, "acme.example.com", "ACME.example.com", 1), ""))
+			assert.ErrorIs(t, verify(strings.Replace(signed, "acme.", "other.", 1), ""), ErrInvalidSignature)
+		})
+
+		t.Run("key_rotation", func(t *testing.T) {
+			server.config.Set("app.key", "old-key")
+			signed := route.BuildSignedURL(time.Now().Add(time.Hour), "report.pdf")
+			server.config.Set("app.key", "current-key")
+			assert.NoError(t, verify(signed, ""))
+
+			server.config.Set("app.previousKeys", []string{})
+			assert.ErrorIs(t, verify(signed, ""), ErrInvalidSignature)
+		})
+	})
+
+	t.Run("SignedURLMiddleware_proxy", func(t *testing.T) {
+		cfg := config.LoadDefault()
+		cfg.Set("app.key", "current-key")
+		cfg.Set("server.proxy.host", "public.example.com")
+		cfg.Set("server.proxy.protocol", "https")
+		cfg.Set("server.proxy.port", 443)
+		cfg.Set("server.proxy.base", "/app")
+		server, err := New(Options{Config: cfg})
+		require.NoError(t, err)
+		router := NewRouter(server)
+		handler := func(response *Response, _ *Request) {
+			response.String(http.StatusOK, "ok")
+		}
+		route := router.Get("/download/{file}", handler).Middleware(&SignedURLMiddleware{})
+		tenantRoute := router.Host("{tenant}.example.com").Get("/download/{file}", handler).Middleware(&SignedURLMiddleware{})
+
+		// The proxy terminates TLS and strips the base path
+		toInternal := func(signed string) string {
+			u, err := url.Parse(signed)
+			require.NoError(t, err)
+			return strings.TrimPrefix(u.RequestURI(), "/app")
+		}
+
+		signed := route.BuildSignedProxyURL(time.Now().Add(time.Hour), "report.pdf")
+		assert.True(t, strings.HasPrefix(signed, "https://public.example.com/app/download/report.pdf?"))
+		res, body := serveSignedURLTest(t, router, toInternal(signed), nil)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "ok", body)
+
+		userSigned := route.BuildUserSignedProxyURL("1", time.Now().Add(time.Hour), "report.pdf")
+		res, _ = serveSignedURLTest(t, router, toInternal(userSigned), nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+
+		res, _ = serveSignedURLTest(t, router, toInternal(route.BuildSignedURL(time.Now().Add(time.Hour), "report.pdf")), nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode, "internal URL")
+
+		tenantSigned := tenantRoute.BuildSignedProxyURL(time.Now().Add(time.Hour), "acme", "report.pdf")
+		assert.True(t, strings.HasPrefix(tenantSigned, "https://acme.example.com/app/download/report.pdf?"))
+		res, _ = serveSignedURLTest(t, router, "http://acme.example.com"+toInternal(tenantSigned), nil)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		res, _ = serveSignedURLTest(t, router, "http://other.example.com"+toInternal(tenantSigned), nil)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("SignedURLMiddleware", func(t *testing.T) {
+		router := prepareSignedURLTest(t)
+		handler := func(response *Response, _ *Request) {
+			response.String(http.StatusOK, "ok")
+		}
+		route := router.Get("/download/{file}", handler).Middleware(&SignedURLMiddleware{})
+		userRoute := router.Get("/invoice/{id}", handler).Middleware(&SignedURLMiddleware{
+			UserID: func(request *Request) string {
+				return request.Header().Get("X-User")
+			},
+		})
+
+		cases := []struct {
+			header         http.Header
+			desc           string
+			path           string
+			expectedBody   string
+			expectedStatus int
+		}{
+			{desc: "valid", path: route.BuildSignedURL(time.Now().Add(time.Hour), "report.pdf"), expectedStatus: http.StatusOK, expectedBody: "ok"},
+			{desc: "tampered", path: strings.Replace(route.BuildSignedURL(time.Now().Add(time.Hour), "report.pdf"), "report", "secret", 1), expectedStatus: http.StatusForbidden, expectedBody: "{\"error\":\"Invalid or missing link signature.\"}\n"},
+			{desc: "missing", path: "/download/report.pdf", expectedStatus: http.StatusForbidden, expectedBody: "{\"error\":\"Invalid or missing link signature.\"}\n"},
+			{desc: "expired", path: route.BuildSignedURL(time.Now().Add(-time.Hour), "report.pdf"), expectedStatus: http.StatusForbidden, expectedBody: "{\"error\":\"This link has expired.\"}\n"},
+			{desc: "user_bound", path: userRoute.BuildUserSignedURL("1", time.Now().Add(time.Hour), "12"), header: http.Header{"X-User": {"1"}}, expectedStatus: http.StatusOK, expectedBody: "ok"},
+			{desc: "user_bound_other_user", path: userRoute.BuildUserSignedURL("1", time.Now().Add(time.Hour), "12"), header: http.Header{"X-User": {"2"}}, expectedStatus: http.StatusForbidden, expectedBody: "{\"error\":\"Invalid or missing link signature.\"}\n"},
+			{desc: "other_host", path: strings.Replace(route.BuildSignedURL(time.Now().Add(time.Hour), "report.pdf"), "127.0.0.1", "localhost", 1), expectedStatus: http.StatusForbidden, expectedBody: "{\"error\":\"Invalid or missing link signature.\"}\n"},
+			{desc: "user_bound_unbound_link", path: userRoute.BuildSignedURL(time.Now().Add(time.Hour), "12"), header: http.Header{"X-User": {"1"}}, expectedStatus: http.StatusForbidden, expectedBody: "{\"error\":\"Invalid or missing link signature.\"}\n"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.desc, func(t *testing.T) {
+				res, body := serveSignedURLTest(t, router, c.path, c.header)
+				assert.Equal(t, c.expectedStatus, res.StatusCode)
+				assert.Equal(t, c.expectedBody, body)
+			})
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// InMemoryExporter an exporter keeping all exported spans in memory.
+// It is meant to be used in tests.
+type InMemoryExporter struct {
+	spans    []SpanData
+	mu       sync.Mutex
+	shutdown bool
+}
+
+var _ Exporter = (*InMemoryExporter)(nil)
+
+// NewInMemoryExporter create a new empty InMemoryExporter.
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+// Export stores the given spans in memory.
+func (e *InMemoryExporter) Export(_ context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+// Shutdown marks the exporter as shut down. The stored spans are kept.
+func (e *InMemoryExporter) Shutdown(_ context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.shutdown = true
+	return nil
+}
+
+// IsShutdown returns true if `Shutdown()` has been called.
+func (e *InMemoryExporter) IsShutdown() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.shutdown
+}
+
+// Spans returns a copy of all the exported spans in order of export.
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return slices.Clone(e.spans)
+}
+
+// Reset removes all stored spans.
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
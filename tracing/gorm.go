@@ -0,0 +1,144 @@
+package tracing
+
+import (
+	stderrors "errors"
+	"regexp"
+
+	"gorm.io/gorm"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+const (
+	tracingCallbackBeforeName = "goyave:tracing_before"
+	tracingCallbackAfterName  = "goyave:tracing_after"
+	tracingInstanceKey        = "goyave:tracing_span"
+)
+
+// GORMPlugin GORM plugin creating a client span for each SQL query. It works the same way
+// as `database.TimeoutPlugin`: the statement's context is replaced with a context containing
+// the new span in a "before" callback on all GORM operations. In an "after" callback, the
+// span is ended.
+//
+// The span is a child of the span contained in the statement's context. Use
+// `db.WithContext(request.Context())` so queries are part of the request's trace.
+//
+// The SQL query is recorded in the "db.statement" attribute after being sanitized:
+// string and numeric literals are replaced with "?" so no sensitive value is exported.
+// Bound variables are never recorded.
+//
+//	if err := server.DB().Use(&tracing.GORMPlugin{Tracer: provider}); err != nil {
+//		panic(err)
+//	}
+type GORMPlugin struct {
+	Tracer Tracer
+}
+
+// Name returns the name of the plugin
+func (p *GORMPlugin) Name() string {
+	return "goyave:tracing"
+}
+
+// Initialize registers the callbacks for all operations.
+func (p *GORMPlugin) Initialize(db *gorm.DB) error {
+	createCallback := db.Callback().Create()
+	if err := createCallback.Before("*").Register(tracingCallbackBeforeName, p.beforeFunc("create")); err != nil {
+		return errors.New(err)
+	}
+	if err := createCallback.After("*").Register(tracingCallbackAfterName, p.after); err != nil {
+		return errors.New(err)
+	}
+
+	queryCallback := db.Callback().Query()
+	if err := queryCallback.Before("*").Register(tracingCallbackBeforeName, p.beforeFunc("query")); err != nil {
+		return errors.New(err)
+	}
+	if err := queryCallback.After("*").Register(tracingCallbackAfterName, p.after); err != nil {
+		return errors.New(err)
+	}
+
+	deleteCallback := db.Callback().Delete()
+	if err := deleteCallback.Before("*").Register(tracingCallbackBeforeName, p.beforeFunc("delete")); err != nil {
+		return errors.New(err)
+	}
+	if err := deleteCallback.After("*").Register(tracingCallbackAfterName, p.after); err != nil {
+		return errors.New(err)
+	}
+
+	updateCallback := db.Callback().Update()
+	if err := updateCallback.Before("*").Register(tracingCallbackBeforeName, p.beforeFunc("update")); err != nil {
+		return errors.New(err)
+	}
+	if err := updateCallback.After("*").Register(tracingCallbackAfterName, p.after); err != nil {
+		return errors.New(err)
+	}
+
+	rowCallback := db.Callback().Row()
+	if err := rowCallback.Before("*").Register(tracingCallbackBeforeName, p.beforeFunc("row")); err != nil {
+		return errors.New(err)
+	}
+	if err := rowCallback.After("*").Register(tracingCallbackAfterName, p.after); err != nil {
+		return errors.New(err)
+	}
+
+	rawCallback := db.Callback().Raw()
+	if err := rawCallback.Before("*").Register(tracingCallbackBeforeName, p.beforeFunc("raw")); err != nil {
+		return errors.New(err)
+	}
+	if err := rawCallback.After("*").Register(tracingCallbackAfterName, p.after); err != nil {
+		return errors.New(err)
+	}
+	return nil
+}
+
+func (p *GORMPlugin) beforeFunc(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		p.before(db, operation)
+	}
+}
+
+func (p *GORMPlugin) before(db *gorm.DB, operation string) {
+	if db.Statement.Context == nil {
+		return
+	}
+	attributes := []Attribute{Attr("db.operation", operation)}
+	if db.Dialector != nil {
+		attributes = append(attributes, Attr("db.system", db.Dialector.Name()))
+	}
+	if db.Statement.Table != "" {
+		attributes = append(attributes, Attr("db.sql.table", db.Statement.Table))
+	}
+	ctx, span := p.Tracer.Start(db.Statement.Context, "db."+operation, &StartOptions{
+		Kind:       SpanKindClient,
+		Attributes: attributes,
+	})
+	db.Statement.Context = ctx
+	db.InstanceSet(tracingInstanceKey, span)
+}
+
+func (p *GORMPlugin) after(db *gorm.DB) {
+	val, ok := db.InstanceGet(tracingInstanceKey)
+	if !ok {
+		return
+	}
+	span := val.(Span)
+	if sql := db.Statement.SQL.String(); sql != "" {
+		span.SetAttributes(Attr("db.statement", SanitizeSQL(sql)))
+	}
+	span.SetAttributes(Attr("db.rows_affected", db.Statement.RowsAffected))
+	if err := db.Error; err != nil && !stderrors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+var (
+	sqlStringLiteralRegex  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlNumericLiteralRegex = regexp.MustCompile(`(^|[^\w$.])\d+(?:\.\d+)?\b`)
+)
+
+// SanitizeSQL replaces string and numeric literals in the given SQL query
+// with a "?" placeholder so the query can be safely exported.
+func SanitizeSQL(sql string) string {
+	sql = sqlStringLiteralRegex.ReplaceAllString(sql, "?")
+	return sqlNumericLiteralRegex.ReplaceAllString(sql, "${1}?")
+}
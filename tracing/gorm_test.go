@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type testModel struct {
+	Name string
+	ID   uint
+}
+
+func TestGORMPlugin(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:tracing_test.db?mode=memory"), &gorm.Config{})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		assert.NoError(t, sqlDB.Close())
+	})
+	require.NoError(t, db.AutoMigrate(&testModel{}))
+
+	exporter := NewInMemoryExporter()
+	provider := NewProvider(exporter, 1)
+	plugin := &GORMPlugin{Tracer: provider}
+	assert.Equal(t, "goyave:tracing", plugin.Name())
+	require.NoError(t, db.Use(plugin))
+
+	ctx, root := provider.Start(context.Background(), "root", nil)
+
+	require.NoError(t, db.WithContext(ctx).Create(&testModel{Name: "secret"}).Error)
+	var model testModel
+	require.NoError(t, db.WithContext(ctx).Where("name = 'secret' AND id = 1").First(&model).Error)
+	require.Error(t, db.WithContext(ctx).Table("unknown_table").Find(&[]testModel{}).Error)
+	root.End()
+	provider.Flush(context.Background())
+
+	spans := exporter.Spans()
+	require.Len(t, spans, 4)
+
+	create := spans[0]
+	assert.Equal(t, "db.create", create.Name)
+	assert.Equal(t, SpanKindClient, create.Kind)
+	assert.Equal(t, root.SpanContext().SpanID, create.ParentSpanID)
+	table, _ := create.Attribute("db.sql.table")
+	assert.Equal(t, "test_models", table)
+	system, _ := create.Attribute("db.system")
+	assert.Equal(t, "sqlite", system)
+	rows, _ := create.Attribute("db.rows_affected")
+	assert.Equal(t, int64(1), rows)
+
+	query := spans[1]
+	assert.Equal(t, "db.query", query.Name)
+	statement, _ := query.Attribute("db.statement")
+	assert.NotContains(t, statement, "secret")
+	assert.Contains(t, statement, "name = ? AND id = ?")
+	assert.Equal(t, StatusUnset, query.Status)
+
+	failed := spans[2]
+	assert.Equal(t, StatusError, failed.Status)
+}
+
+func TestSanitizeSQL(t *testing.T) {
+	assert.Equal(t,
+		`SELECT * FROM "users" WHERE name = ? AND age > ? AND score = ? AND "users"."deleted_at" IS NULL AND t2 = $1`,
+		SanitizeSQL(`SELECT * FROM "users" WHERE name = 'O''Brien' AND age > 18 AND score = 2.5 AND "users"."deleted_at" IS NULL AND t2 = $1`),
+	)
+}
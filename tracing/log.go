@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogHandler a `slog.Handler` wrapper adding the "trace_id" and "span_id" attributes to
+// records if the context given to the logger contains a valid span.
+//
+// Use the context-aware methods of the logger (e.g. `InfoContext()`, `ErrorCtx()`) with
+// the request's context so the records can be correlated with traces.
+//
+//	logger := slog.New(tracing.NewLogHandler(slog.NewHandler(false, os.Stderr)))
+//	server, err := goyave.New(goyave.Options{Logger: logger})
+type LogHandler struct {
+	slog.Handler
+}
+
+var _ slog.Handler = (*LogHandler)(nil)
+
+// NewLogHandler create a new LogHandler wrapping the given handler.
+func NewLogHandler(handler slog.Handler) *LogHandler {
+	return &LogHandler{Handler: handler}
+}
+
+// Handle adds the trace attributes to the record and passes it to the wrapped handler.
+func (h *LogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := SpanFromContext(ctx).SpanContext(); sc.IsValid() {
+		r = r.Clone()
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID.String()),
+			slog.String("span_id", sc.SpanID.String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a new LogHandler wrapping the result of `WithAttrs` on the wrapped handler.
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup returns a new LogHandler wrapping the result of `WithGroup` on the wrapped handler.
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := NewLogHandler(slog.NewJSONHandler(buf, nil))
+	logger := slog.New(handler.WithAttrs([]slog.Attr{slog.String("attr", "value")}))
+
+	provider := NewProvider(NewInMemoryExporter(), 1)
+	ctx, span := provider.Start(context.Background(), "span", nil)
+	defer span.End()
+
+	logger.InfoContext(ctx, "message")
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, span.SpanContext().TraceID.String(), record["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID.String(), record["span_id"])
+	assert.Equal(t, "value", record["attr"])
+
+	buf.Reset()
+	logger.InfoContext(context.Background(), "message")
+	record = nil
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.NotContains(t, record, "trace_id")
+
+	buf.Reset()
+	slog.New(handler.WithGroup("group")).InfoContext(ctx, "message", "key", "val")
+	record = nil
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, span.SpanContext().TraceID.String(), record["group"].(map[string]any)["trace_id"])
+}
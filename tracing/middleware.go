@@ -0,0 +1,136 @@
+package tracing
+
+import (
+	"io"
+	"net/http"
+
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// Middleware starts a server span for each request. If the request contains
+// a valid W3C `traceparent` header, the span is a child of the remote span.
+//
+// The span is added to the request's context and can be retrieved using
+// `tracing.SpanFromContext(request.Context())`. Child spans (such as SQL queries
+// executed with `DB().WithContext(request.Context())`) are therefore part of the
+// same trace.
+//
+// The span is named after the method and the matched route's full URI definition
+// (e.g. `GET /users/{id}`). It is ended at the very end of the request's lifecycle
+// so the status set by status handlers is recorded. Responses with a 5xx status
+// mark the span as failed.
+//
+// This middleware should be used as a global middleware.
+type Middleware struct {
+	goyave.Component
+	Tracer Tracer
+}
+
+// Handle starts the request span and adds the chained writer ending it.
+func (m *Middleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		ctx := request.Context()
+		if header := request.Header().Get(TraceparentHeader); header != "" {
+			if sc, err := ParseTraceparent(header); err == nil {
+				ctx = ContextWithRemoteSpanContext(ctx, sc)
+			}
+		}
+
+		uri := request.Route.GetFullURI()
+		attributes := []Attribute{
+			Attr("http.request.method", request.Method()),
+			Attr("url.path", request.URL().Path),
+			Attr("user_agent.original", request.UserAgent()),
+		}
+		name := request.Method()
+		if uri != "" {
+			name += " " + uri
+			attributes = append(attributes, Attr("http.route", uri))
+		}
+		ctx, span := m.Tracer.Start(ctx, name, &StartOptions{
+			Kind:       SpanKindServer,
+			Start:      request.Now,
+			Attributes: attributes,
+		})
+
+		response.SetWriter(&writer{
+			writer:   response.Writer(),
+			span:     span,
+			response: response,
+		})
+		next(response, request.WithContext(ctx))
+	}
+}
+
+// writer chained writer ending the request span when closed.
+type writer struct {
+	writer   io.Writer
+	span     Span
+	response *goyave.Response
+}
+
+var _ io.Closer = (*writer)(nil)
+var _ goyave.PreWriter = (*writer)(nil)
+
+func (w *writer) PreWrite(b []byte) {
+	if pr, ok := w.writer.(goyave.PreWriter); ok {
+		pr.PreWrite(b)
+	}
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	n, err := w.writer.Write(b)
+	return n, errors.New(err)
+}
+
+func (w *writer) Close() error {
+	status := w.response.GetStatus()
+	w.span.SetAttributes(Attr("http.response.status_code", status))
+	if err := w.response.GetError(); err != nil {
+		w.span.RecordError(err)
+	} else if status >= http.StatusInternalServerError {
+		w.span.SetStatus(StatusError, http.StatusText(status))
+	}
+	w.span.End()
+
+	if wr, ok := w.writer.(io.Closer); ok {
+		return wr.Close()
+	}
+	return nil
+}
+
+// WrapMiddleware returns a middleware executing the given middleware inside its own span,
+// allowing to measure each phase of the middleware stack. The span is a child of the span
+// contained in the request's context and is named after the given name.
+//
+//	router.Middleware(tracing.WrapMiddleware(tracer, "auth", authMiddleware))
+func WrapMiddleware(tracer Tracer, name string, middleware goyave.Middleware) goyave.Middleware {
+	return &wrappedMiddleware{
+		Middleware: middleware,
+		tracer:     tracer,
+		name:       name,
+	}
+}
+
+type wrappedMiddleware struct {
+	goyave.Middleware
+	tracer Tracer
+	name   string
+}
+
+func (m *wrappedMiddleware) Handle(next goyave.Handler) goyave.Handler {
+	handler := m.Middleware.Handle(next)
+	return func(response *goyave.Response, request *goyave.Request) {
+		parentCtx := request.Context()
+		ctx, span := m.tracer.Start(parentCtx, "middleware "+m.name, &StartOptions{
+			Kind:       SpanKindInternal,
+			Attributes: []Attribute{Attr("goyave.middleware", m.name)},
+		})
+		defer func() {
+			span.End()
+			request.WithContext(parentCtx)
+		}()
+		handler(response, request.WithContext(ctx))
+	}
+}
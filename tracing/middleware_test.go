@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/testutil"
+)
+
+type testMiddleware struct {
+	goyave.Component
+	called bool
+}
+
+func (m *testMiddleware) Handle(next goyave.Handler) goyave.Handler {
+	return func(response *goyave.Response, request *goyave.Request) {
+		m.called = true
+		next(response, request)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	cfg := config.LoadDefault()
+	cfg.Set("app.debug", false)
+	server := testutil.NewTestServerWithOptions(t, goyave.Options{Config: cfg})
+	exporter := NewInMemoryExporter()
+	provider := NewProvider(exporter, 1)
+
+	var requestSpan SpanContext
+	inner := &testMiddleware{}
+	router := goyave.NewRouter(server.Server)
+	router.GlobalMiddleware(&Middleware{Tracer: provider})
+	router.Get("/users/{id}", func(response *goyave.Response, request *goyave.Request) {
+		requestSpan = SpanFromContext(request.Context()).SpanContext()
+		response.String(http.StatusOK, "user")
+	}).Middleware(WrapMiddleware(provider, "test", inner))
+	router.Get("/panic", func(_ *goyave.Response, _ *goyave.Request) {
+		panic("test panic")
+	})
+
+	t.Run("span", func(t *testing.T) {
+		exporter.Reset()
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		req.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		req.Header.Set("User-Agent", "test-agent")
+		router.ServeHTTP(recorder, req)
+
+		assert.True(t, inner.called)
+		provider.Flush(context.Background())
+		spans := exporter.Spans()
+		require.Len(t, spans, 2)
+
+		middlewareSpan := spans[0]
+		assert.Equal(t, "middleware test", middlewareSpan.Name)
+		assert.Equal(t, requestSpan, middlewareSpan.SpanContext)
+
+		span := spans[1]
+		assert.Equal(t, "GET /users/{id}", span.Name)
+		assert.Equal(t, SpanKindServer, span.Kind)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext.TraceID.String())
+		assert.Equal(t, "00f067aa0ba902b7", span.ParentSpanID.String())
+		assert.Equal(t, span.SpanContext.SpanID, middlewareSpan.ParentSpanID)
+		assert.Equal(t, StatusUnset, span.Status)
+
+		expectedAttrs := map[string]any{
+			"http.request.method":       http.MethodGet,
+			"url.path":                  "/users/1",
+			"user_agent.original":       "test-agent",
+			"http.route":                "/users/{id}",
+			"http.response.status_code": http.StatusOK,
+		}
+		for k, v := range expectedAttrs {
+			val, ok := span.Attribute(k)
+			assert.True(t, ok, k)
+			assert.Equal(t, v, val, k)
+		}
+	})
+
+	t.Run("invalid_traceparent", func(t *testing.T) {
+		exporter.Reset()
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/unknown", nil)
+		req.Header.Set(TraceparentHeader, "invalid")
+		router.ServeHTTP(recorder, req)
+
+		provider.Flush(context.Background())
+		spans := exporter.Spans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, http.MethodGet, spans[0].Name)
+		assert.False(t, spans[0].ParentSpanID.IsValid())
+		status, _ := spans[0].Attribute("http.response.status_code")
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		exporter.Reset()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		provider.Flush(context.Background())
+		spans := exporter.Spans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, StatusError, spans[0].Status)
+		assert.Equal(t, "test panic", spans[0].StatusText)
+	})
+}
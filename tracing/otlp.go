@@ -0,0 +1,195 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// OTLPExporter exports spans to an OpenTelemetry collector using the
+// OTLP/HTTP protocol with the JSON encoding.
+//
+// Spans are sent with a `POST` request to `{Endpoint}/v1/traces`.
+type OTLPExporter struct {
+	// Client the HTTP client used to send the requests. Defaults to a client
+	// with a 10 seconds timeout.
+	Client *http.Client
+
+	// Headers additional headers sent with each request (e.g. authentication).
+	Headers map[string]string
+
+	// Endpoint the base URL of the collector (e.g. "http://localhost:4318").
+	Endpoint string
+
+	// ServiceName the value of the "service.name" resource attribute.
+	ServiceName string
+}
+
+var _ Exporter = (*OTLPExporter)(nil)
+
+// NewOTLPExporter create a new OTLP/HTTP JSON exporter sending spans to the given endpoint.
+func NewOTLPExporter(endpoint, serviceName string) *OTLPExporter {
+	return &OTLPExporter{
+		Endpoint:    strings.TrimSuffix(endpoint, "/"),
+		ServiceName: serviceName,
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		Headers:     map[string]string{},
+	}
+}
+
+// Export sends the given spans to the collector.
+// Returns an error if the collector doesn't respond with a 2xx status.
+func (e *OTLPExporter) Export(ctx context.Context, spans []SpanData) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(e.makePayload(spans))
+	if err != nil {
+		return errors.New(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.Endpoint+"/v1/traces", bytes.NewReader(body))
+	if err != nil {
+		return errors.New(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.New(err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.New(fmt.Errorf("tracing: OTLP collector responded with status %d", resp.StatusCode))
+	}
+	return nil
+}
+
+// Shutdown closes the idle connections of the HTTP client.
+func (e *OTLPExporter) Shutdown(_ context.Context) error {
+	if e.Client != nil {
+		e.Client.CloseIdleConnections()
+	}
+	return nil
+}
+
+type otlpPayload struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+	Kind              SpanKind       `json:"kind"`
+}
+
+type otlpStatus struct {
+	Message string     `json:"message,omitempty"`
+	Code    StatusCode `json:"code"`
+}
+
+type otlpKeyValue struct {
+	Value map[string]any `json:"value"`
+	Key   string         `json:"key"`
+}
+
+func (e *OTLPExporter) makePayload(spans []SpanData) otlpPayload {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		span := otlpSpan{
+			TraceID:           s.SpanContext.TraceID.String(),
+			SpanID:            s.SpanContext.SpanID.String(),
+			Name:              s.Name,
+			Kind:              s.Kind,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Attributes:        makeOTLPAttributes(s.Attributes),
+			Status:            otlpStatus{Code: s.Status, Message: s.StatusText},
+		}
+		if s.ParentSpanID.IsValid() {
+			span.ParentSpanID = s.ParentSpanID.String()
+		}
+		otlpSpans = append(otlpSpans, span)
+	}
+
+	return otlpPayload{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: makeOTLPAttributes([]Attribute{Attr("service.name", e.ServiceName)}),
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{Name: "goyave.dev/goyave/v5/tracing"},
+						Spans: otlpSpans,
+					},
+				},
+			},
+		},
+	}
+}
+
+func makeOTLPAttributes(attributes []Attribute) []otlpKeyValue {
+	result := make([]otlpKeyValue, 0, len(attributes))
+	for _, a := range attributes {
+		var value map[string]any
+		switch v := a.Value.(type) {
+		case string:
+			value = map[string]any{"stringValue": v}
+		case bool:
+			value = map[string]any{"boolValue": v}
+		case int:
+			value = map[string]any{"intValue": strconv.Itoa(v)}
+		case int64:
+			value = map[string]any{"intValue": strconv.FormatInt(v, 10)}
+		case float64:
+			value = map[string]any{"doubleValue": v}
+		default:
+			value = map[string]any{"stringValue": fmt.Sprintf("%v", v)}
+		}
+		result = append(result, otlpKeyValue{Key: a.Key, Value: value})
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOTLPExporter(t *testing.T) {
+	var received map[string]any
+	var receivedHeaders http.Header
+	var path string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		receivedHeaders = r.Header
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(collector.Close)
+
+	exporter := NewOTLPExporter(collector.URL+"/", "test-service")
+	exporter.Headers["Authorization"] = "Bearer token"
+
+	start := time.Unix(1700000000, 0)
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	require.NoError(t, err)
+	spans := []SpanData{
+		{
+			Name:         "GET /users",
+			Kind:         SpanKindServer,
+			Start:        start,
+			End:          start.Add(time.Second),
+			SpanContext:  sc,
+			ParentSpanID: SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+			Status:       StatusError,
+			StatusText:   "Internal Server Error",
+			Attributes: []Attribute{
+				Attr("string", "value"),
+				Attr("bool", true),
+				Attr("int", 500),
+				Attr("int64", int64(3)),
+				Attr("float", 1.5),
+				Attr("other", []string{"a"}),
+			},
+		},
+	}
+
+	require.NoError(t, exporter.Export(context.Background(), spans))
+	require.NoError(t, exporter.Export(context.Background(), nil)) // No request
+	require.NoError(t, exporter.Shutdown(context.Background()))
+
+	assert.Equal(t, "/v1/traces", path)
+	assert.Equal(t, "application/json", receivedHeaders.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", receivedHeaders.Get("Authorization"))
+
+	expected := map[string]any{
+		"resourceSpans": []any{
+			map[string]any{
+				"resource": map[string]any{
+					"attributes": []any{
+						map[string]any{"key": "service.name", "value": map[string]any{"stringValue": "test-service"}},
+					},
+				},
+				"scopeSpans": []any{
+					map[string]any{
+						"scope": map[string]any{"name": "goyave.dev/goyave/v5/tracing"},
+						"spans": []any{
+							map[string]any{
+								"traceId":           "4bf92f3577b34da6a3ce929d0e0e4736",
+								"spanId":            "00f067aa0ba902b7",
+								"parentSpanId":      "0102030405060708",
+								"name":              "GET /users",
+								"kind":              2.0,
+								"startTimeUnixNano": "1700000000000000000",
+								"endTimeUnixNano":   "1700000001000000000",
+								"status":            map[string]any{"code": 2.0, "message": "Internal Server Error"},
+								"attributes": []any{
+									map[string]any{"key": "string", "value": map[string]any{"stringValue": "value"}},
+									map[string]any{"key": "bool", "value": map[string]any{"boolValue": true}},
+									map[string]any{"key": "int", "value": map[string]any{"intValue": "500"}},
+									map[string]any{"key": "int64", "value": map[string]any{"intValue": "3"}},
+									map[string]any{"key": "float", "value": map[string]any{"doubleValue": 1.5}},
+									map[string]any{"key": "other", "value": map[string]any{"stringValue": "[a]"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, received)
+
+	t.Run("error_status", func(t *testing.T) {
+		collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(collector.Close)
+
+		exporter := NewOTLPExporter(collector.URL, "test-service")
+		err := exporter.Export(context.Background(), spans)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "503")
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		exporter := NewOTLPExporter("http://127.0.0.1:0", "test-service")
+		assert.Error(t, exporter.Export(context.Background(), spans))
+	})
+}
@@ -0,0 +1,321 @@
+package tracing
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// SpanData a read-only snapshot of a finished span, passed to exporters.
+type SpanData struct {
+	Start        time.Time
+	End          time.Time
+	Name         string
+	StatusText   string
+	Attributes   []Attribute
+	SpanContext  SpanContext
+	ParentSpanID SpanID
+	Kind         SpanKind
+	Status       StatusCode
+}
+
+// Attribute returns the value of the attribute identified by the given key
+// and true if found.
+func (d SpanData) Attribute(key string) (any, bool) {
+	for _, a := range d.Attributes {
+		if a.Key == key {
+			return a.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Exporter sends finished spans to a tracing backend.
+type Exporter interface {
+	// Export the given batch of spans.
+	Export(ctx context.Context, spans []SpanData) error
+
+	// Shutdown flushes and releases the resources held by the exporter.
+	Shutdown(ctx context.Context) error
+}
+
+// DefaultQueueSize the maximum number of finished spans a `Provider` holds
+// while waiting for them to be exported.
+const DefaultQueueSize = 2048
+
+// Provider the default `Tracer` implementation. Finished and sampled spans are queued
+// and sent to the exporter in batches by a background goroutine, so ending a span never
+// waits for the tracing backend. If the queue is full (for example because the backend is
+// slow or unreachable), new spans are dropped. This structure is safe for concurrent use.
+//
+// A batch is exported when it reaches the configured batch size, or when
+// `Flush()` or `Shutdown()` are called. It is advised to call `Shutdown()` in a
+// server shutdown hook so no span is lost and the background goroutine is stopped.
+type Provider struct {
+	exporter Exporter
+
+	// OnError is called when the exporter returns an error. If `nil`, errors are ignored.
+	// It is called from the background export goroutine.
+	OnError func(error)
+
+	// Sampler decides if a new root span (with no parent) should be sampled. If `nil`,
+	// all root spans are sampled. Child spans always inherit the decision of their parent.
+	Sampler func(name string) bool
+
+	queue     chan SpanData
+	flushes   chan flushRequest
+	done      chan struct{}
+	stopped   chan struct{}
+	dropped   atomic.Uint64
+	closed    atomic.Bool
+	closeOnce sync.Once
+	batchSize int
+}
+
+type flushRequest struct {
+	ctx  context.Context
+	done chan struct{}
+}
+
+var _ Tracer = (*Provider)(nil)
+
+// NewProvider create a new Provider exporting spans using the given exporter
+// and starts its background export goroutine.
+// If the given batch size is lower than 1, spans are exported one by one
+// as soon as they end.
+func NewProvider(exporter Exporter, batchSize int) *Provider {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	p := &Provider{
+		exporter:  exporter,
+		batchSize: batchSize,
+		queue:     make(chan SpanData, DefaultQueueSize),
+		flushes:   make(chan flushRequest),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Start implementation of `Tracer`.
+func (p *Provider) Start(ctx context.Context, name string, opts *StartOptions) (context.Context, Span) {
+	if opts == nil {
+		opts = &StartOptions{}
+	}
+	parent := SpanFromContext(ctx).SpanContext()
+
+	s := &span{
+		provider: p,
+		data: SpanData{
+			Name:       name,
+			Kind:       opts.Kind,
+			Start:      opts.Start,
+			Attributes: append(make([]Attribute, 0, len(opts.Attributes)+4), opts.Attributes...),
+		},
+	}
+	if s.data.Kind == SpanKindUnspecified {
+		s.data.Kind = SpanKindInternal
+	}
+	if s.data.Start.IsZero() {
+		s.data.Start = time.Now()
+	}
+
+	if parent.IsValid() {
+		s.data.SpanContext.TraceID = parent.TraceID
+		s.data.SpanContext.Sampled = parent.Sampled
+		s.data.ParentSpanID = parent.SpanID
+	} else {
+		s.data.SpanContext.TraceID = newTraceID()
+		s.data.SpanContext.Sampled = p.Sampler == nil || p.Sampler(name)
+	}
+	s.data.SpanContext.SpanID = newSpanID()
+
+	return ContextWithSpan(ctx, s), s
+}
+
+// enqueue adds the given span to the export queue without blocking.
+// The span is dropped if the queue is full or if the provider is shut down.
+func (p *Provider) enqueue(data SpanData) {
+	if !data.SpanContext.Sampled {
+		return
+	}
+	if p.closed.Load() {
+		p.dropped.Add(1)
+		return
+	}
+	select {
+	case p.queue <- data:
+	default:
+		p.dropped.Add(1)
+	}
+}
+
+// run the background export loop.
+func (p *Provider) run() {
+	defer close(p.stopped)
+	batch := make([]SpanData, 0, p.batchSize)
+	for {
+		select {
+		case data := <-p.queue:
+			batch = p.add(context.Background(), batch, data)
+		case req := <-p.flushes:
+			batch = p.drain(req.ctx, batch)
+			if len(batch) > 0 {
+				p.export(req.ctx, batch)
+				batch = make([]SpanData, 0, p.batchSize)
+			}
+			close(req.done)
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// add appends the given span to the batch and exports the batch if it is full.
+func (p *Provider) add(ctx context.Context, batch []SpanData, data SpanData) []SpanData {
+	batch = append(batch, data)
+	if len(batch) < p.batchSize {
+		return batch
+	}
+	p.export(ctx, batch)
+	return make([]SpanData, 0, p.batchSize)
+}
+
+// drain moves all the queued spans to the batch, exporting full batches.
+func (p *Provider) drain(ctx context.Context, batch []SpanData) []SpanData {
+	for {
+		select {
+		case data := <-p.queue:
+			batch = p.add(ctx, batch, data)
+		default:
+			return batch
+		}
+	}
+}
+
+func (p *Provider) export(ctx context.Context, batch []SpanData) {
+	if err := p.exporter.Export(ctx, batch); err != nil && p.OnError != nil {
+		p.OnError(errors.New(err))
+	}
+}
+
+// Flush exports all queued spans and waits until they are exported
+// or until the given context is done.
+func (p *Provider) Flush(ctx context.Context) {
+	req := flushRequest{ctx: ctx, done: make(chan struct{})}
+	select {
+	case p.flushes <- req:
+	case <-p.stopped:
+		return
+	case <-ctx.Done():
+		return
+	}
+	select {
+	case <-req.done:
+	case <-ctx.Done():
+	}
+}
+
+// Dropped returns the number of spans that were dropped because the export
+// queue was full or because the provider was shut down.
+func (p *Provider) Dropped() uint64 {
+	return p.dropped.Load()
+}
+
+// Shutdown flushes the queued spans, stops the background export goroutine
+// and shuts the exporter down. Spans ending after `Shutdown()` is called are dropped.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	p.closed.Store(true)
+	p.Flush(ctx)
+	p.closeOnce.Do(func() {
+		close(p.done)
+	})
+	select {
+	case <-p.stopped:
+	case <-ctx.Done():
+	}
+	return errors.New(p.exporter.Shutdown(ctx))
+}
+
+// span the recording `Span` implementation used by `Provider`.
+type span struct {
+	provider *Provider
+	data     SpanData
+	mu       sync.Mutex
+	ended    bool
+}
+
+func (s *span) SpanContext() SpanContext {
+	return s.data.SpanContext
+}
+
+func (s *span) SetAttributes(attributes ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+outer:
+	for _, attr := range attributes {
+		for i, a := range s.data.Attributes {
+			if a.Key == attr.Key {
+				s.data.Attributes[i] = attr
+				continue outer
+			}
+		}
+		s.data.Attributes = append(s.data.Attributes, attr)
+	}
+}
+
+func (s *span) SetStatus(code StatusCode, description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	s.data.Status = code
+	if code == StatusError {
+		s.data.StatusText = description
+	} else {
+		s.data.StatusText = ""
+	}
+}
+
+func (s *span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.SetAttributes(Attr("exception.message", err.Error()))
+	var e *errors.Error
+	if stderrors.As(err, &e) {
+		s.SetAttributes(Attr("exception.stacktrace", e.StackFrames().String()))
+	}
+	s.SetStatus(StatusError, err.Error())
+}
+
+func (s *span) SetName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.data.Name = name
+	}
+}
+
+func (s *span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+	s.provider.enqueue(data)
+}
@@ -0,0 +1,189 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+type errorExporter struct {
+	InMemoryExporter
+}
+
+func (e *errorExporter) Export(_ context.Context, _ []SpanData) error {
+	return fmt.Errorf("export error")
+}
+
+type slowExporter struct {
+	InMemoryExporter
+	release chan struct{}
+}
+
+func (e *slowExporter) Export(ctx context.Context, spans []SpanData) error {
+	<-e.release
+	return e.InMemoryExporter.Export(ctx, spans)
+}
+
+func TestProvider(t *testing.T) {
+	t.Run("parent_child", func(t *testing.T) {
+		exporter := NewInMemoryExporter()
+		provider := NewProvider(exporter, 0)
+
+		ctx, root := provider.Start(context.Background(), "root", nil)
+		_, child := provider.Start(ctx, "child", &StartOptions{Kind: SpanKindClient, Attributes: []Attribute{Attr("a", 1)}})
+		child.SetAttributes(Attr("a", 2), Attr("b", "value"))
+		child.SetName("renamed")
+		child.End()
+		child.End() // Calls after first one no-op
+		root.End()
+		provider.Flush(context.Background())
+
+		spans := exporter.Spans()
+		require.Len(t, spans, 2)
+		assert.Equal(t, "renamed", spans[0].Name)
+		assert.Equal(t, SpanKindClient, spans[0].Kind)
+		assert.Equal(t, []Attribute{Attr("a", 2), Attr("b", "value")}, spans[0].Attributes)
+		assert.Equal(t, root.SpanContext().TraceID, spans[0].SpanContext.TraceID)
+		assert.Equal(t, root.SpanContext().SpanID, spans[0].ParentSpanID)
+		assert.Equal(t, "root", spans[1].Name)
+		assert.Equal(t, SpanKindInternal, spans[1].Kind)
+		assert.False(t, spans[1].ParentSpanID.IsValid())
+		assert.False(t, spans[1].End.Before(spans[1].Start))
+
+		v, ok := spans[0].Attribute("b")
+		assert.True(t, ok)
+		assert.Equal(t, "value", v)
+		_, ok = spans[0].Attribute("c")
+		assert.False(t, ok)
+	})
+
+	t.Run("remote_parent", func(t *testing.T) {
+		exporter := NewInMemoryExporter()
+		provider := NewProvider(exporter, 1)
+		sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		require.NoError(t, err)
+
+		_, span := provider.Start(ContextWithRemoteSpanContext(context.Background(), sc), "span", nil)
+		span.End()
+		provider.Flush(context.Background())
+
+		spans := exporter.Spans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, sc.TraceID, spans[0].SpanContext.TraceID)
+		assert.Equal(t, sc.SpanID, spans[0].ParentSpanID)
+		assert.False(t, spans[0].SpanContext.Remote)
+	})
+
+	t.Run("sampling", func(t *testing.T) {
+		exporter := NewInMemoryExporter()
+		provider := NewProvider(exporter, 1)
+		provider.Sampler = func(name string) bool { return name != "ignored" }
+
+		ctx, span := provider.Start(context.Background(), "ignored", nil)
+		_, child := provider.Start(ctx, "child", nil)
+		assert.False(t, child.SpanContext().Sampled)
+		child.End()
+		span.End()
+		provider.Flush(context.Background())
+		assert.Empty(t, exporter.Spans())
+
+		sc, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+		_, span = provider.Start(ContextWithRemoteSpanContext(context.Background(), sc), "not sampled", nil)
+		span.End()
+		provider.Flush(context.Background())
+		assert.Empty(t, exporter.Spans())
+	})
+
+	t.Run("batch_and_flush", func(t *testing.T) {
+		exporter := NewInMemoryExporter()
+		provider := NewProvider(exporter, 3)
+
+		for i := 0; i < 4; i++ {
+			_, span := provider.Start(context.Background(), "span", nil)
+			span.End()
+		}
+		assert.Eventually(t, func() bool { return len(exporter.Spans()) == 3 }, time.Second, time.Millisecond)
+
+		require.NoError(t, provider.Shutdown(context.Background()))
+		assert.Len(t, exporter.Spans(), 4)
+		assert.True(t, exporter.IsShutdown())
+
+		exporter.Reset()
+		assert.Empty(t, exporter.Spans())
+	})
+
+	t.Run("status_and_errors", func(t *testing.T) {
+		exporter := NewInMemoryExporter()
+		provider := NewProvider(exporter, 1)
+
+		_, span := provider.Start(context.Background(), "span", nil)
+		span.SetStatus(StatusOK, "ignored description")
+		span.RecordError(nil)
+		span.End()
+		span.SetStatus(StatusError, "after end")
+		span.SetAttributes(Attr("after", "end"))
+
+		_, failed := provider.Start(context.Background(), "failed", nil)
+		failed.RecordError(errors.New("test error"))
+		failed.End()
+		provider.Flush(context.Background())
+
+		spans := exporter.Spans()
+		require.Len(t, spans, 2)
+		assert.Equal(t, StatusOK, spans[0].Status)
+		assert.Empty(t, spans[0].StatusText)
+		assert.Empty(t, spans[0].Attributes)
+
+		assert.Equal(t, StatusError, spans[1].Status)
+		assert.Equal(t, "test error", spans[1].StatusText)
+		msg, _ := spans[1].Attribute("exception.message")
+		assert.Equal(t, "test error", msg)
+		_, ok := spans[1].Attribute("exception.stacktrace")
+		assert.True(t, ok)
+	})
+
+	t.Run("export_error", func(t *testing.T) {
+		provider := NewProvider(&errorExporter{}, 1)
+		var exportErr error
+		provider.OnError = func(err error) { exportErr = err }
+		_, span := provider.Start(context.Background(), "span", nil)
+		span.End()
+		provider.Flush(context.Background())
+		require.Error(t, exportErr)
+		assert.Equal(t, "export error", exportErr.Error())
+	})
+	t.Run("end_does_not_block", func(t *testing.T) {
+		exporter := &slowExporter{release: make(chan struct{})}
+		provider := NewProvider(exporter, 1)
+
+		done := make(chan struct{})
+		go func() {
+			for i := 0; i < DefaultQueueSize+10; i++ {
+				_, span := provider.Start(context.Background(), "span", nil)
+				span.End()
+			}
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "ending spans blocked on the exporter")
+		}
+		assert.Positive(t, provider.Dropped())
+
+		close(exporter.release)
+		require.NoError(t, provider.Shutdown(context.Background()))
+		assert.Equal(t, uint64(DefaultQueueSize+10), uint64(len(exporter.Spans()))+provider.Dropped())
+
+		_, span := provider.Start(context.Background(), "after shutdown", nil)
+		dropped := provider.Dropped()
+		span.End()
+		assert.Equal(t, dropped+1, provider.Dropped())
+		provider.Flush(context.Background()) // No-op after shutdown
+	})
+}
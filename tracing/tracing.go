@@ -0,0 +1,239 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TraceID a unique identifier of a trace, as defined by the W3C Trace Context specification.
+type TraceID [16]byte
+
+// SpanID a unique identifier of a span within a trace.
+type SpanID [8]byte
+
+// IsValid returns true if the trace ID is not all zeros.
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// String returns the lowercase hex representation of the trace ID.
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// IsValid returns true if the span ID is not all zeros.
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// String returns the lowercase hex representation of the span ID.
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// SpanContext the immutable part of a span that is propagated to child spans
+// and across process boundaries using the `traceparent` header.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+	Remote  bool
+}
+
+// IsValid returns true if both the trace ID and the span ID are valid.
+func (c SpanContext) IsValid() bool {
+	return c.TraceID.IsValid() && c.SpanID.IsValid()
+}
+
+// Traceparent returns the value of the W3C `traceparent` header representing
+// this span context.
+func (c SpanContext) Traceparent() string {
+	flags := "00"
+	if c.Sampled {
+		flags = "01"
+	}
+	return "00-" + c.TraceID.String() + "-" + c.SpanID.String() + "-" + flags
+}
+
+// TraceparentHeader the name of the W3C header used for trace context propagation.
+const TraceparentHeader = "traceparent"
+
+// ParseTraceparent parses the value of a W3C `traceparent` header.
+// The returned span context is marked as remote.
+//
+// Future versions (other than "00" and not "ff") are accepted as long as
+// the first four fields are well formed, as required by the specification.
+func ParseTraceparent(header string) (SpanContext, error) {
+	header = strings.TrimSpace(header)
+	parts := strings.Split(header, "-")
+	if len(parts) < 4 {
+		return SpanContext{}, fmt.Errorf("tracing: malformed traceparent %q", header)
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || version == "ff" || !isLowerHex(version) {
+		return SpanContext{}, fmt.Errorf("tracing: invalid traceparent version %q", version)
+	}
+	if version == "00" && len(parts) != 4 {
+		return SpanContext{}, fmt.Errorf("tracing: malformed traceparent %q", header)
+	}
+	if len(traceID) != 32 || len(spanID) != 16 || len(flags) != 2 ||
+		!isLowerHex(traceID) || !isLowerHex(spanID) || !isLowerHex(flags) {
+		return SpanContext{}, fmt.Errorf("tracing: malformed traceparent %q", header)
+	}
+
+	sc := SpanContext{Remote: true}
+	_, _ = hex.Decode(sc.TraceID[:], []byte(traceID))
+	_, _ = hex.Decode(sc.SpanID[:], []byte(spanID))
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("tracing: invalid traceparent IDs %q", header)
+	}
+	var f [1]byte
+	_, _ = hex.Decode(f[:], []byte(flags))
+	sc.Sampled = f[0]&0x01 == 0x01
+	return sc, nil
+}
+
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// SpanKind describes the relationship between the span, its parents and its children.
+// Values match the OTLP specification.
+type SpanKind int
+
+// Span kinds.
+const (
+	SpanKindUnspecified SpanKind = iota
+	SpanKindInternal
+	SpanKindServer
+	SpanKindClient
+	SpanKindProducer
+	SpanKindConsumer
+)
+
+// StatusCode the status of a finished span. Values match the OTLP specification.
+type StatusCode int
+
+// Status codes.
+const (
+	StatusUnset StatusCode = iota
+	StatusOK
+	StatusError
+)
+
+// Attribute a key/value pair attached to a span.
+// Values should be of type string, bool, int, int64 or float64.
+type Attribute struct {
+	Value any
+	Key   string
+}
+
+// Attr create a new Attribute.
+func Attr(key string, value any) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span a single operation within a trace.
+type Span interface {
+	// SpanContext returns the span's propagable context.
+	SpanContext() SpanContext
+
+	// SetAttributes set the given attributes on the span, overriding
+	// attributes having the same key.
+	SetAttributes(attributes ...Attribute)
+
+	// SetStatus set the status of the span.
+	SetStatus(code StatusCode, description string)
+
+	// RecordError marks the span as failed and attaches the error message.
+	RecordError(err error)
+
+	// SetName overrides the name given when starting the span.
+	SetName(name string)
+
+	// End completes the span. Calls after the first one have no effect.
+	End()
+}
+
+// StartOptions options used when starting a span.
+type StartOptions struct {
+	// Start the start time of the span. Defaults to `time.Now()`.
+	Start time.Time
+
+	// Attributes initial attributes of the span.
+	Attributes []Attribute
+
+	// Kind of the span. Defaults to `SpanKindInternal`.
+	Kind SpanKind
+}
+
+// Tracer creates spans.
+type Tracer interface {
+	// Start a new span. If the given context contains a span, the new span is
+	// a child of it. Returns a new context containing the created span.
+	Start(ctx context.Context, name string, opts *StartOptions) (context.Context, Span)
+}
+
+type spanKey struct{}
+
+// ContextWithSpan returns a copy of the given context containing the given span.
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// ContextWithRemoteSpanContext returns a copy of the given context containing a non-recording
+// span having the given span context. Spans started from this context will be children of
+// the remote span.
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return ContextWithSpan(ctx, nonRecordingSpan{sc: sc})
+}
+
+// SpanFromContext returns the span stored in the given context.
+// If there is none, returns a non-recording span having an invalid span context.
+// The returned value is never `nil`.
+func SpanFromContext(ctx context.Context) Span {
+	if ctx != nil {
+		if span, ok := ctx.Value(spanKey{}).(Span); ok {
+			return span
+		}
+	}
+	return nonRecordingSpan{}
+}
+
+// nonRecordingSpan a span that doesn't record anything. It is used to propagate
+// span contexts and as a placeholder when there is no active span.
+type nonRecordingSpan struct {
+	sc SpanContext
+}
+
+func (s nonRecordingSpan) SpanContext() SpanContext       { return s.sc }
+func (nonRecordingSpan) SetAttributes(_ ...Attribute)     {}
+func (nonRecordingSpan) SetStatus(_ StatusCode, _ string) {}
+func (nonRecordingSpan) RecordError(_ error)              {}
+func (nonRecordingSpan) SetName(_ string)                 {}
+func (nonRecordingSpan) End()                             {}
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	cases := []struct {
+		header  string
+		want    string
+		sampled bool
+		wantErr bool
+	}{
+		{header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", want: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sampled: true},
+		{header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", want: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", sampled: false},
+		{header: " 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-03 ", want: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sampled: true},
+		{header: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future", want: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sampled: true},
+		{header: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", wantErr: true},
+		{header: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantErr: true},
+		{header: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", wantErr: true},
+		{header: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", wantErr: true},
+		{header: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", wantErr: true},
+		{header: "00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01", wantErr: true},
+		{header: "00-4bf92f3577b34da6a3ce929d0e0e4736", wantErr: true},
+		{header: "", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.header, func(t *testing.T) {
+			sc, err := ParseTraceparent(c.header)
+			if c.wantErr {
+				require.Error(t, err)
+				assert.False(t, sc.IsValid())
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, sc.Remote)
+			assert.Equal(t, c.sampled, sc.Sampled)
+			assert.Equal(t, c.want, sc.Traceparent())
+		})
+	}
+}
+
+func TestIDs(t *testing.T) {
+	assert.False(t, TraceID{}.IsValid())
+	assert.False(t, SpanID{}.IsValid())
+	traceID := newTraceID()
+	spanID := newSpanID()
+	assert.True(t, traceID.IsValid())
+	assert.True(t, spanID.IsValid())
+	assert.Len(t, traceID.String(), 32)
+	assert.Len(t, spanID.String(), 16)
+}
+
+func TestSpanFromContext(t *testing.T) {
+	span := SpanFromContext(context.Background())
+	require.NotNil(t, span)
+	assert.False(t, span.SpanContext().IsValid())
+
+	// Non-recording span methods are no-op
+	span.SetAttributes(Attr("key", "value"))
+	span.SetStatus(StatusError, "error")
+	span.RecordError(assert.AnError)
+	span.SetName("name")
+	span.End()
+
+	sc := SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Sampled: true, Remote: true}
+	ctx := ContextWithRemoteSpanContext(context.Background(), sc)
+	assert.Equal(t, sc, SpanFromContext(ctx).SpanContext())
+
+	//nolint:staticcheck
+	assert.False(t, SpanFromContext(nil).SpanContext().IsValid())
+}
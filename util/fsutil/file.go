@@ -3,6 +3,7 @@ package fsutil
 import (
 	"encoding/json"
 	"io"
+	"io/fs"
 	"mime/multipart"
 	"net/http"
 	"os"
@@ -14,14 +15,19 @@ import (
 	"goyave.dev/goyave/v5/util/errors"
 )
 
-// marshalCache temporarily stores files' `*multipart.FileHeader`. This type
+// marshalCache temporarily stores files' `*multipart.FileHeader` and `fs.FS`. These types
 // cannot be marshaled, making the use of `fsutil.file` inconvenient with DTO conversion.
 // The key should a be unique ID. The key is removed from the map.
 // To avoid infinite growth of this cache, leading to potential memory problems, this map
 // is reset every time its length goes back to 0.
-var marshalCache = map[string]*multipart.FileHeader{}
+var marshalCache = map[string]cachedFile{}
 var cacheMu sync.RWMutex
 
+type cachedFile struct {
+	header *multipart.FileHeader
+	fs     fs.FS
+}
+
 // File represents a file received from client.
 //
 // File implements `json.Marshaler` and `json.Unmarshaler` to be able
@@ -32,14 +38,32 @@ var cacheMu sync.RWMutex
 // retrieved then deleted from the cache. To avoid orphans clogging up the cache, you should
 // never JSON marshal this type outside of `typeutil.Convert()`: if a marshaled File never gets
 // unmarshaled, its UUID would remain in the cache forever.
+//
+// If the file was received by the parse middleware in streaming mode, its content
+// is not held by `Header` but stored in `FS` at `Path`. Use `File.Open()` to read the
+// content regardless of where it is stored.
 type File struct {
-	Header   *multipart.FileHeader
+	Header *multipart.FileHeader
+
+	// FS the file system the file content was written to while the request
+	// was being read. `nil` if the content is held by `Header`.
+	FS fs.FS
+
 	MIMEType string
+
+	// Path the path of the file content in `FS`.
+	Path string
+
+	// Checksum the hex-encoded SHA-256 checksum of the file content, computed
+	// while the request was being read. Empty if `FS` is `nil`.
+	Checksum string
 }
 
 type marshaledFile struct {
 	MIMEType string
 	Header   string
+	Path     string `json:",omitempty"`
+	Checksum string `json:",omitempty"`
 }
 
 // MarshalJSON implementation of `json.Marhsaler`.
@@ -51,12 +75,14 @@ func (file File) MarshalJSON() ([]byte, error) {
 
 	uidStr := headerUID.String()
 	cacheMu.Lock()
-	marshalCache[uidStr] = file.Header
+	marshalCache[uidStr] = cachedFile{header: file.Header, fs: file.FS}
 	cacheMu.Unlock()
 
 	return json.Marshal(marshaledFile{
 		Header:   uidStr,
 		MIMEType: file.MIMEType,
+		Path:     file.Path,
+		Checksum: file.Checksum,
 	})
 }
 
@@ -68,9 +94,11 @@ func (file *File) UnmarshalJSON(data []byte) error {
 	}
 
 	file.MIMEType = v.MIMEType
+	file.Path = v.Path
+	file.Checksum = v.Checksum
 
 	cacheMu.RLock()
-	header, ok := marshalCache[v.Header]
+	cached, ok := marshalCache[v.Header]
 	cacheMu.RUnlock()
 	if !ok {
 		return errors.New("cannot unmarshal fsutil.File: multipart header not found in cache")
@@ -81,14 +109,33 @@ func (file *File) UnmarshalJSON(data []byte) error {
 	if len(marshalCache) == 0 {
 		// Maps never shrink, let's allocate a new empty map to reset the cache capacity
 		// and allow garbage collecting.
-		marshalCache = map[string]*multipart.FileHeader{}
+		marshalCache = map[string]cachedFile{}
 	}
 	cacheMu.Unlock()
 
-	file.Header = header
+	file.Header = cached.header
+	file.FS = cached.fs
 	return nil
 }
 
+// Open opens the file's content for reading. If the file has been written to
+// a file system (see `File.FS`), the content is read from this file system.
+// Otherwise, `Header.Open()` is used.
+func (file *File) Open() (io.ReadCloser, error) {
+	if file.FS != nil {
+		f, err := file.FS.Open(file.Path)
+		if err != nil {
+			return nil, errors.New(err)
+		}
+		return f, nil
+	}
+	f, err := file.Header.Open()
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return f, nil
+}
+
 // Save writes the file's content to a new file in the given file system.
 // Appends a timestamp to the given file name to avoid duplicate file names.
 // The file is not readable anymore once saved as its FileReader has already been
@@ -107,8 +154,8 @@ func (file *File) Save(fs WritableFS, path string, name string) (filename string
 		}
 	}
 
-	var f multipart.File
-	f, err = file.Header.Open()
+	var f io.ReadCloser
+	f, err = file.Open()
 	if err != nil {
 		err = errors.New(err)
 		return
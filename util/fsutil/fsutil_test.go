@@ -390,3 +390,67 @@ func TestEmbedSub(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestFileOpen(t *testing.T) {
+	t.Run("header", func(t *testing.T) {
+		file := createTestFiles("resources/img/logo/goyave_16.png")[0]
+		f, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		assert.Len(t, content, 630)
+	})
+
+	t.Run("fs", func(t *testing.T) {
+		file := File{
+			Header: &multipart.FileHeader{Filename: "goyave_16.png", Size: 630},
+			FS:     osfs.New(toAbsolutePath("resources/img/logo")),
+			Path:   "goyave_16.png",
+		}
+		f, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		assert.Len(t, content, 630)
+
+		file.Path = "notafile"
+		_, err = file.Open()
+		require.Error(t, err)
+	})
+
+	t.Run("save_from_fs", func(t *testing.T) {
+		dir := t.TempDir()
+		file := File{
+			Header: &multipart.FileHeader{Filename: "goyave_16.png", Size: 630},
+			FS:     osfs.New(toAbsolutePath("resources/img/logo")),
+			Path:   "goyave_16.png",
+		}
+		name, err := file.Save(osfs.New(dir), ".", "saved.png")
+		require.NoError(t, err)
+		mime, size, err := GetMIMEType(osfs.New(dir), name)
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", mime)
+		assert.Equal(t, int64(630), size)
+	})
+
+	t.Run("marshal", func(t *testing.T) {
+		type testDTO struct {
+			Files []File `json:"files"`
+		}
+		files := []File{
+			{
+				Header:   &multipart.FileHeader{Filename: "goyave_16.png", Size: 630},
+				FS:       osfs.New(toAbsolutePath("resources/img/logo")),
+				Path:     "goyave_16.png",
+				MIMEType: "image/png",
+				Checksum: "abc",
+			},
+		}
+		dto, err := typeutil.Convert[*testDTO](map[string]any{"files": files})
+		require.NoError(t, err)
+		assert.Equal(t, files, dto.Files)
+		assert.Same(t, files[0].Header, dto.Files[0].Header)
+	})
+}
@@ -1,5 +1,7 @@
 package validation
 
+import "encoding/json"
+
 // BoolValidator the field under validation must be a bool or one of the following values:
 //   - "1" / "0"
 //   - "true" / "false"
@@ -30,6 +32,13 @@ func (v *BoolValidator) Validate(ctx *Context) bool {
 		f, _, _ := numberAsFloat64(val)
 		ctx.Value = f != 0
 		return true
+	case json.Number:
+		f, err := val.Float64()
+		if err != nil {
+			return false
+		}
+		ctx.Value = f != 0
+		return true
 	}
 	return false
 }
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -64,10 +65,14 @@ func TestBoolValidator(t *testing.T) {
 		{value: uint64(0), want: true, wantValue: false},
 		{value: float32(0), want: true, wantValue: false},
 		{value: float64(0), want: true, wantValue: false},
+		{value: json.Number("1"), want: true, wantValue: true},
+		{value: json.Number("0.5"), want: true, wantValue: true},
+		{value: json.Number("0"), want: true, wantValue: false},
 
 		// Invalid types
 		{value: []string{"string"}, want: false},
 		{value: map[string]any{"a": 1}, want: false},
+		{value: json.Number("not a number"), want: false},
 		{value: nil, want: false},
 	}
 
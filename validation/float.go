@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"strconv"
@@ -46,6 +47,15 @@ func numberAsFloat64(n any) (float64, bool, error) {
 			return float64(val), false, fmt.Errorf("uint64, value %d doesn't fit in float64", val)
 		}
 		return float64(val), true, nil
+	case json.Number:
+		f, err := val.Float64()
+		if err != nil {
+			if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+				return f, false, fmt.Errorf("json.Number value %s doesn't fit in float64", val)
+			}
+			return 0, false, nil
+		}
+		return f, true, nil
 	}
 	return 0, false, nil
 }
@@ -68,6 +78,8 @@ func (v *floatValidator[T]) Validate(ctx *Context) bool {
 		return v.checkFloatRange(ctx, val)
 	case string:
 		return v.parseString(ctx, val)
+	case json.Number:
+		return v.parseString(ctx, string(val))
 	case int:
 		return v.checkIntRange(ctx, val)
 	case int8:
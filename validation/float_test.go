@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"strconv"
@@ -103,6 +104,8 @@ func TestFloat64Validator(t *testing.T) {
 		{value: uint(2), want: true, wantValue: float64(2.0)},
 		{value: 'a', want: true, wantValue: float64(97.0)},
 		{value: "2.5", want: true, wantValue: float64(2.5)},
+		{value: json.Number("2.5"), want: true, wantValue: float64(2.5)},
+		{value: json.Number("1e3"), want: true, wantValue: float64(1000)},
 		{value: strconv.FormatFloat(math.MaxFloat64, 'f', 24, 64), want: true, wantValue: float64(math.MaxFloat64)},
 		{value: strconv.FormatFloat(-math.MaxFloat64, 'f', 24, 64), want: true, wantValue: float64(-math.MaxFloat64)},
 		{value: uint8(math.MaxUint8), want: true, wantValue: float64(math.MaxUint8)},
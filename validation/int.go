@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"strconv"
@@ -23,6 +24,12 @@ func (v *intValidator[T]) Validate(ctx *Context) bool {
 		return v.checkFloat64Range(ctx, val)
 	case string:
 		return v.parseString(ctx, val)
+	case json.Number:
+		if v.parseString(ctx, string(val)) {
+			return true
+		}
+		floatVal, err := val.Float64()
+		return err == nil && v.checkFloat64Range(ctx, floatVal)
 	case int:
 		return v.checkIntRange(ctx, val)
 	case int8:
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"testing"
@@ -346,6 +347,9 @@ func TestInt64Validator(t *testing.T) {
 		{value: 2.0, want: true, wantValue: int64(2)},
 		{value: float32(2.0), want: true, wantValue: int64(2)},
 		{value: "2", want: true, wantValue: int64(2)},
+		{value: json.Number("9007199254740993"), want: true, wantValue: int64(9007199254740993)},
+		{value: json.Number("2.0"), want: true, wantValue: int64(2)},
+		{value: json.Number("2.5"), want: false},
 		{value: 2.5, want: false},
 		{value: float32(2.5), want: false},
 		{value: 'a', want: true, wantValue: int64(97)},
@@ -714,6 +718,8 @@ func TestUint64Validator(t *testing.T) {
 		{value: 2.0, want: true, wantValue: uint64(2)},
 		{value: float32(2.0), want: true, wantValue: uint64(2)},
 		{value: "2", want: true, wantValue: uint64(2)},
+		{value: json.Number("18446744073709551615"), want: true, wantValue: uint64(math.MaxUint64)},
+		{value: json.Number("-1"), want: false},
 		{value: 2.5, want: false},
 		{value: float32(2.5), want: false},
 		{value: 'a', want: true, wantValue: uint64(97)},
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"mime/multipart"
@@ -36,6 +37,9 @@ func TestMinalidator(t *testing.T) {
 		{value: int64(math.MaxInt64), want: false, min: math.MinInt64}, // Don't pass because above max int value that can accurately fit in float64
 		{value: int64(math.MinInt64), want: false, min: math.MinInt64}, // Don't pass because below min int value that can accurately fit in float64
 		{value: 'a', want: false, min: 100},
+		{value: json.Number("25"), want: true, min: 3},
+		{value: json.Number("2.5"), want: false, min: 3},
+		{value: json.Number("1e400"), want: false, min: 3},
 		{value: "abc", want: false, min: 4},
 		{value: []string{"a", "b"}, want: false, min: 3},
 		{value: map[string]any{"a": 1, "b": 2}, want: false, min: 3},
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"encoding/json"
 	"reflect"
 	"strings"
 	"time"
@@ -550,7 +551,7 @@ func appendPath(parentPath, childPath *walk.Path, index int) *walk.Path {
 // GetFieldType returns the non-technical type of the given "value" interface.
 // This is used by validation rules to know if the input data is a candidate
 // for validation or not and is especially useful for type-dependent rules.
-//   - "numeric" (`lang.FieldTypeNumeric`) if the value is an int, uint or a float
+//   - "numeric" (`lang.FieldTypeNumeric`) if the value is an int, uint, a float or a `json.Number`
 //   - "string" (`lang.FieldTypeString`) if the value is a string
 //   - "array" (`lang.FieldTypeArray`) if the value is a slice
 //   - "file" (`lang.FieldTypeFile`) if the value is a slice of "fsutil.File"
@@ -560,9 +561,13 @@ func GetFieldType(value any) string {
 	return getFieldType(reflect.ValueOf(value))
 }
 
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
 func getFieldType(value reflect.Value) string {
 	kind := value.Kind().String()
 	switch {
+	case value.IsValid() && value.Type() == jsonNumberType:
+		return FieldTypeNumeric
 	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint") && kind != "uintptr", strings.HasPrefix(kind, "float"):
 		return FieldTypeNumeric
 	case kind == "string":
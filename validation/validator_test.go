@@ -2,6 +2,7 @@ package validation
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -129,6 +130,7 @@ func TestGetFieldType(t *testing.T) {
 		{desc: "numeric_uint64", value: uint64(1), want: FieldTypeNumeric},
 		{desc: "numeric_float32", value: float32(1), want: FieldTypeNumeric},
 		{desc: "numeric_float64", value: float64(1), want: FieldTypeNumeric},
+		{desc: "numeric_json_number", value: json.Number("1.5"), want: FieldTypeNumeric},
 		{desc: "string", value: "", want: FieldTypeString},
 		{desc: "bool", value: true, want: FieldTypeBool},
 		{desc: "slice_int", value: []int{}, want: FieldTypeArray},
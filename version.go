@@ -0,0 +1,261 @@
+package goyave
+
+import (
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// VersionParameter the name of the route parameter containing the requested
+// API version when using `VersionOptions.PathPrefix`.
+const VersionParameter = "apiVersion"
+
+// ExtraAPIVersion the key used in `Context.Extra` to store the API version
+// resolved for the request (`int`). Only set for routes registered in a versioned router.
+// It is not set if the request doesn't specify a version and the matched route
+// is available in all versions.
+type ExtraAPIVersion struct{}
+
+// VersionOptions defines how the requested API version is resolved in a versioned router.
+// Versions are positive integers. The sources are checked in the following order:
+// path prefix, header, `Accept` media type parameter. The first source containing a
+// valid version is used.
+type VersionOptions struct {
+	// Header the name of a custom header containing the requested version (e.g. "API-Version").
+	// The value can optionally be prefixed with "v" (e.g. "v2"). Ignored if empty.
+	Header string
+
+	// AcceptParameter the name of the `Accept` media type parameter containing the requested
+	// version (e.g. "version" for "application/json; version=2"). Ignored if empty.
+	AcceptParameter string
+
+	// Default the version used if the request doesn't specify one.
+	// If zero, the latest version available for the matched route is used,
+	// even if the route was removed in a later version.
+	Default int
+
+	// PathPrefix if true, the versioned router has the "/v{apiVersion}" prefix
+	// (e.g. "/v2/users"). The version in the path takes precedence over other sources.
+	PathPrefix bool
+}
+
+// VersionRange the range of API versions a route is available in.
+// A zero `To` means the route is available in all versions starting from `From`.
+type VersionRange struct {
+	From int `json:"from"`
+	To   int `json:"to,omitempty"`
+}
+
+// Contains returns true if the given version is in this range.
+func (v *VersionRange) Contains(version int) bool {
+	if v == nil {
+		return true
+	}
+	return version >= v.From && (v.To == 0 || version <= v.To)
+}
+
+func (v *VersionRange) from() int {
+	if v == nil {
+		return 0
+	}
+	return v.From
+}
+
+func (v *VersionRange) to() int {
+	if v == nil || v.To == 0 {
+		return math.MaxInt
+	}
+	return v.To
+}
+
+// latest returns the highest version of this range. If the range has no end,
+// its starting version is returned. Returns 0 if `v` is `nil`.
+func (v *VersionRange) latest() int {
+	if v == nil {
+		return 0
+	}
+	if v.To != 0 {
+		return v.To
+	}
+	return v.From
+}
+
+// isPreferredTo returns true if a route available in this range should be used
+// instead of a route available in the other range. The range with the highest starting
+// version is preferred. If latest is true, the range ending last is preferred first.
+func (v *VersionRange) isPreferredTo(other *VersionRange, latest bool) bool {
+	if latest && v.to() != other.to() {
+		return v.to() > other.to()
+	}
+	return v.from() > other.from()
+}
+
+// Versioned create a new sub-router in which the same route can be registered multiple
+// times for different ranges of API versions using `Route.Versions()`. The requested version
+// is resolved according to the given options.
+//
+// For a requested version, the route with the highest starting version that is lower or equal to
+// the requested version is used. This means that if a route doesn't change in a new version,
+// the handler of the previous version is used as fallback. Routes not using `Route.Versions()`
+// are available in all versions.
+//
+//	api := router.Versioned(&goyave.VersionOptions{Header: "API-Version", Default: 1})
+//	api.Get("/users", ctrl.IndexV1).Versions(1, 0)
+//	api.Get("/users", ctrl.IndexV2).Versions(2, 0)
+//	api.Get("/legacy", ctrl.Legacy).Versions(1, 1) // Removed in version 2
+//
+// Subrouters of a versioned router are versioned too.
+//
+// The resolved version is stored in the request's extra with the key `ExtraAPIVersion`.
+// If the version is resolved from a header or from the `Accept` header, these headers are
+// added to the `Vary` response header.
+func (r *Router) Versioned(options *VersionOptions) *Router {
+	prefix := ""
+	if options.PathPrefix {
+		prefix = "/v{" + VersionParameter + ":[0-9]+}"
+	}
+	router := r.Subrouter(prefix)
+	opts := *options
+	router.versioning = &opts
+	return router
+}
+
+// Versions set the range of API versions this route is available in.
+// A zero `to` means the route is available in all versions starting from `from`.
+// Panics if the route is not in a versioned router.
+func (r *Route) Versions(from, to int) *Route {
+	if r.parent.versioning == nil {
+		panic(errors.NewSkip("route is not in a versioned router", 3))
+	}
+	r.versions = &VersionRange{From: from, To: to}
+	return r
+}
+
+// GetVersions returns the range of API versions this route is available in,
+// or `nil` if the route is available in all versions.
+func (r *Route) GetVersions() *VersionRange {
+	if r.versions == nil {
+		return nil
+	}
+	cpy := *r.versions
+	return &cpy
+}
+
+// matchVersion finds the route best matching the requested version.
+// If no version is requested, the route with the highest version available is used.
+func (r *Router) matchVersion(method string, match *routeMatch) bool {
+	version := resolveVersion(r.versioning, match)
+	latest := version == math.MaxInt
+	var best *Route
+	methodNotAllowed := false
+	for _, route := range r.routes {
+		if (!latest && !route.versions.Contains(version)) || !route.regex.MatchString(match.currentPath) {
+			continue
+		}
+		if !route.checkMethod(method) {
+			methodNotAllowed = true
+			continue
+		}
+		if best == nil || route.versions.isPreferredTo(best.versions, latest) {
+			best = route
+		}
+	}
+
+	if best == nil {
+		if methodNotAllowed {
+			match.err = errMatchMethodNotAllowed
+		} else if match.err == nil {
+			match.err = errMatchNotFound
+		}
+		return false
+	}
+
+	if latest {
+		version = best.versions.latest()
+	}
+	match.version = version
+	match.versioning = r.versioning
+	return best.match(method, match)
+}
+
+// resolveVersion returns the version requested or `math.MaxInt` if the latest
+// version should be used.
+func resolveVersion(options *VersionOptions, match *routeMatch) int {
+	if options.PathPrefix {
+		if version, ok := parseVersion(match.parameters[VersionParameter]); ok {
+			return version
+		}
+	}
+	if options.Header != "" {
+		if version, ok := parseVersion(match.header.Get(options.Header)); ok {
+			return version
+		}
+	}
+	if options.AcceptParameter != "" {
+		for _, accept := range match.header.Values("Accept") {
+			for _, mediaRange := range strings.Split(accept, ",") {
+				_, params, err := mime.ParseMediaType(mediaRange)
+				if err != nil {
+					continue
+				}
+				if version, ok := parseVersion(params[options.AcceptParameter]); ok {
+					return version
+				}
+			}
+		}
+	}
+	if options.Default > 0 {
+		return options.Default
+	}
+	return math.MaxInt
+}
+
+func parseVersion(str string) (int, bool) {
+	str = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(str)), "v")
+	version, err := strconv.Atoi(str)
+	return version, err == nil && version > 0
+}
+
+func writeVersionHeaders(header http.Header, options *VersionOptions) {
+	if options.Header != "" {
+		header.Add("Vary", options.Header)
+	}
+	if options.AcceptParameter != "" {
+		header.Add("Vary", "Accept")
+	}
+}
+
+// Deprecation the value of the `MetaDeprecation` route meta. Deprecated routes
+// have the `Deprecation` (RFC 9745) and `Sunset` (RFC 8594) headers added to their responses.
+type Deprecation struct {
+	// Date the date at which the route was deprecated. If zero, the
+	// `Deprecation` header is set to "true".
+	Date time.Time
+
+	// Sunset the date at which the route will become unavailable. The `Sunset`
+	// header is not set if zero.
+	Sunset time.Time
+
+	// Link an optional URL to a documentation about the deprecation, added
+	// to the `Link` header with the "deprecation" relation type.
+	Link string
+}
+
+func (d *Deprecation) writeHeaders(header http.Header) {
+	if d.Date.IsZero() {
+		header.Set("Deprecation", "true")
+	} else {
+		header.Set("Deprecation", "@"+strconv.FormatInt(d.Date.Unix(), 10))
+	}
+	if !d.Sunset.IsZero() {
+		header.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Link != "" {
+		header.Add("Link", "<"+d.Link+">; rel=\"deprecation\"")
+	}
+}
@@ -0,0 +1,182 @@
+package goyave
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func versionTestHandler(name string) Handler {
+	return func(response *Response, request *Request) {
+		response.String(http.StatusOK, fmt.Sprintf("%s %v", name, request.Extra[ExtraAPIVersion{}]))
+	}
+}
+
+func serveVersionTest(t *testing.T, router *Router, method, path string, header http.Header) (*http.Response, string) {
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	router.ServeHTTP(recorder, req)
+	res := recorder.Result()
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, res.Body.Close())
+	require.NoError(t, err)
+	return res, string(body)
+}
+
+func TestVersionedRouter(t *testing.T) {
+	t.Run("header_and_accept", func(t *testing.T) {
+		router := prepareRouterTest()
+		api := router.Versioned(&VersionOptions{Header: "API-Version", AcceptParameter: "version"})
+		api.Get("/users", versionTestHandler("users v1")).Versions(1, 0)
+		api.Get("/users", versionTestHandler("users v3")).Versions(3, 0)
+		api.Post("/users", versionTestHandler("create users v1")).Versions(1, 0)
+		api.Get("/legacy", versionTestHandler("legacy")).Versions(1, 1)
+		api.Get("/always", versionTestHandler("always"))
+
+		cases := []struct {
+			header         http.Header
+			method         string
+			path           string
+			expectedBody   string
+			expectedStatus int
+		}{
+			{method: http.MethodGet, path: "/users", header: http.Header{"Api-Version": {"1"}}, expectedStatus: http.StatusOK, expectedBody: "users v1 1"},
+			{method: http.MethodGet, path: "/users", header: http.Header{"Api-Version": {"2"}}, expectedStatus: http.StatusOK, expectedBody: "users v1 2"},
+			{method: http.MethodGet, path: "/users", header: http.Header{"Api-Version": {"v3"}}, expectedStatus: http.StatusOK, expectedBody: "users v3 3"},
+			{method: http.MethodGet, path: "/users", header: http.Header{"Api-Version": {"4"}}, expectedStatus: http.StatusOK, expectedBody: "users v3 4"},
+			{method: http.MethodGet, path: "/users", header: http.Header{"Api-Version": {"invalid"}}, expectedStatus: http.StatusOK, expectedBody: "users v3 3"},
+			{method: http.MethodGet, path: "/users", expectedStatus: http.StatusOK, expectedBody: "users v3 3"},
+			{method: http.MethodGet, path: "/users", header: http.Header{"Accept": {"text/html, application/json; version=2"}}, expectedStatus: http.StatusOK, expectedBody: "users v1 2"},
+			{method: http.MethodGet, path: "/users", header: http.Header{"Accept": {"application/json; version=1"}, "Api-Version": {"3"}}, expectedStatus: http.StatusOK, expectedBody: "users v3 3"},
+			{method: http.MethodPost, path: "/users", header: http.Header{"Api-Version": {"3"}}, expectedStatus: http.StatusOK, expectedBody: "create users v1 3"},
+			{method: http.MethodDelete, path: "/users", header: http.Header{"Api-Version": {"3"}}, expectedStatus: http.StatusMethodNotAllowed, expectedBody: "{\"error\":\"Method Not Allowed\"}\n"},
+			{method: http.MethodGet, path: "/legacy", header: http.Header{"Api-Version": {"1"}}, expectedStatus: http.StatusOK, expectedBody: "legacy 1"},
+			{method: http.MethodGet, path: "/legacy", header: http.Header{"Api-Version": {"2"}}, expectedStatus: http.StatusNotFound, expectedBody: "{\"error\":\"Not Found\"}\n"},
+			{method: http.MethodGet, path: "/legacy", expectedStatus: http.StatusOK, expectedBody: "legacy 1"},
+			{method: http.MethodGet, path: "/always", header: http.Header{"Api-Version": {"7"}}, expectedStatus: http.StatusOK, expectedBody: "always 7"},
+			{method: http.MethodGet, path: "/always", expectedStatus: http.StatusOK, expectedBody: "always <nil>"},
+			{method: http.MethodGet, path: "/unknown", expectedStatus: http.StatusNotFound, expectedBody: "{\"error\":\"Not Found\"}\n"},
+		}
+
+		for _, c := range cases {
+			t.Run(fmt.Sprintf("%s_%s_%v", c.method, c.path, c.header), func(t *testing.T) {
+				res, body := serveVersionTest(t, router, c.method, c.path, c.header)
+				assert.Equal(t, c.expectedStatus, res.StatusCode)
+				assert.Equal(t, c.expectedBody, body)
+				if c.expectedStatus == http.StatusOK {
+					assert.Equal(t, []string{"API-Version", "Accept"}, res.Header.Values("Vary"))
+				}
+			})
+		}
+	})
+
+	t.Run("path_prefix_and_default", func(t *testing.T) {
+		router := prepareRouterTest()
+		api := router.Versioned(&VersionOptions{PathPrefix: true, Header: "API-Version", Default: 1})
+		api.Get("/users", versionTestHandler("users v1")).Versions(1, 0)
+		api.Get("/users", versionTestHandler("users v2")).Versions(2, 0)
+		admin := api.Subrouter("/admin")
+		admin.Get("/stats", versionTestHandler("stats v2")).Versions(2, 0)
+
+		res, body := serveVersionTest(t, router, http.MethodGet, "/v1/users", http.Header{"Api-Version": {"2"}})
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "users v1 1", body)
+
+		_, body = serveVersionTest(t, router, http.MethodGet, "/v2/users", nil)
+		assert.Equal(t, "users v2 2", body)
+
+		_, body = serveVersionTest(t, router, http.MethodGet, "/v2/admin/stats", nil)
+		assert.Equal(t, "stats v2 2", body)
+
+		res, _ = serveVersionTest(t, router, http.MethodGet, "/v1/admin/stats", nil)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+		res, _ = serveVersionTest(t, router, http.MethodGet, "/users", nil)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+		assert.Equal(t, "/v{apiVersion:[0-9]+}/users", api.GetRoutes()[0].GetFullURI())
+		assert.Equal(t, "/v2/users", api.GetRoutes()[1].BuildURI("2"))
+	})
+
+	t.Run("latest_ended_range", func(t *testing.T) {
+		router := prepareRouterTest()
+		api := router.Versioned(&VersionOptions{Header: "API-Version"})
+		api.Get("/users", versionTestHandler("users v1")).Versions(1, 2)
+		api.Get("/users", versionTestHandler("users v3")).Versions(3, 4)
+		api.Get("/reports", versionTestHandler("reports v2")).Versions(2, 0)
+		api.Get("/reports", versionTestHandler("reports v1")).Versions(1, 3)
+
+		_, body := serveVersionTest(t, router, http.MethodGet, "/users", nil)
+		assert.Equal(t, "users v3 4", body)
+		_, body = serveVersionTest(t, router, http.MethodGet, "/reports", nil)
+		assert.Equal(t, "reports v2 2", body)
+
+		router.Get("/unversioned", func(response *Response, request *Request) {
+			_, ok := request.Extra[ExtraAPIVersion{}]
+			assert.False(t, ok)
+			response.Status(http.StatusNoContent)
+		})
+		res, _ := serveVersionTest(t, router, http.MethodGet, "/unversioned", nil)
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	})
+
+	t.Run("Versions_not_versioned", func(t *testing.T) {
+		router := prepareRouterTest()
+		assert.Panics(t, func() {
+			router.Get("/users", nil).Versions(1, 0)
+		})
+	})
+
+	t.Run("GetVersions", func(t *testing.T) {
+		router := prepareRouterTest()
+		api := router.Versioned(&VersionOptions{})
+		assert.Nil(t, api.Get("/users", nil).GetVersions())
+		assert.Equal(t, &VersionRange{From: 1, To: 2}, api.Get("/users", nil).Versions(1, 2).GetVersions())
+	})
+}
+
+func TestVersionRange(t *testing.T) {
+	var nilRange *VersionRange
+	assert.True(t, nilRange.Contains(1))
+	assert.True(t, (&VersionRange{From: 2}).Contains(5))
+	assert.False(t, (&VersionRange{From: 2}).Contains(1))
+	assert.True(t, (&VersionRange{From: 2, To: 3}).Contains(3))
+	assert.False(t, (&VersionRange{From: 2, To: 3}).Contains(4))
+}
+
+func TestDeprecation(t *testing.T) {
+	router := prepareRouterTest()
+	date := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+	sunset := time.Date(2025, time.January, 2, 3, 4, 5, 0, time.FixedZone("test", 3600))
+	router.Get("/deprecated", versionTestHandler("deprecated")).SetMeta(MetaDeprecation, &Deprecation{
+		Date:   date,
+		Sunset: sunset,
+		Link:   "https://example.org/deprecation",
+	})
+	subrouter := router.Subrouter("/subrouter")
+	subrouter.SetMeta(MetaDeprecation, &Deprecation{})
+	subrouter.Get("/route", versionTestHandler("inherited"))
+	router.Get("/active", versionTestHandler("active"))
+
+	res, _ := serveVersionTest(t, router, http.MethodGet, "/deprecated", nil)
+	assert.Equal(t, "@1704164645", res.Header.Get("Deprecation"))
+	assert.Equal(t, "Thu, 02 Jan 2025 02:04:05 GMT", res.Header.Get("Sunset"))
+	assert.Equal(t, "<https://example.org/deprecation>; rel=\"deprecation\"", res.Header.Get("Link"))
+
+	res, _ = serveVersionTest(t, router, http.MethodGet, "/subrouter/route", nil)
+	assert.Equal(t, "true", res.Header.Get("Deprecation"))
+	assert.Empty(t, res.Header.Get("Sunset"))
+	assert.Empty(t, res.Header.Get("Link"))
+
+	res, _ = serveVersionTest(t, router, http.MethodGet, "/active", nil)
+	assert.Empty(t, res.Header.Get("Deprecation"))
+}
@@ -0,0 +1,125 @@
+package goyave
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	errorutil "goyave.dev/goyave/v5/util/errors"
+	"goyave.dev/goyave/v5/util/fsutil"
+	"goyave.dev/goyave/v5/view"
+)
+
+// ViewHelper returns a function usable in view templates. It is called for each rendering
+// with the current request, allowing the returned function to depend on it.
+type ViewHelper func(request *Request) any
+
+var viewHelpers = map[string]ViewHelper{}
+
+// RegisterViewHelper register a function available in all view templates under the given name.
+// Helpers must be registered before the views are loaded, that is before creating the server
+// if the views are loaded with the `Options.ViewsFS` option.
+// A helper registered with the name of an existing helper replaces it.
+//
+// The following helpers are available by default:
+//   - `lang "line" "placeholders"...`: the language line in the language of the request
+//   - `route "name" "parameters"...`: the full URL of the named route
+//   - `asset "path"`: the full URL of a static asset (see "server.assetsURL" config entry)
+func RegisterViewHelper(name string, helper ViewHelper) {
+	viewHelpers[name] = helper
+}
+
+// LoadViews load the view templates from the given file system.
+// If the given FS implements `fsutil.WorkingDirFS`, the directory
+// used will be "<working directory>/resources/views".
+//
+// If "app.debug" is enabled, the templates are parsed again before each rendering.
+func (s *Server) LoadViews(fs fsutil.FS) error {
+	directory := "."
+	if wd, ok := fs.(fsutil.WorkingDirFS); ok {
+		workingDir, err := wd.Getwd()
+		if err != nil {
+			return errorutil.New(err)
+		}
+		directory = workingDir + "/resources/views"
+	}
+
+	funcs := template.FuncMap{}
+	for name := range s.viewFuncs(nil) {
+		// Placeholders so the templates can be parsed. They are replaced
+		// by the request-scoped helpers when rendering.
+		funcs[name] = func() string { return "" }
+	}
+	views, err := view.New(fs, directory, &view.Options{
+		Funcs:  funcs,
+		Reload: s.config.GetBool("app.debug"),
+	})
+	if err != nil {
+		return err
+	}
+	s.views = views
+	return nil
+}
+
+// Views returns the view templates loaded with `LoadViews()` or the `ViewsFS` option.
+// Returns `nil` if no views were loaded.
+func (s *Server) Views() *view.Views {
+	return s.views
+}
+
+func (s *Server) viewFuncs(request *Request) template.FuncMap {
+	funcs := template.FuncMap{
+		"lang": func(line string, placeholders ...string) string {
+			language := s.Lang.GetDefault()
+			if request != nil && request.Lang != nil {
+				language = request.Lang
+			}
+			return language.Get(line, placeholders...)
+		},
+		"route": func(name string, parameters ...string) (string, error) {
+			route := s.router.GetRoute(name)
+			if route == nil {
+				return "", fmt.Errorf("route %q not found", name)
+			}
+			return route.BuildURL(parameters...), nil
+		},
+		"asset": func(path string) string {
+			base := s.config.GetString("server.assetsURL")
+			if base == "" {
+				base = s.ProxyBaseURL()
+			}
+			return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/")
+		},
+	}
+	for name, helper := range viewHelpers {
+		if request == nil {
+			funcs[name] = nil
+			continue
+		}
+		funcs[name] = helper(request)
+	}
+	return funcs
+}
+
+// View render the view template identified by the given name with the given data
+// and write it as a response. Also sets the "Content-Type" header automatically.
+//
+// The template is fully rendered before anything is written, so a rendering error
+// doesn't result in a partial response.
+// Panics if the views are not loaded or if the rendering fails.
+func (r *Response) View(responseCode int, name string, data any) {
+	views := r.server.views
+	if views == nil {
+		panic(errorutil.NewSkip("cannot render view: views are not loaded, use the ViewsFS option or server.LoadViews()", 3))
+	}
+	buf := &bytes.Buffer{}
+	if err := views.Render(buf, name, data, r.server.viewFuncs(r.request)); err != nil {
+		panic(errorutil.NewSkip(err, 3))
+	}
+	r.responseWriter.Header().Set("Content-Type", "text/html; charset=utf-8")
+	r.status = responseCode
+	if _, err := r.Write(buf.Bytes()); err != nil {
+		panic(errorutil.NewSkip(err, 3))
+	}
+}
@@ -0,0 +1,164 @@
+package view
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+
+	"goyave.dev/goyave/v5/util/errors"
+)
+
+// Options for the creation of a `Views`.
+type Options struct {
+	// Funcs the functions available in all templates. Functions that depend on the
+	// rendering context (such as the current request) must be declared here so the
+	// templates can be parsed, then replaced when calling `Render()`.
+	Funcs template.FuncMap
+
+	// Extension the file extension of the templates. Files with another extension
+	// are ignored. Defaults to ".html".
+	Extension string
+
+	// Reload if `true`, the templates are parsed again before each rendering so
+	// changes are visible without restarting the application. This should only be
+	// enabled in development.
+	Reload bool
+}
+
+// Views a collection of `html/template` templates loaded from a file system.
+//
+// Each template is identified by its path relative to the views directory, without
+// extension (e.g. "users/index" for "users/index.html"). Templates located in the
+// "layouts" and "partials" directories are shared: they are parsed together with every
+// other template and can be used with the `template` action. A page using a layout
+// defines the blocks expected by the layout and executes it:
+//
+//	{{define "content"}}<h1>{{.Title}}</h1>{{end}}
+//	{{template "layouts/main" .}}
+type Views struct {
+	fs        fs.FS
+	templates map[string]*template.Template
+	options   Options
+	directory string
+	mu        sync.RWMutex
+}
+
+// New load all the templates contained in the given directory of the given file system.
+func New(fsys fs.FS, directory string, options *Options) (*Views, error) {
+	v := &Views{
+		fs:        fsys,
+		directory: path.Clean(directory),
+	}
+	if options != nil {
+		v.options = *options
+	}
+	if v.options.Extension == "" {
+		v.options.Extension = ".html"
+	}
+	if err := v.Load(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Load parse all the templates again, replacing the previously loaded ones.
+func (v *Views) Load() error {
+	shared := map[string]string{}
+	pages := map[string]string{}
+	err := fs.WalkDir(v.fs, v.directory, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(p) != v.options.Extension {
+			return nil
+		}
+		content, err := fs.ReadFile(v.fs, p)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(v.relativePath(p), v.options.Extension)
+		if strings.HasPrefix(name, "layouts/") || strings.HasPrefix(name, "partials/") {
+			shared[name] = string(content)
+		} else {
+			pages[name] = string(content)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.New(err)
+	}
+
+	base := template.New("").Funcs(v.options.Funcs)
+	for name, content := range shared {
+		if _, err := base.New(name).Parse(content); err != nil {
+			return errors.New(err)
+		}
+	}
+
+	templates := make(map[string]*template.Template, len(pages))
+	for name, content := range pages {
+		t, err := base.Clone()
+		if err != nil {
+			return errors.New(err)
+		}
+		if _, err := t.New(name).Parse(content); err != nil {
+			return errors.New(err)
+		}
+		templates[name] = t
+	}
+
+	v.mu.Lock()
+	v.templates = templates
+	v.mu.Unlock()
+	return nil
+}
+
+func (v *Views) relativePath(p string) string {
+	if v.directory == "." {
+		return p
+	}
+	return strings.TrimPrefix(p, v.directory+"/")
+}
+
+// Has returns true if a page template identified by the given name exists.
+func (v *Views) Has(name string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	_, ok := v.templates[strings.TrimSuffix(name, v.options.Extension)]
+	return ok
+}
+
+// Render execute the page template identified by the given name with the given data
+// and write the result to the given writer. The given functions replace the functions
+// of the same name defined in the options for this rendering only.
+//
+// If `Options.Reload` is enabled, all templates are parsed again first.
+func (v *Views) Render(w io.Writer, name string, data any, funcs template.FuncMap) error {
+	if v.options.Reload {
+		if err := v.Load(); err != nil {
+			return err
+		}
+	}
+
+	name = strings.TrimSuffix(name, v.options.Extension)
+	v.mu.RLock()
+	t, ok := v.templates[name]
+	v.mu.RUnlock()
+	if !ok {
+		return errors.New(fmt.Errorf("view %q not found", name))
+	}
+
+	// Templates cannot be cloned once executed, so the original is never executed.
+	t, err := t.Clone()
+	if err != nil {
+		return errors.New(err)
+	}
+	if len(funcs) > 0 {
+		t.Funcs(funcs)
+	}
+	return errors.New(t.ExecuteTemplate(w, name, data))
+}
@@ -0,0 +1,120 @@
+package view
+
+import (
+	"bytes"
+	"html/template"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"views/layouts/main.html":     {Data: []byte(`<html><title>{{block "title" .}}Default{{end}}</title><body>{{template "partials/nav" .}}{{block "content" .}}{{end}}</body></html>`)},
+		"views/partials/nav.html":     {Data: []byte(`<nav>{{.User}}</nav>`)},
+		"views/home.html":             {Data: []byte(`{{define "title"}}Home{{end}}{{define "content"}}<p>{{greet .User}}</p>{{end}}{{template "layouts/main" .}}`)},
+		"views/users/index.html":      {Data: []byte(`{{define "content"}}<ul>{{range .Users}}<li>{{.}}</li>{{end}}</ul>{{end}}{{template "layouts/main" .}}`)},
+		"views/users/standalone.html": {Data: []byte(`<p>{{.User}}</p>`)},
+		"views/readme.md":             {Data: []byte(`ignored`)},
+	}
+}
+
+func TestViews(t *testing.T) {
+	funcs := template.FuncMap{"greet": func(name string) string { return "Hello " + name }}
+
+	t.Run("New", func(t *testing.T) {
+		views, err := New(testFS(), "views", &Options{Funcs: funcs})
+		require.NoError(t, err)
+		assert.Equal(t, ".html", views.options.Extension)
+		assert.True(t, views.Has("home"))
+		assert.True(t, views.Has("home.html"))
+		assert.True(t, views.Has("users/index"))
+		assert.False(t, views.Has("layouts/main"))
+		assert.False(t, views.Has("partials/nav"))
+		assert.False(t, views.Has("readme"))
+	})
+
+	t.Run("Render", func(t *testing.T) {
+		views, err := New(testFS(), "views", &Options{Funcs: funcs})
+		require.NoError(t, err)
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, views.Render(buf, "home", map[string]any{"User": "<b>John</b>"}, nil))
+		assert.Equal(t, `<html><title>Home</title><body><nav>&lt;b&gt;John&lt;/b&gt;</nav><p>Hello &lt;b&gt;John&lt;/b&gt;</p></body></html>`, buf.String())
+
+		buf.Reset()
+		require.NoError(t, views.Render(buf, "users/index.html", map[string]any{"User": "John", "Users": []string{"a", "b"}}, nil))
+		assert.Equal(t, `<html><title>Default</title><body><nav>John</nav><ul><li>a</li><li>b</li></ul></body></html>`, buf.String())
+
+		buf.Reset()
+		require.NoError(t, views.Render(buf, "users/standalone", map[string]any{"User": "John"}, nil))
+		assert.Equal(t, `<p>John</p>`, buf.String())
+	})
+
+	t.Run("Render_funcs", func(t *testing.T) {
+		views, err := New(testFS(), "views", &Options{Funcs: funcs})
+		require.NoError(t, err)
+
+		wg := sync.WaitGroup{}
+		for _, name := range []string{"John", "Jane"} {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				buf := &bytes.Buffer{}
+				override := template.FuncMap{"greet": func(string) string { return "Bye " + name }}
+				assert.NoError(t, views.Render(buf, "home", map[string]any{"User": name}, override))
+				assert.Contains(t, buf.String(), "<p>Bye "+name+"</p>")
+			}(name)
+		}
+		wg.Wait()
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, views.Render(buf, "home", map[string]any{"User": "John"}, nil))
+		assert.Contains(t, buf.String(), "<p>Hello John</p>", "overrides don't affect other renderings")
+	})
+
+	t.Run("Render_errors", func(t *testing.T) {
+		views, err := New(testFS(), "views", &Options{Funcs: funcs})
+		require.NoError(t, err)
+
+		buf := &bytes.Buffer{}
+		assert.ErrorContains(t, views.Render(buf, "missing", nil, nil), `view "missing" not found`)
+		assert.Error(t, views.Render(buf, "home", map[string]any{"User": 1}, nil))
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		fsys := testFS()
+		views, err := New(fsys, "views", &Options{Funcs: funcs, Reload: true})
+		require.NoError(t, err)
+		assert.False(t, views.Has("new"))
+
+		fsys["views/new.html"] = &fstest.MapFile{Data: []byte(`new`)}
+		buf := &bytes.Buffer{}
+		require.NoError(t, views.Render(buf, "new", nil, nil))
+		assert.Equal(t, "new", buf.String())
+
+		fsys["views/new.html"] = &fstest.MapFile{Data: []byte(`{{`)}
+		assert.Error(t, views.Render(buf, "new", nil, nil))
+	})
+
+	t.Run("root_directory", func(t *testing.T) {
+		views, err := New(fstest.MapFS{"index.tmpl": {Data: []byte(`index`)}, "other.html": {Data: []byte(`other`)}}, ".", &Options{Extension: ".tmpl"})
+		require.NoError(t, err)
+		assert.True(t, views.Has("index"))
+		assert.False(t, views.Has("other"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := New(testFS(), "missing", nil)
+		assert.Error(t, err)
+
+		_, err = New(testFS(), "views", nil)
+		assert.ErrorContains(t, err, `function "greet" not defined`)
+
+		_, err = New(fstest.MapFS{"layouts/main.html": {Data: []byte(`{{`)}}, ".", nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,132 @@
+package goyave
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"goyave.dev/goyave/v5/config"
+	"goyave.dev/goyave/v5/util/fsutil/osfs"
+)
+
+func prepareViewTest(t *testing.T, views fstest.MapFS, configure func(cfg *config.Config)) *Server {
+	cfg := config.LoadDefault()
+	if configure != nil {
+		configure(cfg)
+	}
+	server, err := New(Options{Config: cfg, ViewsFS: views})
+	require.NoError(t, err)
+	return server
+}
+
+type viewStatusHandler struct {
+	Component
+}
+
+func (h *viewStatusHandler) Handle(response *Response, _ *Request) {
+	response.View(http.StatusNotFound, "not-found", nil)
+}
+
+func TestView(t *testing.T) {
+	t.Run("ViewsFS_option", func(t *testing.T) {
+		server := prepareViewTest(t, fstest.MapFS{"index.html": {Data: []byte(`index`)}}, nil)
+		require.NotNil(t, server.Views())
+		assert.True(t, server.Views().Has("index"))
+
+		_, err := New(Options{Config: config.LoadDefault(), ViewsFS: fstest.MapFS{"index.html": {Data: []byte(`{{`)}}})
+		assert.Error(t, err)
+
+		server, err = New(Options{Config: config.LoadDefault()})
+		require.NoError(t, err)
+		assert.Nil(t, server.Views())
+	})
+
+	t.Run("LoadViews_working_dir", func(t *testing.T) {
+		server, err := New(Options{Config: config.LoadDefault()})
+		require.NoError(t, err)
+		// The "resources/views" directory doesn't exist in the working directory.
+		assert.Error(t, server.LoadViews(&osfs.FS{}))
+	})
+
+	t.Run("View", func(t *testing.T) {
+		RegisterViewHelper("testUserAgent", func(request *Request) any {
+			return func() string { return request.Header().Get("User-Agent") }
+		})
+		t.Cleanup(func() { delete(viewHelpers, "testUserAgent") })
+
+		server := prepareViewTest(t, fstest.MapFS{
+			"layouts/main.html": {Data: []byte(`<main>{{block "content" .}}{{end}}</main>`)},
+			"index.html":        {Data: []byte(`{{define "content"}}{{lang "disallow-non-validated-fields"}}|{{route "user.show" .ID}}|{{asset "/css/app.css"}}|{{testUserAgent}}{{end}}{{template "layouts/main" .}}`)},
+			"error.html":        {Data: []byte(`{{route "missing"}}`)},
+		}, nil)
+		router := server.Router()
+		router.Get("/users/{userID}", nil).Name("user.show")
+		router.Get("/view", func(response *Response, _ *Request) {
+			response.View(http.StatusCreated, "index", map[string]any{"ID": "12"})
+		})
+		router.Get("/error", func(response *Response, _ *Request) {
+			response.View(http.StatusOK, "error", nil)
+		})
+
+		request := httptest.NewRequest(http.MethodGet, "/view", nil)
+		request.Header.Set("User-Agent", "test-agent")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		res := recorder.Result()
+		body, err := io.ReadAll(res.Body)
+		assert.NoError(t, res.Body.Close())
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, "text/html; charset=utf-8", res.Header.Get("Content-Type"))
+		expected := "<main>" + server.Lang.GetDefault().Get("disallow-non-validated-fields") +
+			"|http://127.0.0.1:8080/users/12|http://127.0.0.1:8080/css/app.css|test-agent</main>"
+		assert.Equal(t, expected, string(body))
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/error", nil))
+		res = recorder.Result()
+		body, err = io.ReadAll(res.Body)
+		assert.NoError(t, res.Body.Close())
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		assert.NotContains(t, string(body), "<main>")
+	})
+
+	t.Run("route_status_handler", func(t *testing.T) {
+		server := prepareViewTest(t, fstest.MapFS{
+			"not-found.html": {Data: []byte(`{{route "home"}}`)},
+		}, nil)
+		router := server.Router()
+		router.Get("/", nil).Name("home")
+		router.StatusHandler(&viewStatusHandler{}, http.StatusNotFound)
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/missing", nil))
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Equal(t, "http://127.0.0.1:8080/", recorder.Body.String())
+	})
+
+	t.Run("assetsURL", func(t *testing.T) {
+		server := prepareViewTest(t, fstest.MapFS{"index.html": {Data: []byte(`{{asset "img/logo.png"}}`)}}, func(cfg *config.Config) {
+			cfg.Set("server.assetsURL", "https://cdn.example.org/assets/")
+		})
+		recorder := httptest.NewRecorder()
+		response := NewResponse(server, NewRequest(httptest.NewRequest(http.MethodGet, "/", nil)), recorder)
+		response.View(http.StatusOK, "index", nil)
+		assert.Equal(t, "https://cdn.example.org/assets/img/logo.png", recorder.Body.String())
+	})
+
+	t.Run("not_loaded", func(t *testing.T) {
+		server, err := New(Options{Config: config.LoadDefault()})
+		require.NoError(t, err)
+		response := NewResponse(server, NewRequest(httptest.NewRequest(http.MethodGet, "/", nil)), httptest.NewRecorder())
+		assert.Panics(t, func() {
+			response.View(http.StatusOK, "index", nil)
+		})
+	})
+}
@@ -2,6 +2,7 @@ package websocket
 
 import (
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	stderrors "errors"
@@ -132,6 +133,8 @@ type Upgrader struct {
 	// Settings the parameters for upgrading the connection. "Error" and "CheckOrigin" are
 	// ignored: use implementations of the interfaces `UpgradeErrorHandler` and `ErrorHandler`.
 	Settings ws.Upgrader
+
+	connections atomic.Int64
 }
 
 // New create a new Upgrader with default settings.
@@ -153,6 +156,13 @@ func (u *Upgrader) RegisterRoutes(router *goyave.Router) {
 	router.Get("", u.Handler())
 }
 
+// ConnectionCount returns the number of websocket connections currently
+// open and served by this Upgrader.
+// This operation is concurrently safe.
+func (u *Upgrader) ConnectionCount() int64 {
+	return u.connections.Load()
+}
+
 func (u *Upgrader) defaultUpgradeErrorHandler(response *goyave.Response, _ *goyave.Request, status int, reason error) {
 	text := http.StatusText(status)
 	if u.Config().GetBool("app.debug") && reason != nil {
@@ -230,9 +240,11 @@ func (u *Upgrader) Handler() goyave.Handler {
 
 func (u *Upgrader) serve(c *ws.Conn, request *goyave.Request, handler func(*Conn, *goyave.Request) error) {
 	conn := newConn(c, time.Duration(u.Config().GetInt("server.websocketCloseTimeout"))*time.Second)
+	u.connections.Add(1)
 	panicked := true
 	var err error
 	defer func() { // Panic recovery
+		defer u.connections.Add(-1)
 		if panicReason := recover(); panicReason != nil || panicked {
 			err = errors.NewSkip(panicReason, 4) // Skipped: runtime.Callers, NewSkip, this func, runtime.panic
 		}
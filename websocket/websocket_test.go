@@ -279,9 +279,10 @@ func TestUpgrade(t *testing.T) {
 	wg.Add(2)
 
 	var routeURL string
+	var upgrader *Upgrader
 	server := testutil.NewTestServerWithOptions(t, prepareTestConfig())
 	server.RegisterRoutes(func(_ *goyave.Server, r *goyave.Router) {
-		upgrader := New(&testController{
+		upgrader = New(&testController{
 			t:  t,
 			wg: &wg,
 			checkOrigin: func(_ *goyave.Request) bool {
@@ -319,6 +320,7 @@ func TestUpgrade(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, ws.TextMessage, messageType)
 		assert.Equal(t, message, data)
+		assert.Equal(t, int64(1), upgrader.ConnectionCount())
 
 		m := ws.FormatCloseMessage(ws.CloseNormalClosure, "Connection closed by client")
 		assert.NoError(t, conn.WriteControl(ws.CloseMessage, m, time.Now().Add(time.Second)))